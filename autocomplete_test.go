@@ -0,0 +1,370 @@
+package autocomplete
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// Helper function to build Algorithm_1 trie and bigram table
+func buildAlg1Trie(corpus []string) *TrieA1 {
+	trie := NewTrieA1()
+	for _, w := range corpus {
+		trie.Insert(w)
+	}
+	trie.BuildBigramTable(corpus)
+	return trie
+}
+
+// Helper to build Algorithm_2 trie
+func buildAlg2Trie(corpus []string) *TriesA2 {
+	trie := NewTriesA2()
+	for _, w := range corpus {
+		trie.Insert(w)
+	}
+	return trie
+}
+
+// Test Case 1: Basic Prefix (No Context)
+func TestBasicPrefixNoContext(t *testing.T) {
+	corpus := []string{"hello", "hell", "helicopter", "hero", "world"}
+	prefix := "he"
+	ideal := []string{"hello", "helicopter", "hell"}
+
+	trieA1 := buildAlg1Trie(corpus)
+	trieA2 := buildAlg2Trie(corpus)
+
+	suggestionsA1 := trieA1.Autocomplete(prefix, 5)
+	suggestionsA2 := Words(trieA2.Autocomplete(prefix, 10))
+
+	// Convert A1 suggestions to []string
+	var wordsA1 []string
+	for _, s := range suggestionsA1 {
+		wordsA1 = append(wordsA1, s.Word)
+	}
+
+	qA1 := MeasureSuggestionQuality(wordsA1, ideal)
+	qA2 := MeasureSuggestionQuality(suggestionsA2, ideal)
+
+	if len(wordsA1) == 0 || len(suggestionsA2) == 0 {
+		t.Errorf("Expected non-empty suggestions for prefix '%s'", prefix)
+	}
+
+	// We only check that we have correct prefix matches and a reasonable quality score.
+	if qA1 <= 0.0 {
+		t.Errorf("Algorithm_1 did not match any ideal suggestions.")
+	}
+	if qA2 <= 0.0 {
+		t.Errorf("Algorithm_2 did not match any ideal suggestions.")
+	}
+}
+
+// Test Case 2: Contextual Ranking
+func TestContextualRanking(t *testing.T) {
+	corpus := []string{"hello", "hello", "hell", "helicopter", "hero", "world", "how", "are", "you", "hello", "war"}
+	prefix := "he"
+	// Suppose "hello" was the previous word, if "hell" often follows "hello" more than others,
+	// Algorithm_1 should rank "hell" higher. Let's define ideal: "hell", "helicopter" as top due to context.
+	// Without actual user context passed, we rely on bigram frequencies.
+	ideal := []string{"hell", "helicopter"}
+
+	trieA1 := buildAlg1Trie(corpus)
+	trieA2 := buildAlg2Trie(corpus)
+
+	suggestionsA1 := trieA1.Autocomplete(prefix, 5)
+	suggestionsA2 := Words(trieA2.Autocomplete(prefix, 10))
+
+	var wordsA1 []string
+	for _, s := range suggestionsA1 {
+		wordsA1 = append(wordsA1, s.Word)
+	}
+
+	qA1 := MeasureSuggestionQuality(wordsA1, ideal)
+	qA2 := MeasureSuggestionQuality(suggestionsA2, ideal)
+
+	// If Algorithm_1 is contextual, qA1 should be >= qA2 in most cases.
+	if qA1 < qA2 {
+		t.Errorf("Algorithm_1 should provide better or equal contextual matches, got qA1: %f, qA2: %f", qA1, qA2)
+	}
+}
+
+// Test Case 3: Non-Existent Prefix
+func TestNonExistentPrefix(t *testing.T) {
+	corpus := []string{"hello", "hell", "helicopter", "hero", "world"}
+	prefix := "xyz"
+
+	trieA1 := buildAlg1Trie(corpus)
+	trieA2 := buildAlg2Trie(corpus)
+
+	suggestionsA1 := trieA1.Autocomplete(prefix, 5)
+	suggestionsA2 := Words(trieA2.Autocomplete(prefix, 10))
+
+	if len(suggestionsA1) != 0 {
+		t.Errorf("Expected empty result for Algorithm_1 with prefix '%s'", prefix)
+	}
+	if len(suggestionsA2) != 0 {
+		t.Errorf("Expected empty result for Algorithm_2 with prefix '%s'", prefix)
+	}
+}
+
+// Test Case 4: Long Prefix Single Match
+func TestLongPrefixSingleMatch(t *testing.T) {
+	corpus := []string{"helicopter", "hello", "hero"}
+	prefix := "helico"
+
+	trieA1 := buildAlg1Trie(corpus)
+	trieA2 := buildAlg2Trie(corpus)
+
+	suggestionsA1 := trieA1.Autocomplete(prefix, 5)
+	suggestionsA2 := Words(trieA2.Autocomplete(prefix, 10))
+
+	if len(suggestionsA1) == 0 || suggestionsA1[0].Word != "helicopter" {
+		t.Errorf("Algorithm_1 expected 'helicopter' for prefix '%s'", prefix)
+	}
+	if len(suggestionsA2) == 0 || suggestionsA2[0] != "helicopter" {
+		t.Errorf("Algorithm_2 expected 'helicopter' for prefix '%s'", prefix)
+	}
+}
+
+// Test Case 5: Large Corpus Performance Test
+// This test is more about performance - run only if you want to measure.
+// We'll just measure build times and ensure no errors occur.
+
+func TestLargeCorpus(t *testing.T) {
+	// Generate a large corpus (simple repetition)
+	var corpus []string
+	for i := 0; i < 10000; i++ {
+		corpus = append(corpus, "he"+time.Now().Format("150405000000")) // unique words
+	}
+	prefix := "he"
+
+	startTime := time.Now()
+	trieA1 := buildAlg1Trie(corpus)
+	buildA1Time := time.Since(startTime)
+
+	startTime = time.Now()
+	trieA2 := buildAlg2Trie(corpus)
+	buildA2Time := time.Since(startTime)
+
+	suggestionsA1 := trieA1.Autocomplete(prefix, 10)
+	suggestionsA2 := Words(trieA2.Autocomplete(prefix, 10))
+
+	// We don't have an ideal here, just checking no error and performance.
+	if len(suggestionsA1) == 0 {
+		t.Errorf("Algorithm_1 returned no suggestions. Possibly an error.")
+	}
+	if len(suggestionsA2) == 0 {
+		t.Errorf("Algorithm_2 returned no suggestions. Possibly an error.")
+	}
+
+	// Just print performance info (not strictly pass/fail).
+	t.Logf("Algorithm_1 Build Time: %v, Algorithm_2 Build Time: %v", buildA1Time, buildA2Time)
+}
+
+// Test Case 6: BuildFromCorpus matches the manual two-step build
+func TestBuildFromCorpus(t *testing.T) {
+	corpus := []string{"hello", "hell", "helicopter", "hero", "world", "how", "are", "you", "hello", "war", "hello"}
+
+	manual := buildAlg1Trie(corpus)
+	combined := NewTrieA1()
+	combined.BuildFromCorpus(corpus)
+
+	if !reflect.DeepEqual(manual.root, combined.root) {
+		t.Errorf("BuildFromCorpus produced a different trie than Insert + BuildBigramTable")
+	}
+	if !reflect.DeepEqual(manual.bigramTable, combined.bigramTable) {
+		t.Errorf("BuildFromCorpus produced a different bigram table: %v vs %v", combined.bigramTable, manual.bigramTable)
+	}
+}
+
+func TestContainsAndFrequency(t *testing.T) {
+	corpus := []string{"hello", "hello", "hell", "help"}
+	a1, a2 := buildAlg1Trie(corpus), buildAlg2Trie(corpus)
+
+	tests := []struct {
+		word      string
+		contains  bool
+		frequency int
+	}{
+		{"hello", true, 2},
+		{"hell", true, 1},
+		{"hel", false, 0},
+		{"helper", false, 0},
+		{"", false, 0},
+	}
+	for _, test := range tests {
+		if got := a1.Contains(test.word); got != test.contains {
+			t.Errorf("Expected A1 Contains(%q) to be %v, got %v", test.word, test.contains, got)
+		}
+		if got := a2.Contains(test.word); got != test.contains {
+			t.Errorf("Expected A2 Contains(%q) to be %v, got %v", test.word, test.contains, got)
+		}
+		if got := a1.Frequency(test.word); got != test.frequency {
+			t.Errorf("Expected A1 Frequency(%q) to be %d, got %d", test.word, test.frequency, got)
+		}
+		if got := a2.Frequency(test.word); got != test.frequency {
+			t.Errorf("Expected A2 Frequency(%q) to be %d, got %d", test.word, test.frequency, got)
+		}
+	}
+
+	a1.Delete("hell")
+	a2.Delete("hell")
+	if a1.Contains("hell") || a2.Contains("hell") || a1.Frequency("hell") != 0 || a2.Frequency("hell") != 0 {
+		t.Errorf("Expected a deleted word to be gone")
+	}
+}
+
+func TestCollectCompletionsDeepTrie(t *testing.T) {
+	// Far deeper than any word, to show the walk does not recurse.
+	long := strings.Repeat("ab", 50000)
+	trie := buildAlg1Trie([]string{long, long + "c", long[:1000] + "x", "b"})
+
+	got := trie.collectCompletions(trie.root, nil)
+	sort.Slice(got, func(i, j int) bool { return got[i].Word < got[j].Word })
+	want := []string{long, long + "c", long[:1000] + "x", "b"}
+	if !reflect.DeepEqual(Words(got), want) {
+		t.Errorf("Expected %d intact words, got %d", len(want), len(got))
+	}
+}
+
+func TestCollectCompletionsLeavesPrefixAlone(t *testing.T) {
+	trie := buildAlg1Trie([]string{"help", "helm", "hello", "he"})
+
+	// Spare capacity behind the prefix, which a careless append would
+	// write into.
+	buffer := []rune("he!")
+	prefix := buffer[:2]
+	got := trie.collectCompletions(trie.searchPrefix("he"), prefix)
+	if len(got) != 4 || string(buffer) != "he!" {
+		t.Errorf("Expected 4 words and the prefix buffer untouched, got %v and %q", got, string(buffer))
+	}
+}
+
+// FuzzCollectCompletions checks the walk against a plain filter over the
+// inserted words, for map and compact nodes and every limit and depth.
+func FuzzCollectCompletions(f *testing.F) {
+	f.Add("hello hell help helium hero hello", "he", uint8(0), int8(-1))
+	f.Add("a ab abc abd b ba", "a", uint8(2), int8(1))
+	f.Add("caf\u00e9 cafe\u0301 caf\u00e8s", "caf", uint8(1), int8(-1))
+	f.Add("\u4e2d\u6587 \u4e2d\u56fd \u65e5\u672c", "", uint8(3), int8(2))
+
+	f.Fuzz(func(t *testing.T, text, prefix string, limit uint8, depth int8) {
+		if !utf8.ValidString(text) || !utf8.ValidString(prefix) {
+			t.Skip()
+		}
+		counts := make(map[string]int)
+		for _, word := range strings.Fields(text) {
+			counts[word]++
+		}
+		var want []Suggestion
+		for word, count := range counts {
+			below := utf8.RuneCountInString(word) - utf8.RuneCountInString(prefix)
+			if strings.HasPrefix(word, prefix) && (depth < 0 || below <= int(depth)) {
+				want = append(want, Suggestion{Word: word, Frequency: count})
+			}
+		}
+		sort.Slice(want, func(i, j int) bool { return want[i].Word < want[j].Word })
+		truncated := int(limit) > 0 && len(want) > int(limit)
+		if truncated {
+			want = want[:limit]
+		}
+
+		for _, compact := range []bool{false, true} {
+			trie := NewTrieA1().WithCompactNodes(compact)
+			for _, word := range strings.Fields(text) {
+				trie.Insert(word)
+			}
+			node := trie.searchPrefix(prefix)
+			if node == nil {
+				if len(want) > 0 {
+					t.Fatalf("Expected a node for prefix %q", prefix)
+				}
+				continue
+			}
+			got, gotTruncated := trie.collectCompletionsLimit(node, []rune(prefix), int(limit), int(depth), nil)
+			if limit == 0 {
+				sort.Slice(got, func(i, j int) bool { return got[i].Word < got[j].Word })
+			}
+			if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Fatalf("For prefix %q, limit %d and depth %d (compact %v): expected %v, got %v", prefix, limit, depth, compact, want, got)
+			}
+			if gotTruncated != truncated {
+				t.Fatalf("For prefix %q and limit %d: expected truncated %v, got %v", prefix, limit, truncated, gotTruncated)
+			}
+		}
+	})
+}
+
+// FuzzInsertAutocomplete inserts the words of text into both tries, deletes
+// one occurrence of each word of deletes, and checks that every word left is
+// suggested for each of its prefixes, that every suggestion starts with its
+// prefix and that no frequency goes negative.
+func FuzzInsertAutocomplete(f *testing.F) {
+	f.Add("hello hell help hello world", "hell hell nothing")
+	f.Add("café café caffè café", "café")
+	f.Add("東京 東京都 京都 東", "東京 東京")
+	f.Add("🙂 🙂🙃 a\u0000b", "🙃")
+
+	f.Fuzz(func(t *testing.T, text, deletes string) {
+		if !utf8.ValidString(text) || !utf8.ValidString(deletes) {
+			t.Skip()
+		}
+		a1, a2 := NewTrieA1(), NewTriesA2()
+		counts := make(map[string]int)
+		for _, word := range strings.Fields(text) {
+			a1.Insert(word)
+			a2.Insert(word)
+			counts[word]++
+		}
+		for _, word := range strings.Fields(deletes) {
+			deleted := counts[word] > 0
+			if got := a1.Delete(word); got != deleted {
+				t.Fatalf("Expected A1 Delete(%q) to return %v, got %v", word, deleted, got)
+			}
+			if got := a2.Delete(word); got != deleted {
+				t.Fatalf("Expected A2 Delete(%q) to return %v, got %v", word, deleted, got)
+			}
+			if deleted {
+				counts[word]--
+			}
+		}
+		if err := a2.Validate(); err != nil {
+			t.Fatalf("Expected a valid A2 trie, got %v", err)
+		}
+
+		for word, count := range counts {
+			if got := a1.Frequency(word); got != count || got < 0 {
+				t.Fatalf("Expected A1 frequency %d for %q, got %d", count, word, got)
+			}
+			if got := a2.Frequency(word); got != count || got < 0 {
+				t.Fatalf("Expected A2 frequency %d for %q, got %d", count, word, got)
+			}
+			if count == 0 {
+				continue
+			}
+			// Every rune boundary of word, the end included.
+			for end := range word + " " {
+				prefix := word[:end]
+				for name, trie := range map[string]Autocompleter{"A1": a1, "A2": a2} {
+					suggestions := trie.Autocomplete(prefix, trie.Len())
+					found := false
+					for _, s := range suggestions {
+						if !strings.HasPrefix(s.Word, prefix) {
+							t.Fatalf("%s suggested %q for prefix %q", name, s.Word, prefix)
+						}
+						if s.Frequency <= 0 {
+							t.Fatalf("%s suggested %q with frequency %d", name, s.Word, s.Frequency)
+						}
+						found = found || s.Word == word
+					}
+					if !found {
+						t.Fatalf("%s did not suggest %q for prefix %q, got %v", name, word, prefix, suggestions)
+					}
+				}
+			}
+		}
+	})
+}
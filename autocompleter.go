@@ -0,0 +1,56 @@
+package autocomplete
+
+// -----------------------------------------
+// Common Interface
+// -----------------------------------------
+
+// Autocompleter is the contract shared by every completion algorithm, so
+// callers and benchmarks can swap implementations freely.
+type Autocompleter interface {
+	// Insert adds one occurrence of word.
+	Insert(word string)
+	// InsertWithWeight adds weight occurrences of word at once.
+	InsertWithWeight(word string, weight int)
+	// Autocomplete returns up to k ranked completions of prefix.
+	Autocomplete(prefix string, k int) []Suggestion
+	// Len returns the number of distinct words stored.
+	Len() int
+}
+
+// Trie is the default completion algorithm, Algorithm_1: it ranks by
+// context when one is given and by frequency otherwise, and supports every
+// option of the package. Callers that need another ranking or layout use
+// TriesA2, TrieA3 or TrieA4 directly.
+type Trie = TrieA1
+
+// NewTrie returns an empty Trie.
+func NewTrie() *Trie {
+	return NewTrieA1()
+}
+
+var (
+	_ Autocompleter = (*Trie)(nil)
+	_ Autocompleter = (*TrieA1)(nil)
+	_ Autocompleter = (*TriesA2)(nil)
+	_ Autocompleter = (*TrieA3)(nil)
+	_ Autocompleter = (*TrieA4)(nil)
+)
+
+// Len returns the number of distinct words in the trie.
+func (t *TrieA1) Len() int {
+	return t.size
+}
+
+// Len returns the number of distinct words in the trie.
+func (t *TriesA2) Len() int {
+	return t.size
+}
+
+// Words extracts the words of suggestions, preserving their order.
+func Words(suggestions []Suggestion) []string {
+	words := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		words[i] = s.Word
+	}
+	return words
+}
@@ -0,0 +1,92 @@
+package autocomplete
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func implementations() map[string]func() Autocompleter {
+	return map[string]func() Autocompleter{
+		"Algorithm_1": func() Autocompleter { return NewTrieA1() },
+		"Algorithm_2": func() Autocompleter { return NewTriesA2() },
+		"Algorithm_3": func() Autocompleter { return NewTrieA3() },
+		"Algorithm_4": func() Autocompleter { return NewTrieA4() },
+		"Trie":        func() Autocompleter { return NewTrie() },
+	}
+}
+
+func TestAutocompleterContract(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hell", "hell", "helicopter", "hero", "world", ""}
+
+	for name, newTrie := range implementations() {
+		trie := newTrie()
+		for _, w := range corpus {
+			trie.Insert(w)
+		}
+
+		if trie.Len() != 5 {
+			t.Errorf("%s: expected 5 distinct words, got %d", name, trie.Len())
+		}
+
+		suggestions := trie.Autocomplete("he", 2)
+		if got := Words(suggestions); len(got) != 2 || got[0] != "hello" || got[1] != "hell" {
+			t.Errorf("%s: expected [hello hell], got %v", name, got)
+		}
+		if got := trie.Autocomplete("xyz", 2); len(got) != 0 {
+			t.Errorf("%s: expected no suggestions for unknown prefix, got %v", name, got)
+		}
+	}
+}
+
+func TestInsertWithWeight(t *testing.T) {
+	for name, newTrie := range implementations() {
+		trie := newTrie()
+		trie.InsertWithWeight("the", 1000000)
+		trie.InsertWithWeight("then", 2)
+		trie.Insert("then")
+		trie.InsertWithWeight("there", 0)
+		trie.InsertWithWeight("these", -5)
+
+		if trie.Len() != 2 {
+			t.Errorf("%s: expected 2 distinct words, got %d", name, trie.Len())
+		}
+		got := trie.Autocomplete("th", 5)
+		if len(got) != 2 || got[0].Frequency != 1000000 || got[1].Frequency != 3 {
+			t.Errorf("%s: expected the(1000000) then then(3), got %v", name, got)
+		}
+	}
+}
+
+func TestInsertWithWeightFloat(t *testing.T) {
+	for name, newTrie := range implementations() {
+		trie := newTrie().(interface {
+			Autocompleter
+			InsertWithWeightFloat(word string, weight float64)
+		})
+		trie.InsertWithWeightFloat("the", 2.5)
+		trie.InsertWithWeightFloat("then", 1.4)
+		trie.InsertWithWeightFloat("there", 0.4)
+		trie.InsertWithWeightFloat("these", math.NaN())
+		trie.InsertWithWeightFloat("they", math.Inf(-1))
+
+		got := trie.Autocomplete("th", 5)
+		if len(got) != 2 || got[0].Frequency != 3 || got[1].Frequency != 1 {
+			t.Errorf("%s: expected the(3) then then(1), got %v", name, got)
+		}
+	}
+}
+
+func TestSuggestionJSON(t *testing.T) {
+	trie := NewTrieA1()
+	trie.BuildFromCorpus([]string{"hello", "hello", "hero"})
+	trie.SetPrecision(2)
+
+	data, err := json.Marshal(trie.Autocomplete("he", 1))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := `[{"word":"hello","score":0.67,"frequency":2}]`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
@@ -0,0 +1,30 @@
+package autocomplete
+
+// -----------------------------------------
+// Prefix Backoff
+// -----------------------------------------
+
+// AutocompleteBackoff completes prefix like Autocomplete, but when prefix has
+// no matches it backs off to the longest leading part of prefix that exists
+// in the trie and completes that instead, so "helixx" still suggests
+// "helicopter" via "heli". It also reports how many trailing runes were
+// dropped. If not even the first rune matches, the result is empty.
+func (t *TrieA1) AutocompleteBackoff(prefix string, k int) ([]Suggestion, int) {
+	runes := []rune(prefix)
+	node := t.root
+	matched := 0
+	for _, char := range runes {
+		child := node.child(char)
+		if child == nil {
+			break
+		}
+		node = child
+		matched++
+	}
+
+	dropped := len(runes) - matched
+	if matched == 0 && dropped > 0 {
+		return nil, dropped
+	}
+	return t.Autocomplete(string(runes[:matched]), k), dropped
+}
@@ -0,0 +1,32 @@
+package autocomplete
+
+import "testing"
+
+func TestAutocompleteBackoffOvershoot(t *testing.T) {
+	trie := buildAlg1Trie([]string{"helicopter", "hello", "hero"})
+
+	suggestions, dropped := trie.AutocompleteBackoff("helix", 5)
+	if dropped != 1 {
+		t.Errorf("Expected 1 dropped rune, got %d", dropped)
+	}
+	if len(suggestions) != 1 || suggestions[0].Word != "helicopter" {
+		t.Errorf("Expected 'helicopter' after backing off to 'heli', got %v", suggestions)
+	}
+
+	suggestions, dropped = trie.AutocompleteBackoff("hel", 5)
+	if dropped != 0 || len(suggestions) != 2 {
+		t.Errorf("Expected a plain completion for a valid prefix, got %v (dropped %d)", suggestions, dropped)
+	}
+}
+
+func TestAutocompleteBackoffNoValidPrefix(t *testing.T) {
+	trie := buildAlg1Trie([]string{"helicopter", "hello", "hero"})
+
+	suggestions, dropped := trie.AutocompleteBackoff("xyz", 5)
+	if len(suggestions) != 0 {
+		t.Errorf("Expected empty result, got %v", suggestions)
+	}
+	if dropped != 3 {
+		t.Errorf("Expected all 3 runes dropped, got %d", dropped)
+	}
+}
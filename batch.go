@@ -0,0 +1,42 @@
+package autocomplete
+
+import "sync"
+
+// -----------------------------------------
+// Batch Queries
+// -----------------------------------------
+
+// batchParallelThreshold is the batch size from which AutocompleteBatch
+// spreads prefixes across goroutines.
+const batchParallelThreshold = 64
+
+// AutocompleteBatch completes every prefix independently and returns the
+// results keyed by prefix. Every input gets an entry: a prefix without
+// matches maps to an empty slice. Large batches are processed concurrently;
+// like Autocomplete, a batch must not run alongside Insert.
+func (t *TrieA1) AutocompleteBatch(prefixes []string, k int) map[string][]Suggestion {
+	results := make(map[string][]Suggestion, len(prefixes))
+	complete := func(prefix string) []Suggestion {
+		suggestions := t.Autocomplete(prefix, k)
+		if suggestions == nil {
+			suggestions = []Suggestion{}
+		}
+		return suggestions
+	}
+
+	if len(prefixes) < batchParallelThreshold {
+		for _, prefix := range prefixes {
+			results[prefix] = complete(prefix)
+		}
+		return results
+	}
+
+	var mu sync.Mutex
+	forEachConcurrently(prefixes, func(prefix string) {
+		suggestions := complete(prefix)
+		mu.Lock()
+		results[prefix] = suggestions
+		mu.Unlock()
+	})
+	return results
+}
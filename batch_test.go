@@ -0,0 +1,56 @@
+package autocomplete
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAutocompleteBatch(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "hell", "helicopter", "world", "war"})
+
+	prefixes := []string{"he", "w", "xyz", "hello"}
+	results := trie.AutocompleteBatch(prefixes, 2)
+
+	if len(results) != len(prefixes) {
+		t.Fatalf("Expected %d entries, got %d", len(prefixes), len(results))
+	}
+	for _, prefix := range prefixes {
+		suggestions, ok := results[prefix]
+		if !ok {
+			t.Errorf("Missing entry for prefix '%s'", prefix)
+		}
+		if suggestions == nil {
+			t.Errorf("Expected an empty slice rather than nil for prefix '%s'", prefix)
+		}
+	}
+	if len(results["xyz"]) != 0 {
+		t.Errorf("Expected no suggestions for 'xyz', got %v", results["xyz"])
+	}
+	if got := results["he"]; len(got) != 2 || got[0].Word != "hello" {
+		t.Errorf("Unexpected suggestions for 'he': %v", got)
+	}
+}
+
+func TestAutocompleteBatchParallel(t *testing.T) {
+	var corpus []string
+	for i := 0; i < 500; i++ {
+		corpus = append(corpus, fmt.Sprintf("w%03d", i))
+	}
+	trie := buildAlg1Trie(corpus)
+
+	var prefixes []string
+	for i := 0; i < 200; i++ {
+		prefixes = append(prefixes, fmt.Sprintf("w%02d", i))
+	}
+	results := trie.AutocompleteBatch(prefixes, 3)
+
+	if len(results) != len(prefixes) {
+		t.Fatalf("Expected %d entries, got %d", len(prefixes), len(results))
+	}
+	for _, prefix := range prefixes {
+		want := trie.Autocomplete(prefix, 3)
+		if len(results[prefix]) != len(want) {
+			t.Errorf("Prefix '%s': expected %d suggestions, got %d", prefix, len(want), len(results[prefix]))
+		}
+	}
+}
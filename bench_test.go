@@ -0,0 +1,130 @@
+package autocomplete
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// benchCorpus is a synthetic running text: a vocabulary of pronounceable
+// words drawn with Zipf-distributed frequencies, like natural language, so
+// a few words dominate and most prefixes have long tails of rare completions.
+var benchCorpus = sync.OnceValue(func() []string {
+	rng := rand.New(rand.NewSource(42))
+	syllables := []string{"ba", "con", "de", "er", "fi", "gra", "hel", "in", "ka", "lo", "men", "no", "pre", "qua", "ro", "st", "tion", "un", "ver", "wa"}
+
+	vocabulary := make([]string, 5000)
+	for i := range vocabulary {
+		var word strings.Builder
+		for n := 1 + rng.Intn(4); n > 0; n-- {
+			word.WriteString(syllables[rng.Intn(len(syllables))])
+		}
+		vocabulary[i] = word.String()
+	}
+
+	zipf := rand.NewZipf(rng, 1.1, 1, uint64(len(vocabulary)-1))
+	corpus := make([]string, 50000)
+	for i := range corpus {
+		corpus[i] = vocabulary[zipf.Uint64()]
+	}
+	return corpus
+})
+
+// benchPrefixes returns prefixes of the given rune length taken from the
+// corpus, so every query has at least one completion.
+func benchPrefixes(length int) []string {
+	var prefixes []string
+	for _, word := range benchCorpus()[:1000] {
+		if runes := []rune(word); len(runes) >= length {
+			prefixes = append(prefixes, string(runes[:length]))
+		}
+	}
+	return prefixes
+}
+
+func benchmarkInsert(b *testing.B, newTrie func() Autocompleter) {
+	corpus := benchCorpus()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		trie := newTrie()
+		for _, word := range corpus {
+			trie.Insert(word)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(corpus)), "ns/word")
+}
+
+func benchmarkAutocomplete(b *testing.B, trie Autocompleter) {
+	for _, length := range []int{1, 2, 3, 5} {
+		prefixes := benchPrefixes(length)
+		b.Run(fmt.Sprintf("prefix=%d", length), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				trie.Autocomplete(prefixes[i%len(prefixes)], 10)
+			}
+		})
+	}
+}
+
+func BenchmarkInsertA1(b *testing.B) {
+	benchmarkInsert(b, func() Autocompleter { return NewTrieA1() })
+}
+
+func BenchmarkInsertA2(b *testing.B) {
+	benchmarkInsert(b, func() Autocompleter { return NewTriesA2() })
+}
+
+func BenchmarkInsertA3(b *testing.B) {
+	benchmarkInsert(b, func() Autocompleter { return NewTrieA3() })
+}
+
+func BenchmarkInsertA4(b *testing.B) {
+	benchmarkInsert(b, func() Autocompleter { return NewTrieA4() })
+}
+
+func BenchmarkAutocompleteA1(b *testing.B) {
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(benchCorpus())
+	benchmarkAutocomplete(b, trie)
+}
+
+func BenchmarkAutocompleteFinalizedA1(b *testing.B) {
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(benchCorpus())
+	trie.Finalize(10)
+	benchmarkAutocomplete(b, trie)
+}
+
+func BenchmarkAutocompleteWithContextA1(b *testing.B) {
+	corpus := benchCorpus()
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(corpus)
+	prefixes := benchPrefixes(2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie.AutocompleteWithContext(corpus[i%len(corpus)], prefixes[i%len(prefixes)], 10)
+	}
+}
+
+func BenchmarkAutocompleteA2(b *testing.B) {
+	benchmarkAutocomplete(b, buildAlg2Trie(benchCorpus()))
+}
+
+func BenchmarkAutocompleteA3(b *testing.B) {
+	trie := NewTrieA3()
+	for _, word := range benchCorpus() {
+		trie.Insert(word)
+	}
+	benchmarkAutocomplete(b, trie)
+}
+
+func BenchmarkAutocompleteA4(b *testing.B) {
+	trie := NewTrieA4()
+	for _, word := range benchCorpus() {
+		trie.Insert(word)
+	}
+	benchmarkAutocomplete(b, trie)
+}
@@ -0,0 +1,101 @@
+package autocomplete
+
+import (
+	"math"
+	"strings"
+)
+
+// -----------------------------------------
+// Bigram Model Utilities
+// -----------------------------------------
+
+// BigramModelStats summarizes how informative the bigram table is.
+type BigramModelStats struct {
+	Contexts     int     // distinct words that have at least one follower
+	AvgFollowers float64 // average number of distinct followers per context
+	Entropy      float64 // conditional entropy H(next | context) in bits
+	Perplexity   float64 // 2^Entropy; 1.0 means the next word is fully predictable
+}
+
+// BigramStats computes statistics over the bigram table built from the
+// training corpus. Low entropy means context strongly predicts the next word
+// and contextual ranking is worth its memory; entropy close to that of plain
+// frequency means it adds little.
+func (t *TrieA1) BigramStats() BigramModelStats {
+	var stats BigramModelStats
+	totalBigrams := 0
+	followers := 0
+
+	for _, contextData := range t.bigramTable {
+		stats.Contexts++
+		followers += len(contextData) - 1 // exclude "_total"
+		totalBigrams += contextData["_total"]
+	}
+	if stats.Contexts == 0 || totalBigrams == 0 {
+		stats.Perplexity = 1
+		return stats
+	}
+	stats.AvgFollowers = float64(followers) / float64(stats.Contexts)
+
+	// H = -sum P(w1, w2) * log2 P(w2 | w1)
+	for _, contextData := range t.bigramTable {
+		contextTotal := float64(contextData["_total"])
+		for word, count := range contextData {
+			if word == "_total" || count == 0 {
+				continue
+			}
+			joint := float64(count) / float64(totalBigrams)
+			conditional := float64(count) / contextTotal
+			stats.Entropy -= joint * math.Log2(conditional)
+		}
+	}
+	stats.Perplexity = math.Pow(2, stats.Entropy)
+	return stats
+}
+
+// PruneBigrams removes every context and follower from the bigram table that
+// is no longer a word in the trie and recomputes "_total" for the contexts it
+// touched. Contexts of the N-gram table go when any of their words does.
+// Call it after removing words so stale entries stop skewing the contextual
+// probabilities.
+func (t *TrieA1) PruneBigrams() {
+	pruneFollowers(t.bigramTable, t.Contains)
+	pruneFollowers(t.ngramTable, func(context string) bool {
+		for _, word := range strings.Split(context, ngramSeparator) {
+			if !t.Contains(word) {
+				return false
+			}
+		}
+		return true
+	})
+	t.cache.clear()
+}
+
+// pruneFollowers removes from table the contexts that known rejects and the
+// followers that are no longer words in the trie, recomputing "_total" for
+// the contexts that remain. known also judges the followers, which are
+// single words.
+func pruneFollowers(table map[string]map[string]int, known func(string) bool) {
+	for context, followers := range table {
+		if !known(context) {
+			delete(table, context)
+			continue
+		}
+		total := 0
+		for word, count := range followers {
+			if word == "_total" {
+				continue
+			}
+			if !known(word) {
+				delete(followers, word)
+				continue
+			}
+			total += count
+		}
+		if total == 0 {
+			delete(table, context)
+			continue
+		}
+		followers["_total"] = total
+	}
+}
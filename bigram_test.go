@@ -0,0 +1,109 @@
+package autocomplete
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestBigramStatsDistinguishesPredictability(t *testing.T) {
+	// Every word is always followed by the same word.
+	var predictable []string
+	for i := 0; i < 200; i++ {
+		predictable = append(predictable, "alpha", "beta", "gamma")
+	}
+
+	// Adjacency is random over a small vocabulary.
+	rng := rand.New(rand.NewSource(42))
+	var random []string
+	for i := 0; i < 600; i++ {
+		random = append(random, fmt.Sprintf("w%d", rng.Intn(10)))
+	}
+
+	low := buildAlg1Trie(predictable).BigramStats()
+	high := buildAlg1Trie(random).BigramStats()
+
+	if low.Contexts != 3 {
+		t.Errorf("Expected 3 contexts, got %d", low.Contexts)
+	}
+	if low.AvgFollowers != 1 {
+		t.Errorf("Expected 1 follower per context, got %f", low.AvgFollowers)
+	}
+	if low.Entropy != 0 || low.Perplexity != 1 {
+		t.Errorf("Expected zero entropy for a deterministic corpus, got H=%f PP=%f", low.Entropy, low.Perplexity)
+	}
+	if high.Entropy <= low.Entropy+2 {
+		t.Errorf("Expected random adjacency to have much higher entropy, got %f vs %f", high.Entropy, low.Entropy)
+	}
+}
+
+func TestBigramStatsEmpty(t *testing.T) {
+	stats := NewTrieA1().BigramStats()
+	if stats.Contexts != 0 || stats.Entropy != 0 || stats.Perplexity != 1 {
+		t.Errorf("Unexpected stats for an empty model: %+v", stats)
+	}
+}
+
+func TestPruneBigrams(t *testing.T) {
+	corpus := []string{"new", "york", "city", "new", "jersey", "new", "york", "times"}
+
+	// The trie is missing "jersey" and "times", as it would be after those
+	// words were removed.
+	trie := NewTrieA1()
+	for _, w := range []string{"new", "york", "city"} {
+		trie.Insert(w)
+	}
+	trie.BuildBigramTable(corpus)
+
+	trie.PruneBigrams()
+
+	for _, removed := range []string{"jersey", "times"} {
+		if _, exists := trie.bigramTable[removed]; exists {
+			t.Errorf("Context '%s' should have been pruned", removed)
+		}
+		for context, followers := range trie.bigramTable {
+			if _, exists := followers[removed]; exists {
+				t.Errorf("Follower '%s' of '%s' should have been pruned", removed, context)
+			}
+		}
+	}
+
+	if got := trie.bigramTable["new"]["_total"]; got != 2 {
+		t.Errorf("Expected total of 2 for 'new' (york x2), got %d", got)
+	}
+	if got := trie.bigramTable["york"]["_total"]; got != 1 {
+		t.Errorf("Expected total of 1 for 'york' (city), got %d", got)
+	}
+	if _, exists := trie.bigramTable["city"]; !exists {
+		t.Errorf("Context 'city' still has follower 'new' and should be kept")
+	}
+}
+
+func TestPruneBigramsPrunesNgrams(t *testing.T) {
+	corpus := []string{"new", "york", "city", "new", "jersey", "city", "new", "york", "times"}
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(corpus)
+	trie.BuildNgramTable(corpus, 3)
+	trie.Delete("jersey")
+	trie.Delete("times")
+
+	trie.PruneBigrams()
+
+	for context, followers := range trie.ngramTable {
+		for _, word := range strings.Split(context, ngramSeparator) {
+			if word == "jersey" || word == "times" {
+				t.Errorf("Context %q should have been pruned", context)
+			}
+		}
+		for word := range followers {
+			if word == "jersey" || word == "times" {
+				t.Errorf("Follower '%s' of %q should have been pruned", word, context)
+			}
+		}
+	}
+	newYork := "new" + ngramSeparator + "york"
+	if got := trie.ngramTable[newYork]["_total"]; got != 1 {
+		t.Errorf("Expected total of 1 for 'new york' (city), got %d", got)
+	}
+}
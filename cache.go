@@ -0,0 +1,188 @@
+package autocomplete
+
+import (
+	"container/list"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// -----------------------------------------
+// Suggestion Cache
+// -----------------------------------------
+
+type cacheKey struct {
+	prefix  string
+	context string // context words joined by ngramSeparator
+	k       int
+}
+
+// cacheEntryOverhead approximates the bytes an entry costs beyond its
+// strings: the list element, the map slot and the slice headers.
+const cacheEntryOverhead = 128
+
+// suggestionCache memoizes Autocomplete results, evicting the least recently
+// used entries once it holds more than maxEntries entries or maxBytes bytes;
+// a zero limit is no limit. All methods are safe to call on a nil cache,
+// which behaves as a cache that never hits.
+type suggestionCache struct {
+	mu         sync.Mutex
+	entries    map[cacheKey]*list.Element
+	recency    *list.List // of *cacheEntry, most recently used first
+	bytes      int
+	maxEntries int
+	maxBytes   int
+	hits       int
+	misses     int
+}
+
+type cacheEntry struct {
+	key         cacheKey
+	suggestions []Suggestion
+	bytes       int
+}
+
+func newSuggestionCache() *suggestionCache {
+	return &suggestionCache{entries: make(map[cacheKey]*list.Element), recency: list.New()}
+}
+
+// emptyCopy returns a new cache with the same limits, or nil for nil.
+func (c *suggestionCache) emptyCopy() *suggestionCache {
+	if c == nil {
+		return nil
+	}
+	copied := newSuggestionCache()
+	c.mu.Lock()
+	copied.maxEntries, copied.maxBytes = c.maxEntries, c.maxBytes
+	c.mu.Unlock()
+	return copied
+}
+
+func (c *suggestionCache) get(key cacheKey) ([]Suggestion, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.recency.MoveToFront(element)
+	return append([]Suggestion(nil), element.Value.(*cacheEntry).suggestions...), true
+}
+
+func (c *suggestionCache) put(key cacheKey, suggestions []Suggestion) {
+	if c == nil {
+		return
+	}
+	entry := &cacheEntry{
+		key:         key,
+		suggestions: append([]Suggestion(nil), suggestions...),
+		bytes:       cacheEntryOverhead + len(key.prefix) + len(key.context),
+	}
+	for _, s := range suggestions {
+		entry.bytes += int(unsafe.Sizeof(s)) + len(s.Word) + len(s.CorrectedPrefix)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	c.entries[key] = c.recency.PushFront(entry)
+	c.bytes += entry.bytes
+	c.evict()
+}
+
+// evict drops least recently used entries until the cache is within its
+// limits.
+func (c *suggestionCache) evict() {
+	for c.recency.Len() > 0 &&
+		(c.maxEntries > 0 && c.recency.Len() > c.maxEntries || c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.remove(c.recency.Back())
+	}
+}
+
+func (c *suggestionCache) remove(element *list.Element) {
+	entry := c.recency.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.bytes
+}
+
+func (c *suggestionCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]*list.Element)
+	c.recency.Init()
+	c.bytes = 0
+}
+
+// EnableCache turns on result caching for Autocomplete, AutocompleteRunes and
+// AutocompleteContext, without a size limit. Cached results are dropped
+// whenever the trie, its context tables or the ranking settings change.
+func (t *TrieA1) EnableCache() {
+	if t.cache == nil {
+		t.cache = newSuggestionCache()
+	}
+}
+
+// SetCacheLimits enables the cache like EnableCache and bounds it to at most
+// maxEntries results and roughly maxBytes bytes, evicting the least recently
+// used results first. Hot short prefixes stay cached while one-off long ones
+// age out. Zero leaves a dimension unlimited.
+func (t *TrieA1) SetCacheLimits(maxEntries, maxBytes int) {
+	t.EnableCache()
+	t.cache.mu.Lock()
+	defer t.cache.mu.Unlock()
+	t.cache.maxEntries, t.cache.maxBytes = max(maxEntries, 0), max(maxBytes, 0)
+	t.cache.evict()
+}
+
+// CacheStats reports how many Autocomplete calls were served from the cache
+// and how many had to traverse the trie.
+func (t *TrieA1) CacheStats() (hits, misses int) {
+	if t.cache == nil {
+		return 0, 0
+	}
+	t.cache.mu.Lock()
+	defer t.cache.mu.Unlock()
+	return t.cache.hits, t.cache.misses
+}
+
+// Warmup precomputes and caches the top k completions for each prefix so the
+// first real query for them is a cache hit. Prefixes are processed
+// concurrently; like Autocomplete, Warmup must not run alongside Insert or
+// BuildBigramTable. Warmup enables the cache if it is not already on.
+func (t *TrieA1) Warmup(prefixes []string, k int) {
+	t.EnableCache()
+
+	forEachConcurrently(prefixes, func(prefix string) {
+		t.cache.put(cacheKey{prefix: prefix, k: k}, t.autocomplete(prefix, k))
+	})
+}
+
+// forEachConcurrently calls fn for every item using one worker per CPU.
+func forEachConcurrently(items []string, fn func(string)) {
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				fn(item)
+			}
+		}()
+	}
+	for _, item := range items {
+		jobs <- item
+	}
+	close(jobs)
+	wg.Wait()
+}
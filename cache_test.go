@@ -0,0 +1,110 @@
+package autocomplete
+
+import "testing"
+
+func TestWarmupPopulatesCache(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hello", "hell", "hell", "hell", "helicopter", "helicopter", "hero", "world", "war"}
+	trie := buildAlg1Trie(corpus)
+
+	trie.Warmup([]string{"he", "w", "xyz"}, 3)
+
+	for _, prefix := range []string{"he", "w", "xyz"} {
+		if _, ok := trie.cache.entries[cacheKey{prefix: prefix, k: 3}]; !ok {
+			t.Errorf("Expected cache entry for prefix '%s' after Warmup", prefix)
+		}
+	}
+
+	want := trie.autocomplete("he", 3)
+	got := trie.Autocomplete("he", 3)
+	hits, misses := trie.CacheStats()
+	if hits != 1 || misses != 0 {
+		t.Errorf("Expected the query to be a cache hit, got hits=%d misses=%d", hits, misses)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Cached result has %d suggestions, expected %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Cached suggestion %d is %v, expected %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCacheInvalidatedOnInsert(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hell"})
+	trie.EnableCache()
+
+	trie.Autocomplete("he", 5)
+	trie.Insert("helium")
+	suggestions := trie.Autocomplete("he", 5)
+
+	if len(suggestions) != 3 {
+		t.Errorf("Expected the new word after Insert, got %v", suggestions)
+	}
+	if hits, _ := trie.CacheStats(); hits != 0 {
+		t.Errorf("Expected no cache hits after Insert, got %d", hits)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hell", "world", "war", "zebra"})
+	trie.SetCacheLimits(2, 0)
+
+	trie.Autocomplete("he", 3)
+	trie.Autocomplete("w", 3)
+	trie.Autocomplete("he", 3) // hit; "w" is now the least recently used
+	trie.Autocomplete("z", 3)  // evicts "w"
+
+	if _, ok := trie.cache.entries[cacheKey{prefix: "w", k: 3}]; ok {
+		t.Errorf("Expected 'w' to be evicted")
+	}
+	for _, prefix := range []string{"he", "z"} {
+		if _, ok := trie.cache.entries[cacheKey{prefix: prefix, k: 3}]; !ok {
+			t.Errorf("Expected '%s' to stay cached", prefix)
+		}
+	}
+	if hits, misses := trie.CacheStats(); hits != 1 || misses != 3 {
+		t.Errorf("Expected 1 hit and 3 misses, got %d and %d", hits, misses)
+	}
+}
+
+func TestCacheByteBudget(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hell", "helicopter", "world", "war"})
+	trie.SetCacheLimits(0, 1)
+
+	trie.Autocomplete("he", 3)
+	if len(trie.cache.entries) != 0 || trie.cache.bytes != 0 {
+		t.Errorf("Expected an entry larger than the budget not to be kept, got %d entries, %d bytes", len(trie.cache.entries), trie.cache.bytes)
+	}
+
+	trie.SetCacheLimits(0, 1<<20)
+	trie.Autocomplete("he", 3)
+	trie.Autocomplete("w", 3)
+	if len(trie.cache.entries) != 2 || trie.cache.bytes <= 0 {
+		t.Errorf("Expected 2 entries within a 1MB budget, got %d entries, %d bytes", len(trie.cache.entries), trie.cache.bytes)
+	}
+}
+
+func TestCacheKeyedByContext(t *testing.T) {
+	trie := NewTrieA1()
+	trie.BuildFromCorpus([]string{"new", "york", "old", "yodel", "yodel"})
+	trie.EnableCache()
+
+	afterNew := trie.AutocompleteContext("yo", []string{"new"}, 1)
+	afterOld := trie.AutocompleteContext("yo", []string{"old"}, 1)
+	if afterNew[0].Word != "york" || afterOld[0].Word != "yodel" {
+		t.Errorf("Expected york after 'new' and yodel after 'old', got %v and %v", afterNew, afterOld)
+	}
+
+	// Only the previous word matters with a bigram model, so a longer
+	// context ending in it shares the entry.
+	trie.AutocompleteContext("yo", []string{"the", "new"}, 1)
+	if hits, _ := trie.CacheStats(); hits != 1 {
+		t.Errorf("Expected the trimmed context to hit the cache, got %d hits", hits)
+	}
+
+	trie.Delete("york")
+	if got := trie.AutocompleteContext("yo", []string{"new"}, 1); got[0].Word != "yodel" {
+		t.Errorf("Expected Delete to invalidate cached context results, got %v", got)
+	}
+}
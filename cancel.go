@@ -0,0 +1,75 @@
+package autocomplete
+
+import "context"
+
+// -----------------------------------------
+// Cancellable Queries
+// -----------------------------------------
+
+// cancelCheckInterval is how many nodes a cancellable traversal visits
+// between polls of its done channel, keeping the check off the hot path.
+const cancelCheckInterval = 256
+
+// canceller stops a traversal once its done channel is closed. A nil
+// canceller never stops, so uncancellable callers pay nothing.
+type canceller struct {
+	done    <-chan struct{}
+	visited int
+	stopped bool
+}
+
+func newCanceller(ctx context.Context) *canceller {
+	return &canceller{done: ctx.Done()}
+}
+
+// stop reports whether the traversal should be abandoned.
+func (c *canceller) stop() bool {
+	if c == nil || c.done == nil {
+		return false
+	}
+	if c.stopped {
+		return true
+	}
+	c.visited++
+	if c.visited%cancelCheckInterval == 0 {
+		select {
+		case <-c.done:
+			c.stopped = true
+		default:
+		}
+	}
+	return c.stopped
+}
+
+// AutocompleteCtx is Autocomplete that gives up when ctx is cancelled or its
+// deadline passes, returning ctx's error, so a slow query on a short prefix
+// over a huge trie can be abandoned once nobody is waiting for it.
+func (t *TrieA1) AutocompleteCtx(ctx context.Context, prefix string, k int) ([]Suggestion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	key := cacheKey{prefix: prefix, k: k}
+	if cached, ok := t.cache.get(key); ok {
+		return cached, nil
+	}
+	suggestions, _ := t.autocompleteBounded([]rune(prefix), prefix, query{cancel: newCanceller(ctx)}, k)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := t.fillFromFallback(prefix, k, suggestions)
+	t.cache.put(key, result)
+	return result, nil
+}
+
+// AutocompleteCtx is Autocomplete that gives up when ctx is cancelled or its
+// deadline passes, returning ctx's error.
+func (t *TriesA2) AutocompleteCtx(ctx context.Context, prefix string, k int) ([]Suggestion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	suggestions := t.autocompleteProb(prefix, k, query{cancel: newCanceller(ctx)})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,55 @@
+package autocomplete
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAutocompleteCtx(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hell", "hell", "helicopter", "world"}
+	a1 := buildAlg1Trie(corpus)
+	a2 := buildAlg2Trie(corpus)
+
+	got1, err1 := a1.AutocompleteCtx(context.Background(), "he", 2)
+	got2, err2 := a2.AutocompleteCtx(context.Background(), "he", 2)
+	if err1 != nil || !reflect.DeepEqual(got1, a1.Autocomplete("he", 2)) {
+		t.Errorf("Expected Algorithm_1 to match Autocomplete, got %v, %v", got1, err1)
+	}
+	if err2 != nil || !reflect.DeepEqual(got2, a2.Autocomplete("he", 2)) {
+		t.Errorf("Expected Algorithm_2 to match Autocomplete, got %v, %v", got2, err2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got, err := a1.AutocompleteCtx(ctx, "he", 2); !errors.Is(err, context.Canceled) || got != nil {
+		t.Errorf("Expected Algorithm_1 to return context.Canceled, got %v, %v", got, err)
+	}
+	if got, err := a2.AutocompleteCtx(ctx, "he", 2); !errors.Is(err, context.Canceled) || got != nil {
+		t.Errorf("Expected Algorithm_2 to return context.Canceled, got %v, %v", got, err)
+	}
+}
+
+func TestCancellationStopsTraversal(t *testing.T) {
+	a1, a2 := NewTrieA1(), NewTriesA2()
+	for i := 0; i < 10*cancelCheckInterval; i++ {
+		word := fmt.Sprintf("w%05d", i)
+		a1.Insert(word)
+		a2.Insert(word)
+	}
+	done := make(chan struct{})
+	close(done)
+
+	// The done channel is only polled periodically, so the walk stops
+	// after at most one interval.
+	got, truncated := a1.collectCompletionsLimit(a1.root, nil, 0, -1, &canceller{done: done})
+	if !truncated || len(got) >= cancelCheckInterval {
+		t.Errorf("Expected Algorithm_1 to stop within %d nodes, collected %d words", cancelCheckInterval, len(got))
+	}
+	var entries []Suggestion
+	if collectEntriesA2Cancel(a2.root, "", &entries, -1, &canceller{done: done}) || len(entries) >= cancelCheckInterval {
+		t.Errorf("Expected Algorithm_2 to stop within %d nodes, collected %d words", cancelCheckInterval, len(entries))
+	}
+}
@@ -0,0 +1,210 @@
+package autocomplete
+
+import (
+	"math"
+	"time"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Click-Through Re-Ranking
+// -----------------------------------------
+
+// minSelections is the decayed count below which DecaySelections forgets a
+// selection.
+const minSelections = 0.01
+
+// clickModel counts the suggestions users accepted after typing each prefix.
+// Counts may be fractional once decayed. All methods are safe to call on a
+// nil model, which has no selections.
+type clickModel struct {
+	weight    float64
+	halfLife  time.Duration
+	lastDecay time.Time
+	now       func() time.Time
+
+	counts map[string]map[string]float64
+	totals map[string]float64
+}
+
+func newClickModel(weight float64, halfLife time.Duration, existing *clickModel) *clickModel {
+	if weight <= 0 {
+		return nil
+	}
+	m := existing
+	if m == nil {
+		m = &clickModel{
+			now:    time.Now,
+			counts: make(map[string]map[string]float64),
+			totals: make(map[string]float64),
+		}
+	}
+	m.weight, m.halfLife = min(weight, 1), max(halfLife, 0)
+	m.lastDecay = m.now()
+	return m
+}
+
+func (m *clickModel) record(prefix, word string) {
+	if m == nil {
+		return
+	}
+	if m.counts[prefix] == nil {
+		m.counts[prefix] = make(map[string]float64)
+	}
+	m.counts[prefix][word]++
+	m.totals[prefix]++
+}
+
+// rate returns the share of the selections made after prefix that picked
+// word, or zero if there were none.
+func (m *clickModel) rate(prefix, word string) float64 {
+	if m == nil || m.totals[prefix] == 0 {
+		return 0
+	}
+	return m.counts[prefix][word] / m.totals[prefix]
+}
+
+// blend mixes the click-through rate of every candidate into its score. The
+// rates come from the longest part of prefix, itself included, that anyone
+// selected a suggestion after, so "heli" still learns from picks made after
+// typing "hel".
+func (m *clickModel) blend(prefix string, scored []Suggestion) {
+	if m == nil || len(m.totals) == 0 {
+		return
+	}
+	for {
+		if m.totals[prefix] > 0 {
+			break
+		}
+		if prefix == "" {
+			return
+		}
+		_, size := utf8.DecodeLastRuneInString(prefix)
+		prefix = prefix[:len(prefix)-size]
+	}
+	for i := range scored {
+		scored[i].Score = (1-m.weight)*scored[i].Score + m.weight*m.rate(prefix, scored[i].Word)
+	}
+}
+
+// decay ages every count by the time elapsed since the previous pass and
+// forgets the ones that fall below minSelections.
+func (m *clickModel) decay() {
+	if m == nil || m.halfLife <= 0 {
+		return
+	}
+	now := m.now()
+	elapsed := now.Sub(m.lastDecay)
+	if elapsed <= 0 {
+		return
+	}
+	factor := math.Pow(0.5, float64(elapsed)/float64(m.halfLife))
+	for prefix, words := range m.counts {
+		total := 0.0
+		for word, count := range words {
+			count *= factor
+			if count < minSelections {
+				delete(words, word)
+				continue
+			}
+			words[word] = count
+			total += count
+		}
+		if len(words) == 0 {
+			delete(m.counts, prefix)
+			delete(m.totals, prefix)
+			continue
+		}
+		m.totals[prefix] = total
+	}
+	m.lastDecay = now
+}
+
+func (m *clickModel) clone() *clickModel {
+	if m == nil {
+		return nil
+	}
+	c := *m
+	c.counts = cloneFloatCounts(m.counts)
+	c.totals = make(map[string]float64, len(m.totals))
+	for prefix, total := range m.totals {
+		c.totals[prefix] = total
+	}
+	return &c
+}
+
+func cloneFloatCounts(table map[string]map[string]float64) map[string]map[string]float64 {
+	c := make(map[string]map[string]float64, len(table))
+	for key, inner := range table {
+		copied := make(map[string]float64, len(inner))
+		for word, count := range inner {
+			copied[word] = count
+		}
+		c[key] = copied
+	}
+	return c
+}
+
+// WithClickThrough blends how often users pick each suggestion into its
+// score, so the suggestions users actually choose rise over time: a
+// candidate's score becomes (1-weight) times its own plus weight times its
+// click-through rate, the share of the selections recorded with
+// RecordSelection for the prefix that picked it. Prefixes nobody selected
+// after back off to their longest shorter prefix that has selections.
+//
+// With a positive halfLife, every selection loses half its weight each
+// halfLife when DecaySelections runs, so rates follow changing tastes. A
+// weight of zero or less turns re-ranking off and forgets all selections;
+// changing the weight or halfLife of an enabled model keeps them.
+// Selections are not part of snapshots.
+func (t *TrieA1) WithClickThrough(weight float64, halfLife time.Duration) *TrieA1 {
+	t.clicks = newClickModel(weight, halfLife, t.clicks)
+	t.cache.clear()
+	return t
+}
+
+// RecordSelection counts one pick of word from the suggestions for prefix.
+// It does nothing unless WithClickThrough is enabled. When queries run
+// concurrently, call it through Concurrent.Update.
+func (t *TrieA1) RecordSelection(prefix, word string) {
+	t.clicks.record(prefix, word)
+	t.cache.clear()
+}
+
+// DecaySelections ages every recorded selection by the time elapsed since
+// the previous pass. It is meant to run periodically, as Decay is, and does
+// nothing without a halfLife.
+func (t *TrieA1) DecaySelections() {
+	t.clicks.decay()
+	t.cache.clear()
+}
+
+// ClickThroughRate returns the share of the selections recorded for prefix
+// that picked word, or zero if none were recorded.
+func (t *TrieA1) ClickThroughRate(prefix, word string) float64 {
+	return t.clicks.rate(prefix, word)
+}
+
+// WithClickThrough blends how often users pick each suggestion into its
+// score, as TrieA1.WithClickThrough does.
+func (t *TriesA2) WithClickThrough(weight float64, halfLife time.Duration) *TriesA2 {
+	t.clicks = newClickModel(weight, halfLife, t.clicks)
+	return t
+}
+
+// RecordSelection counts one pick of word from the suggestions for prefix.
+func (t *TriesA2) RecordSelection(prefix, word string) {
+	t.clicks.record(prefix, word)
+}
+
+// DecaySelections ages every recorded selection by the time elapsed since
+// the previous pass.
+func (t *TriesA2) DecaySelections() {
+	t.clicks.decay()
+}
+
+// ClickThroughRate returns the share of the selections recorded for prefix
+// that picked word, or zero if none were recorded.
+func (t *TriesA2) ClickThroughRate(prefix, word string) float64 {
+	return t.clicks.rate(prefix, word)
+}
@@ -0,0 +1,65 @@
+package autocomplete
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClickThroughReranking(t *testing.T) {
+	words := []string{"hello", "hello", "hello", "help", "helmet"}
+	a1 := buildAlg1Trie(words).WithClickThrough(0.5, 0)
+	a2 := buildAlg2Trie(words).WithClickThrough(0.5, 0)
+
+	for name, trie := range map[string]interface {
+		Autocompleter
+		RecordSelection(prefix, word string)
+		ClickThroughRate(prefix, word string) float64
+	}{"a1": a1, "a2": a2} {
+		if got := Words(trie.Autocomplete("hel", 1)); !reflect.DeepEqual(got, []string{"hello"}) {
+			t.Errorf("%s: expected [hello] before any selection, got %v", name, got)
+		}
+		for _, word := range []string{"helmet", "helmet", "helmet", "hello"} {
+			trie.RecordSelection("hel", word)
+		}
+		if rate := trie.ClickThroughRate("hel", "helmet"); rate != 0.75 {
+			t.Errorf("%s: expected a rate of 0.75, got %v", name, rate)
+		}
+		// helmet: 0.5*0.2 + 0.5*0.75 beats hello: 0.5*0.6 + 0.5*0.25.
+		if got := Words(trie.Autocomplete("hel", 2)); !reflect.DeepEqual(got, []string{"helmet", "hello"}) {
+			t.Errorf("%s: expected [helmet hello] after the selections, got %v", name, got)
+		}
+		// Longer prefixes back off to the selections made after "hel".
+		if got := Words(trie.Autocomplete("helm", 1)); !reflect.DeepEqual(got, []string{"helmet"}) {
+			t.Errorf("%s: expected [helmet] for a longer prefix, got %v", name, got)
+		}
+	}
+}
+
+func TestClickThroughDecay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trie := buildAlg1Trie([]string{"go", "golang"})
+	trie.clicks = &clickModel{now: func() time.Time { return now }, counts: map[string]map[string]float64{}, totals: map[string]float64{}}
+	trie.WithClickThrough(1, time.Hour)
+
+	trie.RecordSelection("go", "golang")
+	now = now.Add(time.Hour)
+	trie.DecaySelections()
+	trie.RecordSelection("go", "go")
+	if rate := trie.ClickThroughRate("go", "golang"); math.Abs(rate-1.0/3) > 1e-9 {
+		t.Errorf("Expected the older selection to count half, got a rate of %v", rate)
+	}
+
+	now = now.Add(10 * time.Hour)
+	trie.DecaySelections()
+	if len(trie.clicks.counts) != 0 {
+		t.Errorf("Expected faded selections to be forgotten, got %v", trie.clicks.counts)
+	}
+
+	trie.WithClickThrough(0, 0)
+	trie.RecordSelection("go", "go")
+	if rate := trie.ClickThroughRate("go", "go"); rate != 0 {
+		t.Errorf("Expected no rates with re-ranking off, got %v", rate)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+// exampleIdeal is the expected top-3 completions of "he" in exampleCorpus,
+// based on its known frequencies and contexts.
+var exampleIdeal = []string{"hello", "helicopter", "hell"}
+
+// Measures memory usage and returns bytes allocated
+func getMemoryUsage() uint64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.Alloc
+}
+
+// runBench compares the algorithms on the example corpus or a corpus file.
+func runBench(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	corpusPath := flags.String("corpus", "", "plain-text corpus file (default: built-in example)")
+	lowercase := flags.Bool("lowercase", false, "fold the corpus to lower case")
+	prefix := flags.String("prefix", "he", "prefix to query")
+	k := flags.Int("k", 3, "number of suggestions")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	corpus, err := loadCorpus(*corpusPath, *lowercase)
+	if err != nil {
+		return err
+	}
+	var ideal []string
+	if *corpusPath == "" && *prefix == "he" && *k == 3 {
+		ideal = exampleIdeal
+	}
+	compare(corpus, *prefix, *k, ideal)
+	return nil
+}
+
+// compare builds every trie from corpus, queries each for the top k
+// completions of prefix and prints their metrics. Suggestion quality is only
+// reported when ideal, the expected completions, is known.
+func compare(corpus []string, prefix string, k int, ideal []string) {
+	// Metrics: Build tries and measure insertion time and memory
+	startMem := getMemoryUsage()
+	startTime := time.Now()
+
+	trieA1 := autocomplete.NewTrieA1()
+	for _, w := range corpus {
+		trieA1.Insert(w)
+	}
+	trieA1.BuildBigramTable(corpus)
+
+	buildTimeA1 := time.Since(startTime)
+	endMemA1 := getMemoryUsage()
+	memoryUsedA1 := endMemA1 - startMem
+
+	// Algorithm 2 build
+	startMem = getMemoryUsage()
+	startTime = time.Now()
+
+	trieA2 := autocomplete.NewTriesA2()
+	for _, w := range corpus {
+		trieA2.Insert(w)
+	}
+
+	buildTimeA2 := time.Since(startTime)
+	endMemA2 := getMemoryUsage()
+	memoryUsedA2 := endMemA2 - startMem
+
+	// Algorithm 3 build
+	startMem = getMemoryUsage()
+	startTime = time.Now()
+
+	trieA3 := autocomplete.NewTrieA3()
+	for _, w := range corpus {
+		trieA3.Insert(w)
+	}
+
+	buildTimeA3 := time.Since(startTime)
+	endMemA3 := getMemoryUsage()
+	memoryUsedA3 := endMemA3 - startMem
+
+	// Algorithm 4 build
+	startMem = getMemoryUsage()
+	startTime = time.Now()
+
+	trieA4 := autocomplete.NewTrieA4()
+	for _, w := range corpus {
+		trieA4.Insert(w)
+	}
+
+	buildTimeA4 := time.Since(startTime)
+	endMemA4 := getMemoryUsage()
+	memoryUsedA4 := endMemA4 - startMem
+
+	// Query metrics
+	// Algorithm_1 query
+	startTime = time.Now()
+	suggestionsA1 := trieA1.Autocomplete(prefix, k)
+	queryTimeA1 := time.Since(startTime)
+
+	var wordsA1 []string
+	for _, s := range suggestionsA1 {
+		wordsA1 = append(wordsA1, s.Word)
+	}
+
+	// Algorithm_2 query
+	startTime = time.Now()
+	suggestionsA2 := trieA2.AutocompleteTopK(prefix, k)
+	queryTimeA2 := time.Since(startTime)
+
+	// Algorithm_3 query
+	startTime = time.Now()
+	suggestionsA3 := autocomplete.Words(trieA3.Autocomplete(prefix, k))
+	queryTimeA3 := time.Since(startTime)
+
+	// Algorithm_4 query
+	startTime = time.Now()
+	suggestionsA4 := autocomplete.Words(trieA4.Autocomplete(prefix, k))
+	queryTimeA4 := time.Since(startTime)
+
+	qualityA1 := autocomplete.MeasureSuggestionQuality(wordsA1, ideal)
+	qualityA2 := autocomplete.MeasureSuggestionQuality(suggestionsA2, ideal)
+	qualityA3 := autocomplete.MeasureSuggestionQuality(suggestionsA3, ideal)
+	qualityA4 := autocomplete.MeasureSuggestionQuality(suggestionsA4, ideal)
+
+	// Print results
+	fmt.Println("------ Algorithm 1 (Contextual) Metrics ------")
+	fmt.Printf("Build Time: %v\n", buildTimeA1)
+	fmt.Printf("Memory Used (bytes): %d\n", memoryUsedA1)
+	fmt.Printf("Query Time: %v\n", queryTimeA1)
+	fmt.Printf("Suggestions: %v\n", wordsA1)
+	printQuality(qualityA1, ideal)
+
+	fmt.Println()
+	fmt.Println("------ Algorithm 2 (Frequency) Metrics ------")
+	fmt.Printf("Build Time: %v\n", buildTimeA2)
+	fmt.Printf("Memory Used (bytes): %d\n", memoryUsedA2)
+	fmt.Printf("Query Time: %v\n", queryTimeA2)
+	fmt.Printf("Suggestions: %v\n", suggestionsA2)
+	printQuality(qualityA2, ideal)
+
+	fmt.Println()
+	fmt.Println("------ Algorithm 3 (Radix) Metrics ------")
+	fmt.Printf("Build Time: %v\n", buildTimeA3)
+	fmt.Printf("Memory Used (bytes): %d\n", memoryUsedA3)
+	fmt.Printf("Query Time: %v\n", queryTimeA3)
+	fmt.Printf("Suggestions: %v\n", suggestionsA3)
+	printQuality(qualityA3, ideal)
+
+	fmt.Println()
+	fmt.Println("------ Algorithm 4 (Ternary Search Tree) Metrics ------")
+	fmt.Printf("Build Time: %v\n", buildTimeA4)
+	fmt.Printf("Memory Used (bytes): %d\n", memoryUsedA4)
+	fmt.Printf("Query Time: %v\n", queryTimeA4)
+	fmt.Printf("Suggestions: %v\n", suggestionsA4)
+	printQuality(qualityA4, ideal)
+}
+
+func printQuality(quality float64, ideal []string) {
+	if ideal != nil {
+		fmt.Printf("Suggestion Quality: %.2f\n", quality)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	autocomplete "auto-complete"
+	"auto-complete/corpus"
+)
+
+// -----------------------------------------
+// Configuration Files
+// -----------------------------------------
+
+// envPrefix starts the environment variables that override the
+// configuration file: AUTOCOMPLETE_CACHE_ENTRIES sets --cache-entries.
+const envPrefix = "AUTOCOMPLETE_"
+
+// parseFlags parses args into flags and then fills in every flag the command
+// line left out, first from its environment variable and then from the JSON
+// configuration file named by --config or AUTOCOMPLETE_CONFIG. The file is
+// an object whose keys are flag names, such as
+//
+//	{"algorithm": "a2", "k": 10, "smoothing": "kneser-ney:0.75",
+//	 "tokenizer": "words", "decay": "24h", "cache-entries": 10000,
+//	 "listen": ":8080", "index": ["en=en.bin", "de=de.bin"]}
+//
+// and whose values are strings, numbers, booleans or, for repeatable
+// flags, arrays of them. Keys that are no flag of the command are ignored,
+// so one file can configure every command.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	configPath := flags.String("config", "", "JSON file of flag values; AUTOCOMPLETE_* environment variables override it")
+	flags.Parse(args)
+
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	path := *configPath
+	if path == "" && !given["config"] {
+		path = os.Getenv(envPrefix + "CONFIG")
+	}
+	config, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	var failed error
+	flags.VisitAll(func(f *flag.Flag) {
+		if given[f.Name] || f.Name == "config" || failed != nil {
+			return
+		}
+		values := config[f.Name]
+		if env, ok := os.LookupEnv(envName(f.Name)); ok {
+			values = []string{env}
+		}
+		for _, value := range values {
+			if err := flags.Set(f.Name, value); err != nil {
+				failed = fmt.Errorf("setting %s to %q: %w", f.Name, value, err)
+				return
+			}
+		}
+	})
+	return failed
+}
+
+// envName returns the environment variable that sets flag name.
+func envName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// loadConfig reads the configuration file at path into the values of each
+// flag, or returns nothing when path is empty.
+func loadConfig(path string) (map[string][]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	config := make(map[string][]string, len(raw))
+	for name, value := range raw {
+		list, ok := value.([]any)
+		if !ok {
+			list = []any{value}
+		}
+		for _, item := range list {
+			switch item := item.(type) {
+			case string:
+				config[name] = append(config[name], item)
+			case json.Number:
+				config[name] = append(config[name], item.String())
+			case bool:
+				config[name] = append(config[name], strconv.FormatBool(item))
+			default:
+				return nil, fmt.Errorf("%s: %s must be a string, number, boolean or an array of them", path, name)
+			}
+		}
+	}
+	return config, nil
+}
+
+// parseTokenizer returns the tokenizer named by --tokenizer: words,
+// whitespace, shingles:N or cjk, which splits Chinese and Japanese text
+// into characters.
+func parseTokenizer(name string) (corpus.Tokenizer, error) {
+	method, param, _ := strings.Cut(name, ":")
+	switch method {
+	case "", "words":
+		return corpus.Words, nil
+	case "whitespace":
+		return corpus.Whitespace, nil
+	case "cjk":
+		return corpus.CJKCharacters(corpus.Words), nil
+	case "shingles":
+		n, err := strconv.Atoi(param)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("--tokenizer: expected shingles:N with a positive N, got %q", name)
+		}
+		return corpus.Shingles(n), nil
+	}
+	return nil, fmt.Errorf("--tokenizer: unknown tokenizer %q", name)
+}
+
+// parseSmoothing returns the smoothing named by --smoothing: none, add-k:K
+// or kneser-ney[:DISCOUNT].
+func parseSmoothing(name string) (autocomplete.Smoothing, error) {
+	method, param, hasParam := strings.Cut(name, ":")
+	value := 0.0
+	if hasParam {
+		var err error
+		if value, err = strconv.ParseFloat(param, 64); err != nil {
+			return autocomplete.Smoothing{}, fmt.Errorf("--smoothing: bad parameter in %q", name)
+		}
+	}
+	switch method {
+	case "", "none":
+		return autocomplete.Smoothing{}, nil
+	case "add-k":
+		if !hasParam {
+			return autocomplete.Smoothing{}, fmt.Errorf("--smoothing: expected add-k:K, got %q", name)
+		}
+		return autocomplete.AddK(value), nil
+	case "kneser-ney":
+		return autocomplete.KneserNey(value), nil
+	}
+	return autocomplete.Smoothing{}, fmt.Errorf("--smoothing: unknown smoothing %q", name)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	autocomplete "auto-complete"
+	"auto-complete/eval"
+)
+
+// runEval scores every algorithm on a labeled test set or, with --split, by
+// typing the held-out end of the corpus.
+func runEval(args []string) error {
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	corpusPath := flags.String("corpus", "", "plain-text corpus file to build from (default: built-in example)")
+	lowercase := flags.Bool("lowercase", false, "fold the corpus to lower case")
+	casesPath := flags.String("cases", "", "test set, one JSON {context, prefix, expected} per line")
+	split := flags.Float64("split", 0, "hold out this fraction of the corpus and type it word by word instead of using --cases")
+	contextLen := flags.Int("context", 2, "preceding words used as context with --split")
+	k := flags.Int("k", 3, "number of suggestions")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if (*casesPath == "") == (*split == 0) {
+		return errors.New("one of --cases or --split is required")
+	}
+	words, err := loadCorpus(*corpusPath, *lowercase)
+	if err != nil {
+		return err
+	}
+	var cases []eval.Case
+	if *casesPath != "" {
+		if cases, err = eval.LoadCasesFile(*casesPath); err != nil {
+			return err
+		}
+	} else {
+		var test []string
+		words, test = eval.Split(words, *split)
+		cases = eval.TypingCases(test, *contextLen)
+		fmt.Printf("Built from %d words, typing %d held-out words\n", len(words), len(test))
+	}
+
+	for _, s := range suggesters(words) {
+		fmt.Printf("%-28s %v\n", s.name, eval.Evaluate(cases, s.suggest, *k))
+	}
+	return nil
+}
+
+type namedSuggester struct {
+	name    string
+	suggest eval.Suggester
+}
+
+// suggesters builds every algorithm from corpus, in the order bench prints
+// them.
+func suggesters(corpus []string) []namedSuggester {
+	trieA1 := autocomplete.NewTrieA1()
+	trieA1.BuildFromCorpus(corpus)
+	others := []autocomplete.Autocompleter{
+		autocomplete.NewTriesA2(),
+		autocomplete.NewTrieA3(),
+		autocomplete.NewTrieA4(),
+	}
+	for _, trie := range others {
+		for _, w := range corpus {
+			trie.Insert(w)
+		}
+	}
+	return []namedSuggester{
+		{"Algorithm 1 (Contextual)", eval.FromTrieA1(trieA1)},
+		{"Algorithm 2 (Frequency)", eval.FromAutocompleter(others[0])},
+		{"Algorithm 3 (Radix)", eval.FromAutocompleter(others[1])},
+		{"Algorithm 4 (Ternary)", eval.FromAutocompleter(others[2])},
+	}
+}
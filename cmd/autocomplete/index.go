@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	autocomplete "auto-complete"
+)
+
+// runBuild builds a trie from a corpus or a word-frequency list and saves
+// it as a snapshot.
+func runBuild(args []string) error {
+	flags := flag.NewFlagSet("build", flag.ExitOnError)
+	corpusPath := flags.String("corpus", "", "plain-text corpus file")
+	var frequencies frequencyFlags
+	flags.Var(&frequencies, "frequencies", "word,count list (.csv) or word<TAB>count list (any other extension), as path or path=weight to scale its counts (repeatable)")
+	out := flags.String("out", "", "index file to write (required)")
+	algorithm := flags.String("algorithm", algorithmContextual, "a1 (contextual) or a2 (frequency)")
+	lowercase := flags.Bool("lowercase", false, "fold the corpus to lower case")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if (*corpusPath == "") == (len(frequencies) == 0) || *out == "" {
+		return errors.New("--out and one of --corpus or --frequencies are required")
+	}
+
+	var trie autocomplete.Autocompleter
+	var save func(f *os.File) error
+	switch *algorithm {
+	case algorithmContextual:
+		a1 := autocomplete.NewTrieA1()
+		trie, save = a1, func(f *os.File) error { return a1.Save(f) }
+	case algorithmFrequency:
+		a2 := autocomplete.NewTriesA2()
+		trie, save = a2, func(f *os.File) error { return a2.Save(f) }
+	default:
+		return fmt.Errorf("unknown algorithm %q", *algorithm)
+	}
+
+	summary := ""
+	if len(frequencies) > 0 {
+		for _, list := range frequencies {
+			loader := autocomplete.FrequencyLoader{
+				Comma:  '\t',
+				Weight: list.weight,
+				Progress: func(rows int, bytes int64) {
+					fmt.Fprintf(os.Stderr, "\rRead %d rows (%d MB) of %s", rows, bytes>>20, list.path)
+				},
+			}
+			if strings.HasSuffix(list.path, ".csv") {
+				loader.Comma = ','
+			}
+			err := loader.LoadFile(list.path, trie)
+			fmt.Fprintln(os.Stderr)
+			if err != nil {
+				return err
+			}
+		}
+		summary = fmt.Sprintf("%d words", trie.Len())
+	} else {
+		words, err := loadCorpus(*corpusPath, *lowercase)
+		if err != nil {
+			return err
+		}
+		if a1, ok := trie.(*autocomplete.TrieA1); ok {
+			a1.BuildFromCorpusParallel(words, 0)
+		} else {
+			for _, w := range words {
+				trie.Insert(w)
+			}
+		}
+		summary = fmt.Sprintf("%d words (%d tokens)", trie.Len(), len(words))
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Indexed %s into %s\n", summary, *out)
+	return nil
+}
+
+// frequencyFlags collects repeated --frequencies path[=weight] flags, in
+// order.
+type frequencyFlags []weightedList
+
+// weightedList is a word-frequency list and the weight of its counts.
+type weightedList struct {
+	path   string
+	weight float64
+}
+
+func (f *frequencyFlags) String() string {
+	return fmt.Sprint([]weightedList(*f))
+}
+
+func (f *frequencyFlags) Set(value string) error {
+	list := weightedList{path: value, weight: 1}
+	if i := strings.LastIndex(value, "="); i >= 0 {
+		weight, err := strconv.ParseFloat(value[i+1:], 64)
+		if err != nil || weight <= 0 || math.IsInf(weight, 0) {
+			return fmt.Errorf("expected path or path=weight with a positive weight, got %q", value)
+		}
+		list = weightedList{path: value[:i], weight: weight}
+	}
+	if list.path == "" {
+		return fmt.Errorf("expected path or path=weight, got %q", value)
+	}
+	*f = append(*f, list)
+	return nil
+}
+
+// runQuery loads a saved index and prints the suggestions for a prefix.
+func runQuery(args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	index := flags.String("index", "", "index file written by build (required)")
+	prefix := flags.String("prefix", "", "prefix to complete")
+	k := flags.Int("k", 5, "number of suggestions")
+	algorithm := flags.String("algorithm", algorithmContextual, "algorithm the index was built with: a1 or a2")
+	context := flags.String("context", "", "previous word, used by a1")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *index == "" {
+		return errors.New("--index is required")
+	}
+	trie, err := openIndex(*index, *algorithm)
+	if err != nil {
+		return err
+	}
+
+	var suggestions []autocomplete.Suggestion
+	if a1, ok := trie.(*autocomplete.TrieA1); ok {
+		suggestions = a1.AutocompleteWithContext(*context, *prefix, *k)
+	} else {
+		suggestions = trie.Autocomplete(*prefix, *k)
+	}
+	for _, s := range suggestions {
+		fmt.Println(s)
+	}
+	return nil
+}
+
+// runStats loads a saved index and prints what it holds, optionally
+// exporting every word's frequency.
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	index := flags.String("index", "", "index file written by build (required)")
+	algorithm := flags.String("algorithm", algorithmContextual, "algorithm the index was built with: a1 or a2")
+	export := flags.String("export", "", "also write word<TAB>count lines to this file")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *index == "" {
+		return errors.New("--index is required")
+	}
+	trie, err := openIndex(*index, *algorithm)
+	if err != nil {
+		return err
+	}
+
+	var stats autocomplete.TrieStats
+	var exportTo func(f *os.File) error
+	switch trie := trie.(type) {
+	case *autocomplete.TrieA1:
+		stats, exportTo = trie.Stats(), func(f *os.File) error { return trie.ExportFrequencies(f) }
+	case *autocomplete.TriesA2:
+		stats, exportTo = trie.Stats(), func(f *os.File) error { return trie.ExportFrequencies(f) }
+	}
+	fmt.Printf("Words:        %d (%d unique)\n", stats.TotalWords, stats.UniqueWords)
+	fmt.Printf("Nodes:        %d\n", stats.Nodes)
+	fmt.Printf("Max depth:    %d\n", stats.MaxDepth)
+	if *algorithm == algorithmContextual {
+		fmt.Printf("Bigrams:      %d (%d contexts)\n", stats.Bigrams, stats.BigramContexts)
+	}
+	fmt.Println("Most frequent:")
+	for _, w := range stats.TopWords {
+		fmt.Printf("  %-20s %d\n", w.Word, w.Frequency)
+	}
+
+	if *export == "" {
+		return nil
+	}
+	f, err := os.Create(*export)
+	if err != nil {
+		return err
+	}
+	if err := exportTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// openIndex loads the snapshot at path into a trie of the given algorithm.
+func openIndex(path, algorithm string) (autocomplete.Autocompleter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch algorithm {
+	case algorithmContextual:
+		trie := autocomplete.NewTrieA1()
+		return trie, trie.Load(f)
+	case algorithmFrequency:
+		trie := autocomplete.NewTriesA2()
+		return trie, trie.Load(f)
+	}
+	return nil, fmt.Errorf("unknown algorithm %q", algorithm)
+}
@@ -0,0 +1,264 @@
+// Command autocomplete builds, queries, serves and benchmarks the completion
+// algorithms of package autocomplete.
+//
+// Usage:
+//
+//	autocomplete build --corpus words.txt --out index.bin [--algorithm a1|a2] [--lowercase]
+//	autocomplete build --frequencies counts.tsv [--frequencies more.csv=0.5 ...] --out index.bin [--algorithm a1|a2]
+//	autocomplete query --index index.bin --prefix he [-k 5] [--algorithm a1|a2] [--context word]
+//	autocomplete stats --index index.bin [--algorithm a1|a2] [--export counts.tsv]
+//	autocomplete serve [--listen :8080] [--corpus words.txt] [--algorithm a1|a2] [-k 5] [--smoothing kneser-ney] [--tokenizer words] [--decay 24h] [--lowercase] [--cache-entries 10000] [--compact] [--finalize 10] [--corrections 2] [--index name=index.bin ...] [--query-log queries.jsonl] [--fold-every 1m] [--experiment a1,a2] [--experiment-percent 50] [--experiment-log ab.jsonl] [--watch 10s] [--tls-cert cert.pem --tls-key key.pem]
+//	autocomplete bench [--corpus words.txt] [--prefix he] [-k 3] [--lowercase]
+//	autocomplete eval  --cases cases.jsonl [--corpus words.txt] [-k 3] [--lowercase]
+//	autocomplete eval  --split 0.2 [--context 2] [--corpus words.txt] [-k 3] [--lowercase]
+//	autocomplete repl  [--corpus words.txt] [-k 5] [--lowercase]
+//
+// Without --corpus, serve, bench, eval and repl use a small built-in example corpus.
+//
+// Every command also accepts --config file.json, a JSON object of flag
+// values such as {"listen": ":9090", "cache-entries": 10000}, and reads
+// each flag left off the command line from its AUTOCOMPLETE_* environment
+// variable, such as AUTOCOMPLETE_CACHE_ENTRIES, before the file. Flags on
+// the command line win over both.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	autocomplete "auto-complete"
+	"auto-complete/corpus"
+	"auto-complete/server"
+)
+
+// Example Corpus
+var exampleCorpus = []string{
+	"hello", "hell", "helicopter", "hero", "world",
+	"how", "are", "you", "hello", "war", "hello",
+}
+
+// Algorithm names accepted by --algorithm; the same as the server's.
+const (
+	algorithmContextual = server.AlgorithmContextual
+	algorithmFrequency  = server.AlgorithmFrequency
+)
+
+var commands = map[string]func(args []string) error{
+	"build": runBuild,
+	"query": runQuery,
+	"stats": runStats,
+	"serve": runServe,
+	"bench": runBench,
+	"eval":  runEval,
+	"repl":  runRepl,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: autocomplete <command> [flags]
+
+Commands:
+  build   build an index from a corpus or frequency list and save it
+  query   print suggestions for a prefix from a saved index
+  stats   summarize a saved index and export its word frequencies
+  serve   serve suggestions over HTTP
+  bench   compare the algorithms' build time, memory and quality
+  eval    score the algorithms on a labeled test set or a held-out corpus split
+  repl    type interactively and watch both algorithms' suggestions
+
+Run "autocomplete <command> -h" for the flags of a command.`)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "autocomplete: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "autocomplete %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// loadCorpus tokenizes the file at path, or returns the example corpus when
+// path is empty.
+func loadCorpus(path string, lowercase bool) ([]string, error) {
+	if path == "" {
+		return exampleCorpus, nil
+	}
+	return corpus.Loader{Lowercase: lowercase}.LoadFile(path)
+}
+
+// runServe starts the HTTP server with both tries built from the corpus.
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	port := flags.Int("port", 8080, "port to listen on")
+	listen := flags.String("listen", "", "address to listen on, such as localhost:8080 (overrides --port)")
+	algorithm := flags.String("algorithm", algorithmContextual, "algorithm answering /suggest requests without one: a1 or a2")
+	k := flags.Int("k", 5, "number of suggestions for requests without k")
+	smoothing := flags.String("smoothing", "none", "a1 context smoothing: none, add-k:K or kneser-ney[:DISCOUNT]")
+	tokenizerName := flags.String("tokenizer", "words", "corpus tokenizer: words, whitespace, shingles:N or cjk")
+	decay := flags.Duration("decay", 0, "a2 frequency half-life, so recent words rank higher (0: no decay)")
+	corpusPath := flags.String("corpus", "", "plain-text corpus file (default: built-in example)")
+	lowercase := flags.Bool("lowercase", false, "fold the corpus to lower case")
+	cacheEntries := flags.Int("cache-entries", 0, "cache up to this many a1 results (0: no cache)")
+	compact := flags.Bool("compact", false, "store a1 trie nodes in sorted slices to save memory")
+	finalize := flags.Int("finalize", 0, "store the top K a1 completions at every node for faster queries, until a word is learned (0: off)")
+	corrections := flags.Int("corrections", 0, "suggest completions of prefixes up to this many typos away when a prefix has none (0-2)")
+	var indexes indexFlags
+	flags.Var(&indexes, "index", "serve the a1 index file under /indexes/name (name=path, repeatable)")
+	queryLog := flags.String("query-log", "", "record POST /select events in this file, learning from those it already holds")
+	foldEvery := flags.Duration("fold-every", time.Minute, "how often logged selections are folded into the a1 trie")
+	variants := flags.String("experiment", "", "compare two algorithms under /experiment, as a,b (e.g. a1,a2)")
+	percentB := flags.Int("experiment-percent", 50, "percentage of experiment queries answered by the second algorithm")
+	experimentLog := flags.String("experiment-log", "", "append every experiment query and selection to this file")
+	watch := flags.Duration("watch", 0, "rebuild when the corpus or an index file changes, checking this often (0: only on SIGHUP)")
+	tlsCert := flags.String("tls-cert", "", "serve HTTPS and the gRPC service with this certificate file (needs --tls-key)")
+	tlsKey := flags.String("tls-key", "", "private key file of --tls-cert")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *algorithm != algorithmContextual && *algorithm != algorithmFrequency {
+		return fmt.Errorf("unknown algorithm %q", *algorithm)
+	}
+	if (*tlsCert == "") != (*tlsKey == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	tokenizer, err := parseTokenizer(*tokenizerName)
+	if err != nil {
+		return err
+	}
+	smoothed, err := parseSmoothing(*smoothing)
+	if err != nil {
+		return err
+	}
+
+	build := func() (*autocomplete.TrieA1, *autocomplete.TriesA2, error) {
+		words := exampleCorpus
+		if *corpusPath != "" {
+			var err error
+			words, err = corpus.Loader{Lowercase: *lowercase, Tokenizer: tokenizer}.LoadFile(*corpusPath)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		trieA1 := autocomplete.NewTrieA1().WithCompactNodes(*compact).WithCorrections(*corrections)
+		trieA1.SetSmoothing(smoothed)
+		trieA1.BuildFromCorpusParallel(words, 0)
+		if *cacheEntries > 0 {
+			trieA1.SetCacheLimits(*cacheEntries, 0)
+		}
+		trieA1.Finalize(*finalize)
+		trieA2 := autocomplete.NewTriesA2().WithCorrections(*corrections)
+		for _, w := range words {
+			trieA2.Insert(w)
+		}
+		trieA2.WithDecay(*decay)
+		return trieA1, trieA2, nil
+	}
+	trieA1, trieA2, err := build()
+	if err != nil {
+		return err
+	}
+
+	srv := server.New(trieA1, trieA2).WithDefaults(*algorithm, *k)
+	if *decay > 0 {
+		defer srv.DecayEvery(*decay / 10)()
+	}
+	if len(indexes) > 0 {
+		set := autocomplete.NewIndexSet(func(t *autocomplete.TrieA1) {
+			t.WithCompactNodes(*compact).WithCorrections(*corrections)
+			if *cacheEntries > 0 {
+				t.SetCacheLimits(*cacheEntries, 0)
+			}
+		})
+		for name, path := range indexes {
+			set.Register(name, path)
+		}
+		srv.WithIndexes(set)
+	}
+	if *queryLog != "" {
+		f, err := os.OpenFile(*queryLog, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		events := srv.EnableQueryLog(24*time.Hour, f)
+		replayed, err := events.Replay(f)
+		if err != nil {
+			return err
+		}
+		log.Printf("Learned from %d selections in %d logged queries", events.Fold(), replayed)
+		defer events.FoldEvery(*foldEvery)()
+	}
+	if *variants != "" {
+		a, b, ok := strings.Cut(*variants, ",")
+		if !ok {
+			return fmt.Errorf("--experiment: expected a,b, got %q", *variants)
+		}
+		exp := server.Experiment{A: a, B: b, PercentB: *percentB}
+		if *experimentLog != "" {
+			f, err := os.OpenFile(*experimentLog, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			exp.Sink = f
+		}
+		if err := srv.EnableExperiment(exp); err != nil {
+			return err
+		}
+		log.Printf("Sending %d%% of /experiment queries to %s, the rest to %s", *percentB, b, a)
+	}
+
+	reloader := server.Reloader{Build: build, Interval: *watch}
+	if *corpusPath != "" {
+		reloader.Paths = append(reloader.Paths, *corpusPath)
+	}
+	for _, path := range indexes {
+		reloader.Paths = append(reloader.Paths, path)
+	}
+	defer srv.WatchReload(reloader)()
+
+	addr := *listen
+	if addr == "" {
+		addr = fmt.Sprintf(":%d", *port)
+	}
+	if *tlsCert != "" {
+		// TLS brings HTTP/2, which gRPC clients need.
+		log.Printf("Listening on %s with TLS, serving gRPC too", addr)
+		return http.ListenAndServeTLS(addr, *tlsCert, *tlsKey, srv)
+	}
+	log.Printf("Listening on %s", addr)
+	return http.ListenAndServe(addr, srv)
+}
+
+// indexFlags collects repeated --index name=path flags.
+type indexFlags map[string]string
+
+func (f *indexFlags) String() string {
+	return fmt.Sprint(map[string]string(*f))
+}
+
+func (f *indexFlags) Set(value string) error {
+	name, path, ok := strings.Cut(value, "=")
+	if !ok || name == "" || path == "" {
+		return fmt.Errorf("expected name=path, got %q", value)
+	}
+	if *f == nil {
+		*f = make(indexFlags)
+	}
+	(*f)[name] = path
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	autocomplete "auto-complete"
+)
+
+// Control keys understood by the REPL.
+const (
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyBackspace = 0x08
+	keyTab       = '\t'
+	keyDelete    = 0x7f
+)
+
+// repl holds the state of an interactive session: the word being typed and
+// the last completed word, which Algorithm_1 uses as context.
+type repl struct {
+	a1      *autocomplete.TrieA1
+	a2      *autocomplete.TriesA2
+	k       int
+	context string
+	word    []rune
+}
+
+// runRepl reads keystrokes and redraws both algorithms' suggestions after
+// every character. Space or enter completes the word and makes it the
+// context, tab accepts Algorithm_1's top suggestion, and Ctrl-D or Ctrl-C
+// quits.
+func runRepl(args []string) error {
+	flags := flag.NewFlagSet("repl", flag.ExitOnError)
+	corpusPath := flags.String("corpus", "", "plain-text corpus file (default: built-in example)")
+	lowercase := flags.Bool("lowercase", false, "fold the corpus to lower case")
+	k := flags.Int("k", 5, "number of suggestions")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	words, err := loadCorpus(*corpusPath, *lowercase)
+	if err != nil {
+		return err
+	}
+	r := &repl{a1: autocomplete.NewTrieA1(), a2: autocomplete.NewTriesA2(), k: *k}
+	r.a1.BuildFromCorpus(words)
+	for _, w := range words {
+		r.a2.Insert(w)
+	}
+
+	// Without a terminal (input piped in) keys arrive a line at a time,
+	// which still works, just without live redraws.
+	if restore, err := rawTerminal(); err == nil {
+		defer restore()
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	r.render(os.Stdout)
+	for {
+		key, _, err := in.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !r.handle(key) {
+			break
+		}
+		r.render(os.Stdout)
+	}
+	fmt.Println()
+	return nil
+}
+
+// handle applies one keystroke and reports whether the session goes on.
+func (r *repl) handle(key rune) bool {
+	switch {
+	case key == keyCtrlC || key == keyCtrlD:
+		return false
+	case key == keyBackspace || key == keyDelete:
+		if len(r.word) > 0 {
+			r.word = r.word[:len(r.word)-1]
+		}
+	case key == keyTab:
+		if top := r.suggestA1(); len(top) > 0 {
+			r.word = []rune(top[0].Word)
+		}
+	case key == ' ' || key == '\n' || key == '\r':
+		if len(r.word) > 0 {
+			r.context = string(r.word)
+			r.word = r.word[:0]
+		}
+	case key >= ' ':
+		r.word = append(r.word, key)
+	}
+	return true
+}
+
+func (r *repl) suggestA1() []autocomplete.Suggestion {
+	return r.a1.AutocompleteWithContext(r.context, string(r.word), r.k)
+}
+
+// render clears the screen and draws the prompt above one column of
+// suggestions per algorithm.
+func (r *repl) render(w io.Writer) {
+	a1 := r.suggestA1()
+	a2 := r.a2.Autocomplete(string(r.word), r.k)
+
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	fmt.Fprintf(&b, "context: %q\r\n\r\n", r.context)
+	fmt.Fprintf(&b, "%-32s%s\r\n", "Algorithm_1 (contextual)", "Algorithm_2 (frequency)")
+	for i := 0; i < r.k; i++ {
+		var left, right string
+		if i < len(a1) {
+			left = a1[i].String()
+		}
+		if i < len(a2) {
+			right = a2[i].String()
+		}
+		fmt.Fprintf(&b, "%-32s%s\r\n", left, right)
+	}
+	fmt.Fprintf(&b, "\r\n> %s", string(r.word))
+	io.WriteString(w, b.String())
+}
+
+// rawTerminal switches the terminal to unbuffered input without echo so each
+// key is seen as it is pressed, and returns a function restoring the
+// previous settings. It uses stty to stay within the standard library.
+func rawTerminal() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(saved)) }, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
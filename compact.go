@@ -0,0 +1,236 @@
+package autocomplete
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// -----------------------------------------
+// Compact Nodes
+// -----------------------------------------
+
+// sortedChildren holds a compact node's children as parallel slices sorted
+// by rune, searched by binary search. For the handful of children most
+// nodes have this is a fraction of the size of a map, and it is walked in
+// rune order.
+type sortedChildren struct {
+	runes []rune
+	nodes []*TrieNodeA1
+}
+
+// A node is compact when it has no children map; its children, if any, are
+// then in sorted. Nodes of both kinds can be mixed in one trie, and every
+// method below works on either.
+
+func (n *TrieNodeA1) compact() bool {
+	return n.children == nil
+}
+
+// child returns the child reached by char, or nil.
+func (n *TrieNodeA1) child(char rune) *TrieNodeA1 {
+	if !n.compact() {
+		return n.children[char]
+	}
+	if n.sorted == nil {
+		return nil
+	}
+	i, found := n.sorted.search(char)
+	if !found {
+		return nil
+	}
+	return n.sorted.nodes[i]
+}
+
+// addChild returns the child reached by char, creating it, of the same kind
+// as n, if needed.
+func (n *TrieNodeA1) addChild(char rune) *TrieNodeA1 {
+	if child := n.child(char); child != nil {
+		return child
+	}
+	if !n.compact() {
+		child := NewTrieNodeA1()
+		n.children[char] = child
+		return child
+	}
+	child := &TrieNodeA1{}
+	n.setChild(char, child)
+	return child
+}
+
+// setChild makes child the node reached by char, replacing any other.
+func (n *TrieNodeA1) setChild(char rune, child *TrieNodeA1) {
+	if !n.compact() {
+		n.children[char] = child
+		return
+	}
+	if n.sorted == nil {
+		n.sorted = &sortedChildren{}
+	}
+	s := n.sorted
+	i, found := s.search(char)
+	if found {
+		s.nodes[i] = child
+		return
+	}
+	s.runes = append(s.runes, 0)
+	copy(s.runes[i+1:], s.runes[i:])
+	s.runes[i] = char
+	s.nodes = append(s.nodes, nil)
+	copy(s.nodes[i+1:], s.nodes[i:])
+	s.nodes[i] = child
+}
+
+// removeChild drops the child reached by char, if any.
+func (n *TrieNodeA1) removeChild(char rune) {
+	if !n.compact() {
+		delete(n.children, char)
+		return
+	}
+	if n.sorted == nil {
+		return
+	}
+	s := n.sorted
+	i, found := s.search(char)
+	if !found {
+		return
+	}
+	s.runes = append(s.runes[:i], s.runes[i+1:]...)
+	s.nodes = append(s.nodes[:i], s.nodes[i+1:]...)
+	if len(s.runes) == 0 {
+		n.sorted = nil
+	}
+}
+
+// childCount returns the number of children.
+func (n *TrieNodeA1) childCount() int {
+	if !n.compact() {
+		return len(n.children)
+	}
+	if n.sorted == nil {
+		return 0
+	}
+	return len(n.sorted.runes)
+}
+
+// eachChild calls fn for every child, in rune order for compact nodes and
+// in map order otherwise.
+func (n *TrieNodeA1) eachChild(fn func(char rune, child *TrieNodeA1)) {
+	if !n.compact() {
+		for char, child := range n.children {
+			fn(char, child)
+		}
+		return
+	}
+	if n.sorted == nil {
+		return
+	}
+	for i, char := range n.sorted.runes {
+		fn(char, n.sorted.nodes[i])
+	}
+}
+
+// childRunes returns the runes of the children in ascending order.
+func (n *TrieNodeA1) childRunes() []rune {
+	if !n.compact() {
+		return sortedKeys(n.children)
+	}
+	if n.sorted == nil {
+		return nil
+	}
+	return n.sorted.runes
+}
+
+func (s *sortedChildren) search(char rune) (int, bool) {
+	i := sort.Search(len(s.runes), func(i int) bool { return s.runes[i] >= char })
+	return i, i < len(s.runes) && s.runes[i] == char
+}
+
+// newNode returns an empty node of the kind the trie is set to use.
+func (t *TrieA1) newNode() *TrieNodeA1 {
+	if t.compactNodes {
+		return &TrieNodeA1{}
+	}
+	return NewTrieNodeA1()
+}
+
+// WithCompactNodes switches every node of the trie, and every node created
+// later, to storing its children in sorted slices instead of a map. That
+// uses much less memory, keeps lookups logarithmic in the number of
+// children and walks children in rune order, at some cost to insertion
+// into nodes with many children. false switches back to maps.
+func (t *TrieA1) WithCompactNodes(compact bool) *TrieA1 {
+	if compact == t.compactNodes {
+		return t
+	}
+	t.compactNodes = compact
+	t.root = convertNodeA1(t.root, compact)
+	t.cache.clear()
+	return t
+}
+
+// convertNodeA1 returns a copy of the subtree at node whose nodes are all of
+// the given kind.
+func convertNodeA1(node *TrieNodeA1, compact bool) *TrieNodeA1 {
+	converted := *node
+	converted.children, converted.sorted = nil, nil
+	if !compact {
+		converted.children = make(map[rune]*TrieNodeA1, node.childCount())
+	}
+	for _, char := range node.childRunes() {
+		converted.setChild(char, convertNodeA1(node.child(char), compact))
+	}
+	return &converted
+}
+
+// NodeMemory estimates the memory held by a trie's nodes. Bytes is for the
+// layout in use; MapBytes and CompactBytes are for every node using maps or
+// sorted slices, so their difference is what WithCompactNodes saves.
+type NodeMemory struct {
+	Nodes        int
+	Bytes        int
+	MapBytes     int
+	CompactBytes int
+}
+
+// Estimates of the runtime's map layout: a header, then groups of eight
+// slots, each a control byte and a rune/pointer pair padded to 16 bytes,
+// filled to at most 7/8.
+const (
+	mapHeaderBytes = 48
+	mapGroupSlots  = 8
+	mapGroupBytes  = mapGroupSlots + mapGroupSlots*16
+)
+
+// NodeMemory walks the trie and estimates its node memory in both layouts.
+func (t *TrieA1) NodeMemory() NodeMemory {
+	var m NodeMemory
+	nodeBytes := int(unsafe.Sizeof(TrieNodeA1{}))
+	var walk func(*TrieNodeA1)
+	walk = func(node *TrieNodeA1) {
+		n := node.childCount()
+		mapBytes := nodeBytes + mapBytesFor(n)
+		compactBytes := nodeBytes
+		if n > 0 {
+			compactBytes += int(unsafe.Sizeof(sortedChildren{})) + n*int(unsafe.Sizeof(rune(0))+unsafe.Sizeof(node))
+		}
+		m.Nodes++
+		m.MapBytes += mapBytes
+		m.CompactBytes += compactBytes
+		if node.compact() {
+			m.Bytes += compactBytes
+		} else {
+			m.Bytes += mapBytes
+		}
+		node.eachChild(func(_ rune, child *TrieNodeA1) { walk(child) })
+	}
+	walk(t.root)
+	return m
+}
+
+func mapBytesFor(entries int) int {
+	if entries == 0 {
+		return mapHeaderBytes
+	}
+	groups := (entries*8/7 + mapGroupSlots - 1) / mapGroupSlots
+	return mapHeaderBytes + groups*mapGroupBytes
+}
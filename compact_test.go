@@ -0,0 +1,95 @@
+package autocomplete
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func sortedByWord(suggestions []Suggestion) []Suggestion {
+	sort.Slice(suggestions, func(i, j int) bool { return suggestions[i].Word < suggestions[j].Word })
+	return suggestions
+}
+
+func TestCompactNodesMatchMapNodes(t *testing.T) {
+	corpus := syntheticCorpus(3000)
+	mapped := NewTrieA1()
+	mapped.BuildFromCorpus(corpus)
+	compact := NewTrieA1().WithCompactNodes(true)
+	compact.BuildFromCorpus(corpus)
+
+	if compact.Len() != mapped.Len() {
+		t.Fatalf("Expected %d words, got %d", mapped.Len(), compact.Len())
+	}
+	for _, prefix := range []string{"", "a", "b1", "z25", "q9", "nope"} {
+		want := sortedByWord(mapped.Autocomplete(prefix, 1000))
+		got := sortedByWord(compact.Autocomplete(prefix, 1000))
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("Prefix '%s': compact nodes returned %d suggestions, expected %d", prefix, len(got), len(want))
+		}
+	}
+}
+
+func TestCompactNodesInsertDeleteAndConvert(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "help", "hero"}).WithCompactNodes(true)
+	trie.Insert("helium")
+	trie.Delete("help")
+
+	if got := Words(trie.Autocomplete("he", 5)); len(got) != 3 || trie.Contains("help") {
+		t.Errorf("Expected hello, helium and hero, got %v", got)
+	}
+	if node := trie.searchPrefix("hel"); node == nil || !node.compact() || node.childCount() != 2 {
+		t.Errorf("Expected a compact 'hel' node with 2 children after the delete")
+	}
+	if runes := trie.searchPrefix("he").childRunes(); string(runes) != "lr" {
+		t.Errorf("Expected children in rune order, got %q", string(runes))
+	}
+
+	trie.WithCompactNodes(false)
+	if trie.root.compact() || trie.searchPrefix("hel").compact() || trie.Len() != 3 {
+		t.Errorf("Expected every node back on maps")
+	}
+}
+
+func TestCompactNodesSnapshotAndParallelBuild(t *testing.T) {
+	source := buildAlg1Trie([]string{"the", "cat", "the", "car", "the", "car"})
+	var buf bytes.Buffer
+	if err := source.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewTrieA1().WithCompactNodes(true)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.searchPrefix("ca").compact() || loaded.Len() != 3 {
+		t.Errorf("Expected a compact trie with the snapshot's 3 words")
+	}
+	if got := loaded.AutocompleteWithContext("the", "ca", 1); len(got) != 1 || got[0].Word != "car" {
+		t.Errorf("Expected 'car' after 'the', got %v", got)
+	}
+
+	corpus := syntheticCorpus(2000)
+	parallel := NewTrieA1().WithCompactNodes(true)
+	parallel.BuildFromCorpusParallel(corpus, 4)
+	sequential := NewTrieA1().WithCompactNodes(true)
+	sequential.BuildFromCorpus(corpus)
+	if !reflect.DeepEqual(parallel.root, sequential.root) {
+		t.Errorf("Expected the parallel build to produce the same compact trie")
+	}
+}
+
+func TestNodeMemory(t *testing.T) {
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(syntheticCorpus(2000))
+	before := trie.NodeMemory()
+	if before.Bytes != before.MapBytes || before.CompactBytes >= before.MapBytes {
+		t.Errorf("Expected map nodes to cost more than compact ones, got %+v", before)
+	}
+
+	trie.WithCompactNodes(true)
+	after := trie.NodeMemory()
+	if after.Nodes != before.Nodes || after.Bytes != after.CompactBytes || after.MapBytes != before.MapBytes {
+		t.Errorf("Expected the same nodes now costing the compact estimate, got %+v (before %+v)", after, before)
+	}
+}
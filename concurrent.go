@@ -0,0 +1,79 @@
+package autocomplete
+
+import "sync"
+
+// -----------------------------------------
+// Concurrency-Safe Wrapper
+// -----------------------------------------
+
+// Concurrent makes any Autocompleter safe for use from multiple goroutines.
+// Queries share a read lock and run in parallel; Insert and other mutations
+// take the write lock. The wrapped trie must not be used directly while the
+// wrapper is in use.
+type Concurrent struct {
+	mu    sync.RWMutex
+	inner Autocompleter
+}
+
+// NewConcurrent wraps inner for concurrent access.
+func NewConcurrent(inner Autocompleter) *Concurrent {
+	return &Concurrent{inner: inner}
+}
+
+// Insert adds one occurrence of word under the write lock.
+func (c *Concurrent) Insert(word string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inner.Insert(word)
+}
+
+// InsertWithWeight adds weight occurrences of word under the write lock.
+func (c *Concurrent) InsertWithWeight(word string, weight int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inner.InsertWithWeight(word, weight)
+}
+
+// Autocomplete returns up to k completions of prefix under the read lock.
+func (c *Concurrent) Autocomplete(prefix string, k int) []Suggestion {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.inner.Autocomplete(prefix, k)
+}
+
+// Len returns the number of distinct words under the read lock.
+func (c *Concurrent) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.inner.Len()
+}
+
+// View runs fn with the read lock held, for queries beyond the
+// Autocompleter interface. fn must not modify the trie.
+func (c *Concurrent) View(fn func(Autocompleter)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	fn(c.inner)
+}
+
+// Update runs fn with the write lock held, for mutations beyond Insert such
+// as Delete or BuildBigramTable.
+func (c *Concurrent) Update(fn func(Autocompleter)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(c.inner)
+}
+
+// Swap replaces the wrapped trie with next and returns the previous one.
+// It takes the write lock, so queries already running finish on the old
+// trie and later ones see the new one; none is dropped. Build next before
+// calling Swap to keep the pause short.
+func (c *Concurrent) Swap(next Autocompleter) Autocompleter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous := c.inner
+	c.inner = next
+	return previous
+}
+
+var _ Autocompleter = (*Concurrent)(nil)
@@ -0,0 +1,81 @@
+package autocomplete
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Run with -race to check for data races.
+func TestConcurrentInsertAndAutocomplete(t *testing.T) {
+	for name, newTrie := range implementations() {
+		trie := NewConcurrent(newTrie())
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					trie.Insert(fmt.Sprintf("he%d-%d", w, i))
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					trie.Autocomplete("he", 5)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if trie.Len() != 800 {
+			t.Errorf("%s: expected 800 words, got %d", name, trie.Len())
+		}
+		if got := trie.Autocomplete("he", 5); len(got) != 5 {
+			t.Errorf("%s: expected 5 suggestions, got %d", name, len(got))
+		}
+	}
+}
+
+func TestConcurrentUpdateAndView(t *testing.T) {
+	trie := NewConcurrent(NewTrieA1())
+	trie.Insert("hello")
+	trie.Insert("help")
+
+	trie.Update(func(a Autocompleter) {
+		a.(*TrieA1).Delete("help")
+	})
+	trie.View(func(a Autocompleter) {
+		if a.(*TrieA1).Contains("help") {
+			t.Errorf("Expected 'help' to be deleted inside Update")
+		}
+	})
+}
+
+func TestConcurrentSwap(t *testing.T) {
+	c := NewConcurrent(buildAlg2Trie([]string{"hello"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if got := c.Autocomplete("h", 1); len(got) != 1 {
+					t.Errorf("Expected a suggestion from either trie, got %v", got)
+					return
+				}
+			}
+		}()
+	}
+	previous := c.Swap(buildAlg2Trie([]string{"help", "hero"}))
+	wg.Wait()
+
+	if previous.Len() != 1 {
+		t.Errorf("Expected the previous trie back, got %d words", previous.Len())
+	}
+	if got := Words(c.Autocomplete("he", 5)); len(got) != 2 {
+		t.Errorf("Expected the new trie's words, got %v", got)
+	}
+}
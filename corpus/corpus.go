@@ -0,0 +1,79 @@
+// Package corpus turns running text into the word sequences the autocomplete
+// tries are built from.
+package corpus
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// -----------------------------------------
+// Plain-Text Corpus Loading
+// -----------------------------------------
+
+// Loader tokenizes plain text. The zero value keeps words as written.
+type Loader struct {
+	// Lowercase folds every word to lower case, so "The" and "the" are
+	// counted as one word.
+	Lowercase bool
+
+	// Tokenizer splits each line into words; nil uses Words. Chain one
+	// with filters to stem words or drop stopwords as they are loaded.
+	Tokenizer Tokenizer
+}
+
+// LoadFile tokenizes the file at path with the default Loader.
+func LoadFile(path string) ([]string, error) {
+	return Loader{}.LoadFile(path)
+}
+
+// LoadReader tokenizes r with the default Loader.
+func LoadReader(r io.Reader) ([]string, error) {
+	return Loader{}.LoadReader(r)
+}
+
+// LoadFile tokenizes the file at path.
+func (l Loader) LoadFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return l.LoadReader(f)
+}
+
+// LoadReader splits the text read from r into words, in order, so the result
+// can be passed straight to BuildFromCorpus or to Insert and
+// BuildBigramTable. Text is tokenized a line at a time, by default with
+// Words.
+func (l Loader) LoadReader(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	var words []string
+	for {
+		line, err := br.ReadString('\n')
+		words = append(words, l.tokens(line)...)
+		if err == io.EOF {
+			return words, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// tokens splits text with the Loader's tokenizer and case folding.
+func (l Loader) tokens(text string) []string {
+	tokenizer := l.Tokenizer
+	if tokenizer == nil {
+		tokenizer = Words
+	}
+	words := tokenizer.Tokenize(text)
+	if l.Lowercase {
+		for i, w := range words {
+			words[i] = strings.ToLower(w)
+		}
+	}
+	return words
+}
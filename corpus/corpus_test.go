@@ -0,0 +1,50 @@
+package corpus
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadReader(t *testing.T) {
+	text := "Hello, world! The world's well-known -- trailing- 'quoted' café 42\nnext\tline"
+	got, err := LoadReader(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+	want := []string{"Hello", "world", "The", "world's", "well-known", "trailing", "quoted", "café", "42", "next", "line"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestLoaderLowercase(t *testing.T) {
+	got, err := Loader{Lowercase: true}.LoadReader(strings.NewReader("The cat saw THE Dog"))
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+	want := []string{"the", "cat", "saw", "the", "dog"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corpus.txt")
+	if err := os.WriteFile(path, []byte("hello hello\nhelp"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if want := []string{"hello", "hello", "help"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
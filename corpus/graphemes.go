@@ -0,0 +1,220 @@
+package corpus
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Grapheme Clusters and CJK Text
+// -----------------------------------------
+
+// graphemeClass is a rune's Grapheme_Cluster_Break property, as far as the
+// segmentation rules below need it.
+type graphemeClass uint8
+
+const (
+	gcOther graphemeClass = iota
+	gcCR
+	gcLF
+	gcControl
+	gcExtend
+	gcZWJ
+	gcSpacingMark
+	gcRegionalIndicator
+	gcL
+	gcV
+	gcT
+	gcLV
+	gcLVT
+)
+
+func classify(char rune) graphemeClass {
+	switch {
+	case char == '\r':
+		return gcCR
+	case char == '\n':
+		return gcLF
+	case char == 0x200D:
+		return gcZWJ
+	case char == 0x200C,
+		unicode.Is(unicode.Mn, char), unicode.Is(unicode.Me, char),
+		char >= 0x1F3FB && char <= 0x1F3FF, // emoji skin tone modifiers
+		char >= 0xE0020 && char <= 0xE007F: // emoji tag sequences
+		return gcExtend
+	case unicode.Is(unicode.Mc, char):
+		return gcSpacingMark
+	case unicode.IsControl(char), char == 0x2028, char == 0x2029:
+		return gcControl
+	case char >= 0x1F1E6 && char <= 0x1F1FF:
+		return gcRegionalIndicator
+	case char >= 0x1100 && char <= 0x115F, char >= 0xA960 && char <= 0xA97C:
+		return gcL
+	case char >= 0x1160 && char <= 0x11A7, char >= 0xD7B0 && char <= 0xD7C6:
+		return gcV
+	case char >= 0x11A8 && char <= 0x11FF, char >= 0xD7CB && char <= 0xD7FB:
+		return gcT
+	case char >= 0xAC00 && char <= 0xD7A3:
+		if (char-0xAC00)%28 == 0 {
+			return gcLV
+		}
+		return gcLVT
+	}
+	return gcOther
+}
+
+// isPictographic approximates Extended_Pictographic with the blocks emoji
+// are drawn from.
+func isPictographic(char rune) bool {
+	switch {
+	case char >= 0x1F000 && char <= 0x1FAFF,
+		char >= 0x2600 && char <= 0x27BF,
+		char >= 0x2300 && char <= 0x23FF,
+		char >= 0x2B00 && char <= 0x2BFF,
+		char >= 0x2190 && char <= 0x21FF,
+		char == 0xA9, char == 0xAE, char == 0x203C, char == 0x2049,
+		char == 0x2122, char == 0x2139, char == 0x3030, char == 0x303D,
+		char == 0x3297, char == 0x3299:
+		return true
+	}
+	return false
+}
+
+// graphemeState is what the segmentation rules need to remember about the
+// cluster so far.
+type graphemeState struct {
+	prev graphemeClass
+	// regional counts the regional indicators ending the cluster, so flags
+	// pair up.
+	regional int
+	// emoji is set after a pictograph and any extending runes, and zwj
+	// after such an emoji is followed by a zero width joiner.
+	emoji, zwj bool
+}
+
+// breaks reports whether a cluster boundary falls before char, following
+// the extended grapheme cluster rules of Unicode Standard Annex #29, and
+// moves the state past char.
+func (s *graphemeState) breaks(char rune, first bool) bool {
+	class := classify(char)
+	prev := s.prev
+	boundary := true
+	switch {
+	case first:
+	case prev == gcCR && class == gcLF:
+		boundary = false
+	case prev == gcCR || prev == gcLF || prev == gcControl:
+	case class == gcCR || class == gcLF || class == gcControl:
+	case prev == gcL && (class == gcL || class == gcV || class == gcLV || class == gcLVT),
+		(prev == gcLV || prev == gcV) && (class == gcV || class == gcT),
+		(prev == gcLVT || prev == gcT) && class == gcT:
+		boundary = false
+	case class == gcExtend || class == gcZWJ || class == gcSpacingMark:
+		boundary = false
+	case s.zwj && isPictographic(char):
+		boundary = false
+	case class == gcRegionalIndicator && s.regional%2 == 1:
+		boundary = false
+	}
+
+	switch {
+	case isPictographic(char):
+		s.emoji, s.zwj = true, false
+	case class == gcExtend && s.emoji && !boundary:
+	case class == gcZWJ && s.emoji && !boundary:
+		s.zwj = true
+	default:
+		s.emoji, s.zwj = false, false
+	}
+	if class == gcRegionalIndicator {
+		s.regional++
+	} else {
+		s.regional = 0
+	}
+	s.prev = class
+	return boundary
+}
+
+// Graphemes splits text into its user-perceived characters: a base letter
+// with its combining marks, an emoji with its modifiers and joined emoji, a
+// flag's pair of regional indicators or a Hangul syllable's jamo.
+func Graphemes(text string) []string {
+	var clusters []string
+	var s graphemeState
+	start := 0
+	for i, char := range text {
+		if s.breaks(char, i == 0) && i > 0 {
+			clusters = append(clusters, text[start:i])
+			start = i
+		}
+	}
+	if start < len(text) {
+		clusters = append(clusters, text[start:])
+	}
+	return clusters
+}
+
+// IsGraphemeBoundary reports whether byte offset i of text falls between two
+// grapheme clusters. The start and end of text are boundaries; offsets
+// inside a rune are not.
+func IsGraphemeBoundary(text string, i int) bool {
+	if i <= 0 || i >= len(text) {
+		return i == 0 || i == len(text)
+	}
+	if !utf8.RuneStart(text[i]) {
+		return false
+	}
+	var s graphemeState
+	for j, char := range text {
+		if s.breaks(char, j == 0) && j == i {
+			return true
+		}
+		if j >= i {
+			return false
+		}
+	}
+	return false
+}
+
+// isCJK reports whether char belongs to a script written without spaces
+// between words. Korean is left out, since it is spaced.
+func isCJK(char rune) bool {
+	return unicode.In(char, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// CJKCharacters returns a Tokenizer that splits text with tokenizer and then
+// breaks every run of Chinese or Japanese characters in a token into one
+// token per character, so text written without spaces is indexed character
+// by character and the bigram table learns which character follows which.
+// Other text is left as tokenizer split it.
+func CJKCharacters(tokenizer Tokenizer) Tokenizer {
+	return TokenizerFunc(func(text string) []string {
+		var tokens []string
+		for _, token := range tokenizer.Tokenize(text) {
+			tokens = append(tokens, splitCJK(token)...)
+		}
+		return tokens
+	})
+}
+
+// splitCJK returns the runs of non-CJK clusters in token whole and every
+// CJK cluster alone.
+func splitCJK(token string) []string {
+	var parts []string
+	start, offset := 0, 0
+	for _, cluster := range Graphemes(token) {
+		first, _ := utf8.DecodeRuneInString(cluster)
+		if isCJK(first) {
+			if offset > start {
+				parts = append(parts, token[start:offset])
+			}
+			parts = append(parts, cluster)
+			start = offset + len(cluster)
+		}
+		offset += len(cluster)
+	}
+	if offset > start {
+		parts = append(parts, token[start:offset])
+	}
+	return parts
+}
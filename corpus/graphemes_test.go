@@ -0,0 +1,43 @@
+package corpus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"café", []string{"c", "a", "f", "é"}},
+		{"👩\u200d💻!", []string{"👩\u200d💻", "!"}},
+		{"👍🏽👍", []string{"👍🏽", "👍"}},
+		{"🇫🇷🇩🇪🇮", []string{"🇫🇷", "🇩🇪", "🇮"}},
+		{"한가", []string{"한", "가"}},
+		{"a\r\nb", []string{"a", "\r\n", "b"}},
+		{"", nil},
+	}
+	for _, test := range tests {
+		if got := Graphemes(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("For %q: expected %q, got %q", test.text, test.want, got)
+		}
+	}
+}
+
+func TestIsGraphemeBoundary(t *testing.T) {
+	text := "e\u0301t"
+	for i, want := range []bool{true, false, false, true, true} {
+		if got := IsGraphemeBoundary(text, i); got != want {
+			t.Errorf("Expected IsGraphemeBoundary(%q, %d) to be %v, got %v", text, i, want, got)
+		}
+	}
+}
+
+func TestCJKCharacters(t *testing.T) {
+	got := CJKCharacters(Words).Tokenize("我喜欢Go语言, hello world")
+	want := []string{"我", "喜", "欢", "Go", "语", "言", "hello", "world"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
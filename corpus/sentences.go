@@ -0,0 +1,166 @@
+package corpus
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Sentence Splitting
+// -----------------------------------------
+
+// abbreviations end in a period that does not end a sentence.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true,
+	"jr": true, "st": true, "vs": true, "etc": true, "e.g": true, "i.e": true,
+	"no": true, "inc": true, "ltd": true, "co": true, "fig": true, "approx": true,
+}
+
+func isTerminator(char rune) bool {
+	return strings.ContainsRune(".!?…。！？", char)
+}
+
+// isCloser reports whether char may follow a terminator within the sentence
+// it ends, such as the quote in `"Stop!" she said`.
+func isCloser(char rune) bool {
+	return strings.ContainsRune(`"')]}»”’」』`, char)
+}
+
+// Sentences splits text into sentences, trimmed of surrounding white space.
+// A sentence ends at a blank line or at a run of terminal punctuation
+// (. ! ? … and their full-width forms), with any closing quotes or
+// brackets, that is followed by white space and not by a lower-case word.
+// A period after a common abbreviation such as "Dr." or "e.g." or after a
+// single-letter initial does not end a sentence. Full-width terminators
+// end one even with no space after them, as Chinese and Japanese text has
+// none.
+func Sentences(text string) []string {
+	var sentences []string
+	start := 0
+	add := func(end int) {
+		if s := strings.TrimSpace(text[start:end]); s != "" {
+			sentences = append(sentences, s)
+		}
+		start = end
+	}
+
+	for i := 0; i < len(text); {
+		char, size := utf8.DecodeRuneInString(text[i:])
+		if char == '\n' {
+			if j := skipSpace(text, i+size, false); j < len(text) && text[j] == '\n' {
+				add(i)
+				i = j
+				continue
+			}
+		}
+		if !isTerminator(char) {
+			i += size
+			continue
+		}
+
+		end := i
+		for end < len(text) {
+			c, n := utf8.DecodeRuneInString(text[end:])
+			if !isTerminator(c) && !isCloser(c) {
+				break
+			}
+			end += n
+		}
+		if endsSentence(text, i, end) {
+			add(end)
+		}
+		i = end
+	}
+	add(len(text))
+	return sentences
+}
+
+// endsSentence reports whether the terminators at text[at:end] end a
+// sentence.
+func endsSentence(text string, at, end int) bool {
+	if char, _ := utf8.DecodeRuneInString(text[at:]); char >= 0x3000 {
+		return true
+	}
+	next := skipSpace(text, end, true)
+	if next == end && end < len(text) {
+		return false // "3.14", "example.com"
+	}
+	if next < len(text) {
+		if c, _ := utf8.DecodeRuneInString(text[next:]); unicode.IsLower(c) {
+			return false
+		}
+	}
+	if text[at] != '.' {
+		return true
+	}
+
+	word := text[:at]
+	if i := strings.LastIndexFunc(word, func(c rune) bool { return !unicode.IsLetter(c) && c != '.' }); i >= 0 {
+		word = word[i+1:]
+	}
+	if utf8.RuneCountInString(word) == 1 && unicode.IsUpper([]rune(word)[0]) {
+		return false
+	}
+	return !abbreviations[strings.ToLower(word)]
+}
+
+// skipSpace returns the offset of the first rune from i on that is not
+// white space, stopping at a newline unless newlines is set.
+func skipSpace(text string, i int, newlines bool) int {
+	for i < len(text) {
+		c, n := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(c) || (c == '\n' && !newlines) {
+			break
+		}
+		i += n
+	}
+	return i
+}
+
+// LoadSentencesFile splits the file at path into tokenized sentences.
+func (l Loader) LoadSentencesFile(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return l.LoadSentences(f)
+}
+
+// LoadSentences splits the text read from r into sentences, as Sentences
+// does, and each sentence into words, as LoadReader does, for
+// BuildFromSentences or BuildBigramTableFromSentences. Sentences never
+// span a blank line, so text is read a paragraph at a time. Sentences with
+// no words are left out.
+func (l Loader) LoadSentences(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+	var sentences [][]string
+	var paragraph strings.Builder
+	flush := func() {
+		for _, sentence := range Sentences(paragraph.String()) {
+			if words := l.tokens(sentence); len(words) > 0 {
+				sentences = append(sentences, words)
+			}
+		}
+		paragraph.Reset()
+	}
+	for {
+		line, err := br.ReadString('\n')
+		if strings.TrimSpace(line) == "" {
+			flush()
+		} else {
+			paragraph.WriteString(line)
+		}
+		if err == io.EOF {
+			flush()
+			return sentences, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package corpus
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSentences(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"It rained. We stayed in!  Did you?", []string{"It rained.", "We stayed in!", "Did you?"}},
+		{"Dr. Smith met J. Doe at 3.30 p.m. on Monday. Then he left.", []string{"Dr. Smith met J. Doe at 3.30 p.m. on Monday.", "Then he left."}},
+		{`"Stop!" She ran... and hid.`, []string{`"Stop!"`, "She ran... and hid."}},
+		{"A title\n\nThe body\ncontinues here", []string{"A title", "The body\ncontinues here"}},
+		{"今天很好。我们走吧！", []string{"今天很好。", "我们走吧！"}},
+		{"  ", nil},
+	}
+	for _, test := range tests {
+		if got := Sentences(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("For %q: expected %q, got %q", test.text, test.want, got)
+		}
+	}
+}
+
+func TestLoadSentences(t *testing.T) {
+	text := "The cat sat. The dog ran\naway.\n\nNew paragraph\n"
+	got, err := Loader{Lowercase: true}.LoadSentences(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("LoadSentences failed: %v", err)
+	}
+	want := [][]string{{"the", "cat", "sat"}, {"the", "dog", "ran", "away"}, {"new", "paragraph"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
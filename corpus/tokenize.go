@@ -0,0 +1,163 @@
+package corpus
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Tokenizers and Token Filters
+// -----------------------------------------
+
+// Tokenizer splits text into tokens, in order.
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// TokenizerFunc adapts a function to a Tokenizer.
+type TokenizerFunc func(text string) []string
+
+// Tokenize calls f(text).
+func (f TokenizerFunc) Tokenize(text string) []string {
+	return f(text)
+}
+
+// Whitespace splits text at runs of white space and keeps everything else,
+// punctuation included.
+var Whitespace Tokenizer = TokenizerFunc(strings.Fields)
+
+// Words splits text at word boundaries: a word is a run of letters, digits
+// and combining marks, and an apostrophe or hyphen between two such
+// characters stays part of the word, as in "don't" or "well-known".
+// Everything else separates words. It is the Loader's default.
+var Words Tokenizer = TokenizerFunc(splitWords)
+
+func splitWords(text string) []string {
+	var words []string
+	start, end := -1, 0
+	pendingJoiner := false
+	for i, char := range text {
+		switch {
+		case isWordRune(char):
+			if start < 0 {
+				start = i
+			}
+			pendingJoiner = false
+			end = i + utf8.RuneLen(char)
+		case isJoiner(char) && start >= 0 && !pendingJoiner:
+			pendingJoiner = true
+		default:
+			if start >= 0 {
+				words = append(words, text[start:end])
+			}
+			start, pendingJoiner = -1, false
+		}
+	}
+	if start >= 0 {
+		words = append(words, text[start:end])
+	}
+	return words
+}
+
+// Regexp returns a Tokenizer whose tokens are the non-overlapping matches of
+// pattern, such as `#?\w+` to keep hashtags whole.
+func Regexp(pattern *regexp.Regexp) Tokenizer {
+	return TokenizerFunc(func(text string) []string {
+		return pattern.FindAllString(text, -1)
+	})
+}
+
+// Shingles returns a Tokenizer that splits text at white space and then
+// each word into its overlapping runs of n runes, so "hello" with n=3 gives
+// "hel", "ell" and "llo". Words shorter than n are kept whole. A
+// non-positive n keeps every word whole.
+func Shingles(n int) Tokenizer {
+	return TokenizerFunc(func(text string) []string {
+		var shingles []string
+		for _, word := range strings.Fields(text) {
+			runes := []rune(word)
+			if n <= 0 || len(runes) <= n {
+				shingles = append(shingles, word)
+				continue
+			}
+			for i := 0; i+n <= len(runes); i++ {
+				shingles = append(shingles, string(runes[i:i+n]))
+			}
+		}
+		return shingles
+	})
+}
+
+// TokenFilter rewrites one token, or drops it by returning false.
+type TokenFilter func(token string) (string, bool)
+
+// Lowercase folds tokens to lower case.
+func Lowercase(token string) (string, bool) {
+	return strings.ToLower(token), true
+}
+
+// Stem rewrites tokens with stem, such as EnglishStem, and drops those it
+// stems to nothing.
+func Stem(stem func(string) string) TokenFilter {
+	return func(token string) (string, bool) {
+		token = stem(token)
+		return token, token != ""
+	}
+}
+
+// Stopwords drops the given words. Matching is exact, so put it after
+// Lowercase to drop "The" as well as "the".
+func Stopwords(words []string) TokenFilter {
+	stop := make(map[string]bool, len(words))
+	for _, w := range words {
+		stop[w] = true
+	}
+	return func(token string) (string, bool) {
+		return token, !stop[token]
+	}
+}
+
+// EnglishStem is Harman's S stemmer: it reduces regular English plurals to
+// their singular, "queries" to "query" and "cats" to "cat", and leaves
+// everything else alone. It expects lower-case input.
+func EnglishStem(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && !strings.HasSuffix(word, "eies") && !strings.HasSuffix(word, "aies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "es") && !strings.HasSuffix(word, "aes") && !strings.HasSuffix(word, "ees") && !strings.HasSuffix(word, "oes"):
+		return word[:len(word)-1]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// Chain returns a Tokenizer that splits text with tokenizer and passes every
+// token through filters in order, dropping it as soon as one does.
+func Chain(tokenizer Tokenizer, filters ...TokenFilter) Tokenizer {
+	return TokenizerFunc(func(text string) []string {
+		tokens := tokenizer.Tokenize(text)
+		kept := tokens[:0]
+	next:
+		for _, token := range tokens {
+			for _, filter := range filters {
+				var ok bool
+				if token, ok = filter(token); !ok {
+					continue next
+				}
+			}
+			kept = append(kept, token)
+		}
+		return kept
+	})
+}
+
+func isWordRune(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsDigit(char) || unicode.Is(unicode.Mn, char)
+}
+
+func isJoiner(char rune) bool {
+	return char == '\'' || char == '’' || char == '-'
+}
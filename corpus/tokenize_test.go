@@ -0,0 +1,65 @@
+package corpus
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTokenizers(t *testing.T) {
+	text := "Don't panic -- #golang rocks!"
+	tests := []struct {
+		name      string
+		tokenizer Tokenizer
+		want      []string
+	}{
+		{"whitespace", Whitespace, []string{"Don't", "panic", "--", "#golang", "rocks!"}},
+		{"words", Words, []string{"Don't", "panic", "golang", "rocks"}},
+		{"regexp", Regexp(regexp.MustCompile(`#?\w+`)), []string{"Don", "t", "panic", "#golang", "rocks"}},
+		{"shingles", Shingles(4), []string{"Don'", "on't", "pani", "anic", "--", "#gol", "gola", "olan", "lang", "rock", "ocks", "cks!"}},
+	}
+	for _, test := range tests {
+		if got := test.tokenizer.Tokenize(text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("For %s: expected %q, got %q", test.name, test.want, got)
+		}
+	}
+}
+
+func TestChainFilters(t *testing.T) {
+	tokenizer := Chain(Words, Lowercase, Stopwords([]string{"the", "of"}), Stem(EnglishStem))
+	got := tokenizer.Tokenize("The Queries of the cats and Dogs")
+	want := []string{"query", "cat", "and", "dog"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestEnglishStem(t *testing.T) {
+	tests := map[string]string{
+		"queries": "query",
+		"cats":    "cat",
+		"boxes":   "boxe",
+		"toes":    "toe",
+		"bus":     "bus",
+		"glass":   "glass",
+		"cat":     "cat",
+	}
+	for word, want := range tests {
+		if got := EnglishStem(word); got != want {
+			t.Errorf("Expected %q to stem to %q, got %q", word, want, got)
+		}
+	}
+}
+
+func TestLoaderTokenizer(t *testing.T) {
+	loader := Loader{Tokenizer: Chain(Whitespace, Stopwords([]string{"--"})), Lowercase: true}
+	got, err := loader.LoadReader(strings.NewReader("C++ -- rocks\nGo's fine"))
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+	want := []string{"c++", "rocks", "go's", "fine"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
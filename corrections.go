@@ -0,0 +1,151 @@
+package autocomplete
+
+// -----------------------------------------
+// Spelling Corrections
+// -----------------------------------------
+
+// maxCorrectionEdits is the most edits WithCorrections accepts.
+const maxCorrectionEdits = 2
+
+// WithCorrections makes queries whose prefix no word starts with return
+// "did you mean" suggestions instead of nothing: the completions of the
+// nearest prefixes in the trie, at most maxEdits typos away, each with
+// CorrectedPrefix set to the prefix it completes. Only one edit is allowed
+// per three runes of the query, so short prefixes are not corrected into
+// unrelated words. maxEdits is capped at 2; zero turns corrections off.
+func (t *TrieA1) WithCorrections(maxEdits int) *TrieA1 {
+	t.maxCorrections = min(max(maxEdits, 0), maxCorrectionEdits)
+	t.cache.clear()
+	return t
+}
+
+// WithCorrections makes queries whose prefix no word starts with return
+// "did you mean" suggestions, as TrieA1.WithCorrections does.
+func (t *TriesA2) WithCorrections(maxEdits int) *TriesA2 {
+	t.maxCorrections = min(max(maxEdits, 0), maxCorrectionEdits)
+	return t
+}
+
+// correction is a trie node whose path is among the nearest to a prefix
+// that has no completions of its own.
+type correction[N any] struct {
+	node N
+	path []rune
+}
+
+// nearestPrefixes returns the shallowest nodes under root whose paths are at
+// the smallest edit distance from prefix that any path reaches, if that is
+// within maxEdits and one edit per three runes of prefix. eachChild lists a
+// node's children.
+func nearestPrefixes[N any](root N, eachChild func(N, func(rune, N)), prefix []rune, maxEdits int) []correction[N] {
+	maxEdits = min(maxEdits, len(prefix)/3)
+	if maxEdits == 0 {
+		return nil
+	}
+
+	var found []correction[N]
+	var distances []int
+	best := maxEdits
+
+	var dfs func(node N, path []rune, prev, prevPrev []int)
+	dfs = func(node N, path []rune, prev, prevPrev []int) {
+		if d := prev[len(prefix)]; len(path) > 0 && d <= best {
+			best = d
+			found = append(found, correction[N]{node, append([]rune(nil), path...)})
+			distances = append(distances, d)
+		}
+		if minInts(prev) > best {
+			return
+		}
+		eachChild(node, func(char rune, child N) {
+			dfs(child, append(path, char), nextEditRow(prefix, path, char, prev, prevPrev), prev)
+		})
+	}
+	firstRow := make([]int, len(prefix)+1)
+	for j := range firstRow {
+		firstRow[j] = j
+	}
+	dfs(root, nil, firstRow, nil)
+
+	// Keep the nearest, dropping any below another kept one since its
+	// completions are already included.
+	kept := make(map[string]bool)
+	var nearest []correction[N]
+	for i, c := range found {
+		if distances[i] != best || hasKeptAncestor(kept, c.path) {
+			continue
+		}
+		kept[string(c.path)] = true
+		nearest = append(nearest, c)
+	}
+	return nearest
+}
+
+func hasKeptAncestor(kept map[string]bool, path []rune) bool {
+	for i := 1; i < len(path); i++ {
+		if kept[string(path[:i])] {
+			return true
+		}
+	}
+	return false
+}
+
+// markCorrections sets CorrectedPrefix on every suggestion from the prefix
+// of the correction it came from.
+func markCorrections(suggestions []Suggestion, prefixOf map[string]string) {
+	for i := range suggestions {
+		suggestions[i].CorrectedPrefix = prefixOf[suggestions[i].Word]
+	}
+}
+
+// correct answers a query whose prefix has no completions from the nearest
+// prefixes, or returns nil when corrections are off or none are near.
+func (t *TrieA1) correct(prefix []rune, prefixStr string, q query, k int) []Suggestion {
+	if t.maxCorrections == 0 {
+		return nil
+	}
+	eachChild := func(n *TrieNodeA1, fn func(rune, *TrieNodeA1)) { n.eachChild(fn) }
+	var completions []Suggestion
+	prefixOf := make(map[string]string)
+	for _, c := range nearestPrefixes(t.root, eachChild, prefix, t.maxCorrections) {
+		collected, _ := t.collectCompletionsLimit(c.node, c.path, t.maxScan, t.options.depth(), q.cancel)
+		for _, s := range collected {
+			prefixOf[s.Word] = string(c.path)
+		}
+		completions = append(completions, collected...)
+	}
+
+	ranked := topK(t.scoreCandidates(prefixStr, q, completions, nil), t.options.limit(k))
+	t.roundProbabilities(ranked)
+	markCorrections(ranked, prefixOf)
+	return ranked
+}
+
+// correct answers a query whose prefix has no completions from the nearest
+// prefixes, or returns nil when corrections are off or none are near.
+func (t *TriesA2) correct(prefix string, q query, k int) []Suggestion {
+	if t.maxCorrections == 0 {
+		return nil
+	}
+	eachChild := func(n *NodeA2, fn func(rune, *NodeA2)) {
+		for char, child := range n.children {
+			fn(char, child)
+		}
+	}
+	var entries []Suggestion
+	prefixOf := make(map[string]string)
+	for _, c := range nearestPrefixes(t.root, eachChild, []rune(prefix), t.maxCorrections) {
+		var collected []Suggestion
+		if !collectEntriesA2Cancel(c.node, string(c.path), &collected, t.options.depth(), q.cancel) {
+			return nil
+		}
+		for _, s := range collected {
+			prefixOf[s.Word] = string(c.path)
+		}
+		entries = append(entries, collected...)
+	}
+
+	ranked := topK(t.scoreEntries(prefix, q, entries), t.options.limit(k))
+	markCorrections(ranked, prefixOf)
+	return ranked
+}
@@ -0,0 +1,49 @@
+package autocomplete
+
+import "testing"
+
+func TestCorrectionsA1(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hell", "hell", "help", "world"}
+	trie := buildAlg1Trie(corpus)
+	if got := trie.Autocomplete("helo", 3); len(got) != 0 {
+		t.Errorf("Expected no corrections unless enabled, got %v", got)
+	}
+
+	trie.WithCorrections(2)
+	got := trie.Autocomplete("helo", 3)
+	want := []string{"hello", "hell", "help"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i, s := range got {
+		if s.Word != want[i] || s.CorrectedPrefix != "hel" {
+			t.Errorf("Suggestion %d: expected %s corrected from 'hel', got %+v", i, want[i], s)
+		}
+	}
+
+	// The nearest prefix wins: "hxllo" is one edit from "hello" but two
+	// from "hell".
+	if got := trie.Autocomplete("hxllo", 3); len(got) != 1 || got[0].Word != "hello" || got[0].CorrectedPrefix != "hello" {
+		t.Errorf("Expected only 'hello' corrected from 'hello', got %+v", got)
+	}
+	if got := trie.Autocomplete("hx", 3); len(got) != 0 {
+		t.Errorf("Expected short prefixes not to be corrected, got %v", got)
+	}
+	if got := trie.Autocomplete("he", 1); len(got) != 1 || got[0].CorrectedPrefix != "" {
+		t.Errorf("Expected exact completions not to be flagged, got %+v", got)
+	}
+}
+
+func TestCorrectionsA2(t *testing.T) {
+	trie := buildAlg2Trie([]string{"world", "world", "word", "war"}).WithCorrections(1)
+
+	got := trie.Autocomplete("wrld", 5)
+	if len(got) != 1 || got[0].Word != "world" || got[0].CorrectedPrefix != "world" || got[0].Score != 1 {
+		t.Errorf("Expected 'world' corrected from 'world', got %+v", got)
+	}
+
+	trie.SetBlocklist([]string{"world"})
+	if got := trie.Autocomplete("wrld", 5); len(got) != 0 {
+		t.Errorf("Expected corrections to respect the blocklist, got %v", got)
+	}
+}
@@ -0,0 +1,183 @@
+package autocomplete
+
+import (
+	"encoding/binary"
+	"sort"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// DAWG: Frozen Read-Only Index
+// -----------------------------------------
+
+// DAWG is an immutable, minimized form of an Algorithm_2 trie: identical
+// subtrees, most often shared suffixes such as "-ing" or "-tion", are stored
+// once. Frequencies cannot live on shared nodes, so every word gets its
+// position in alphabetical order, computed from per-node word counts while
+// walking, and frequencies are kept in one slice indexed by that position.
+//
+// A DAWG answers the same queries as the trie it was frozen from but can
+// never change; build a new one to add words.
+type DAWG struct {
+	nodes       []dawgNode
+	edges       []dawgEdge
+	frequencies []int
+	root        int32
+}
+
+// dawgNode's outgoing edges are edges[first : first+n], sorted by rune.
+type dawgNode struct {
+	first int32
+	n     int32
+	words int32 // words ending at or below this node
+	isEnd bool
+}
+
+type dawgEdge struct {
+	char rune
+	to   int32
+}
+
+// Freeze builds a DAWG holding the trie's words and all-time frequencies,
+// leaving out the words its blocklist and filter hide. The trie is left
+// unchanged and can keep being updated independently.
+func (t *TriesA2) Freeze() *DAWG {
+	d := &DAWG{}
+	registry := make(map[string]int32)
+	var key []byte
+
+	// Children are frozen before their parent, so equal subtrees already
+	// share an id and a node is identified by its flag and its edges.
+	var freeze func(node *NodeA2, path string) int32
+	freeze = func(node *NodeA2, path string) int32 {
+		chars := sortedKeys(node.children)
+		targets := make([]int32, len(chars))
+		for i, char := range chars {
+			targets[i] = freeze(node.children[char], path+string(char))
+		}
+
+		isEnd := node.isEndOfWord && t.filter.allows(path)
+		key = key[:0]
+		if isEnd {
+			key = append(key, 1)
+		} else {
+			key = append(key, 0)
+		}
+		for i, char := range chars {
+			key = binary.AppendVarint(key, int64(char))
+			key = binary.AppendUvarint(key, uint64(targets[i]))
+		}
+		if id, ok := registry[string(key)]; ok {
+			return id
+		}
+
+		n := dawgNode{first: int32(len(d.edges)), n: int32(len(chars)), isEnd: isEnd}
+		if n.isEnd {
+			n.words = 1
+		}
+		for i, char := range chars {
+			d.edges = append(d.edges, dawgEdge{char: char, to: targets[i]})
+			n.words += d.nodes[targets[i]].words
+		}
+		id := int32(len(d.nodes))
+		d.nodes = append(d.nodes, n)
+		registry[string(key)] = id
+		return id
+	}
+	d.root = freeze(t.root, "")
+
+	// Word positions follow the same order: a word before its extensions,
+	// children by rune.
+	var number func(node *NodeA2, path string)
+	number = func(node *NodeA2, path string) {
+		if node.isEndOfWord && t.filter.allows(path) {
+			d.frequencies = append(d.frequencies, node.frequency)
+		}
+		for _, char := range sortedKeys(node.children) {
+			number(node.children[char], path+string(char))
+		}
+	}
+	number(t.root, "")
+	return d
+}
+
+// Len returns the number of distinct words in the DAWG.
+func (d *DAWG) Len() int {
+	return len(d.frequencies)
+}
+
+// Autocomplete returns up to k completions of prefix ranked by frequency,
+// with each word's share of the candidates' total frequency as its
+// probability, exactly as Algorithm_2 does.
+func (d *DAWG) Autocomplete(prefix string, k int) []Suggestion {
+	return dawgAutocomplete(d, d.root, prefix, k)
+}
+
+func (d *DAWG) node(i int32) dawgNode   { return d.nodes[i] }
+func (d *DAWG) edge(i int32) dawgEdge   { return d.edges[i] }
+func (d *DAWG) frequency(index int) int { return d.frequencies[index] }
+
+// dawgStore gives read access to a DAWG's arrays wherever they live: in Go
+// slices for a DAWG, in a mapped file for a MappedIndex.
+type dawgStore interface {
+	node(i int32) dawgNode
+	edge(i int32) dawgEdge
+	frequency(index int) int
+}
+
+func dawgAutocomplete(d dawgStore, root int32, prefix string, k int) []Suggestion {
+	node, index := root, 0
+	for _, char := range prefix {
+		next, skipped, ok := dawgStep(d, node, char)
+		if !ok {
+			return nil
+		}
+		node, index = next, index+skipped
+	}
+
+	var entries []Suggestion
+	dawgCollect(d, node, []byte(prefix), index, &entries)
+
+	total := 0
+	for _, e := range entries {
+		total += e.Frequency
+	}
+	for i := range entries {
+		entries[i].Score = float64(entries[i].Frequency) / float64(total)
+	}
+	return topK(entries, k)
+}
+
+// dawgStep follows the edge labelled char out of node and reports how many
+// word positions it skips: the node's own word and those below earlier
+// siblings.
+func dawgStep(d dawgStore, node int32, char rune) (int32, int, bool) {
+	n := d.node(node)
+	i := int32(sort.Search(int(n.n), func(i int) bool { return d.edge(n.first+int32(i)).char >= char }))
+	if i == n.n || d.edge(n.first+i).char != char {
+		return 0, 0, false
+	}
+	skipped := 0
+	if n.isEnd {
+		skipped++
+	}
+	for j := int32(0); j < i; j++ {
+		skipped += int(d.node(d.edge(n.first + j).to).words)
+	}
+	return d.edge(n.first + i).to, skipped, true
+}
+
+// dawgCollect gathers every word under node, whose first word has position
+// index.
+func dawgCollect(d dawgStore, node int32, path []byte, index int, results *[]Suggestion) {
+	n := d.node(node)
+	if n.isEnd {
+		*results = append(*results, Suggestion{Word: string(path), Frequency: d.frequency(index)})
+		index++
+	}
+	for j := n.first; j < n.first+n.n; j++ {
+		e := d.edge(j)
+		dawgCollect(d, e.to, utf8.AppendRune(path, e.char), index, results)
+		index += int(d.node(e.to).words)
+	}
+}
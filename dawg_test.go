@@ -0,0 +1,55 @@
+package autocomplete
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestFreezeSharesSuffixes(t *testing.T) {
+	trie := buildAlg2Trie([]string{"walking", "talking", "talking", "walked", "talked"})
+	dawg := trie.Freeze()
+
+	// The trie needs a separate "alk" + {"ing", "ed"} branch under both "w"
+	// and "t"; the DAWG stores it once: root, the node after "w" or "t",
+	// a, l, k, i, n, e and a single final node shared by "g" and "d".
+	if got, trieNodes := len(dawg.nodes), countNodesA2(trie.root); got != 9 || got >= trieNodes {
+		t.Errorf("Expected 9 DAWG nodes (trie has %d), got %d", trieNodes, got)
+	}
+	if dawg.Len() != trie.Len() {
+		t.Errorf("Expected %d words, got %d", trie.Len(), dawg.Len())
+	}
+
+	// Shared nodes must still report each word's own frequency.
+	got := dawg.Autocomplete("t", 5)
+	if len(got) != 2 || got[0].Word != "talking" || got[0].Frequency != 2 || got[1].Frequency != 1 {
+		t.Errorf("Expected talking(2) then talked(1), got %v", got)
+	}
+}
+
+func TestFreezeMatchesTrie(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	letters := []rune("abcé")
+	trie := NewTriesA2()
+	for i := 0; i < 500; i++ {
+		word := make([]rune, 1+rng.Intn(6))
+		for j := range word {
+			word[j] = letters[rng.Intn(len(letters))]
+		}
+		trie.Insert(string(word))
+	}
+	dawg := trie.Freeze()
+
+	for _, prefix := range []string{"", "a", "ab", "é", "cé", "abca", "zzz"} {
+		want, got := trie.Autocomplete(prefix, 10), dawg.Autocomplete(prefix, 10)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("For prefix %q: expected %v, got %v", prefix, want, got)
+		}
+	}
+
+	// Later inserts do not reach an already frozen DAWG.
+	trie.Insert("zzz")
+	if got := dawg.Autocomplete("zzz", 1); len(got) != 0 {
+		t.Errorf("Expected the DAWG to be unaffected by later inserts, got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package autocomplete
+
+import (
+	"math"
+	"time"
+)
+
+// -----------------------------------------
+// Frequency Decay
+// -----------------------------------------
+
+// WithDecay makes ranking favour recent activity: every occurrence of a word
+// loses half its weight each halfLife, so words that stop being inserted
+// sink out of the top-k. Weights start from the current frequencies and only
+// decay when Decay runs. A non-positive halfLife turns decay off. It returns
+// t so it can be chained after NewTriesA2.
+//
+// Decay only changes ranking scores; Frequency in results stays the
+// all-time count. This differs from the recency window of EnableWindow,
+// whose counts replace Frequency. If both are enabled the window takes
+// precedence.
+func (t *TriesA2) WithDecay(halfLife time.Duration) *TriesA2 {
+	if halfLife <= 0 {
+		t.halfLife = 0
+		return t
+	}
+	t.halfLife = halfLife
+	t.lastDecay = t.clock()
+	resetWeights(t.root)
+	return t
+}
+
+func resetWeights(node *NodeA2) {
+	node.weight = float64(node.frequency)
+	for _, child := range node.children {
+		resetWeights(child)
+	}
+}
+
+// Decay ages every weight by the time elapsed since the previous pass. It is
+// meant to run periodically in the background, for example from a
+// time.Ticker; when queries run concurrently, call it through
+// Concurrent.Update. It does nothing unless decay is enabled.
+func (t *TriesA2) Decay() {
+	if t.halfLife <= 0 {
+		return
+	}
+	now := t.clock()
+	elapsed := now.Sub(t.lastDecay)
+	if elapsed <= 0 {
+		return
+	}
+	factor := math.Pow(0.5, float64(elapsed)/float64(t.halfLife))
+	scaleWeights(t.root, factor)
+	t.lastDecay = now
+}
+
+func scaleWeights(node *NodeA2, factor float64) {
+	node.weight *= factor
+	for _, child := range node.children {
+		scaleWeights(child, factor)
+	}
+}
+
+// DecayedFrequency returns the decayed weight of word as of the last Decay
+// pass, or zero when decay is disabled or the word is unknown.
+func (t *TriesA2) DecayedFrequency(word string) float64 {
+	node := t.searchPrefix(word)
+	if t.halfLife <= 0 || node == nil || !node.isEndOfWord {
+		return 0
+	}
+	return node.weight
+}
+
+func (t *TriesA2) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,47 @@
+package autocomplete
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trie := NewTriesA2()
+	trie.now = func() time.Time { return now }
+	for i := 0; i < 8; i++ {
+		trie.Insert("hello")
+	}
+	trie.WithDecay(time.Hour)
+
+	// Two hours later "hello" has a quarter of its weight left, so three
+	// fresh inserts of "help" overtake it.
+	now = now.Add(2 * time.Hour)
+	trie.Decay()
+	if got := trie.DecayedFrequency("hello"); math.Abs(got-2) > 1e-9 {
+		t.Errorf("Expected hello to decay to 2, got %v", got)
+	}
+	for i := 0; i < 3; i++ {
+		trie.Insert("help")
+	}
+
+	got := trie.Autocomplete("hel", 2)
+	if want := []string{"help", "hello"}; !reflect.DeepEqual(Words(got), want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got[0].Score != 0.6 || got[1].Frequency != 8 {
+		t.Errorf("Expected help to score 0.6 and hello to keep frequency 8, got %v", got)
+	}
+
+	// Turning decay off returns to all-time frequency.
+	trie.WithDecay(0)
+	trie.Decay()
+	if got := Words(trie.Autocomplete("hel", 1)); got[0] != "hello" {
+		t.Errorf("Expected hello first without decay, got %v", got)
+	}
+	if trie.DecayedFrequency("hello") != 0 {
+		t.Errorf("Expected no decayed frequency while decay is off")
+	}
+}
@@ -0,0 +1,104 @@
+package autocomplete
+
+// -----------------------------------------
+// Deletion
+// -----------------------------------------
+
+// Delete removes one occurrence of word. When its frequency reaches zero the
+// word is removed and any branch left without words is pruned. It returns
+// false if word is not in the trie.
+func (t *TrieA1) Delete(word string) bool {
+	return t.remove(word, false)
+}
+
+// RemoveAll removes word regardless of its frequency. It returns false if
+// word is not in the trie. Bigrams mentioning word are kept; call
+// PruneBigrams to drop them.
+func (t *TrieA1) RemoveAll(word string) bool {
+	return t.remove(word, true)
+}
+
+func (t *TrieA1) remove(word string, all bool) bool {
+	runes := []rune(word)
+	path := make([]*TrieNodeA1, 1, len(runes)+1)
+	path[0] = t.root
+	node := t.root
+	for _, char := range runes {
+		child := node.child(char)
+		if child == nil {
+			return false
+		}
+		node = child
+		path = append(path, node)
+	}
+	if !node.isEnd {
+		return false
+	}
+
+	node.frequency--
+	if all || node.frequency <= 0 {
+		node.isEnd = false
+		node.frequency = 0
+		t.size--
+		delete(t.payloads, word)
+		for i := len(runes); i > 0 && !path[i].isEnd && path[i].childCount() == 0; i-- {
+			path[i-1].removeChild(runes[i-1])
+		}
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].recount()
+	}
+	t.unfinalize()
+	t.resetKeyIndexes()
+	t.cache.clear()
+	return true
+}
+
+// Delete removes one occurrence of word. When its frequency reaches zero the
+// word is removed and any branch left without words is pruned. It returns
+// false if word is not in the trie.
+func (t *TriesA2) Delete(word string) bool {
+	return t.remove(word, false)
+}
+
+// RemoveAll removes word regardless of its frequency. It returns false if
+// word is not in the trie.
+func (t *TriesA2) RemoveAll(word string) bool {
+	return t.remove(word, true)
+}
+
+func (t *TriesA2) remove(word string, all bool) bool {
+	runes := []rune(word)
+	path := make([]*NodeA2, 1, len(runes)+1)
+	path[0] = t.root
+	current := t.root
+	for _, char := range runes {
+		node, ok := current.children[char]
+		if !ok {
+			return false
+		}
+		current = node
+		path = append(path, current)
+	}
+	if !current.isEndOfWord {
+		return false
+	}
+
+	current.frequency--
+	if all || current.frequency <= 0 {
+		current.isEndOfWord = false
+		current.frequency = 0
+		current.weight = 0
+		t.forgetInWindow(current)
+		t.size--
+		t.resetInfix()
+		delete(t.payloads, word)
+		for _, n := range path {
+			n.words--
+		}
+		for i := len(runes); i > 0 && !path[i].isEndOfWord && len(path[i].children) == 0; i-- {
+			delete(path[i-1].children, runes[i-1])
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package autocomplete
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeleteDecrementsAndPrunes(t *testing.T) {
+	trieA1 := buildAlg1Trie([]string{"hello", "hello", "hell", "helicopter"})
+	trieA2 := buildAlg2Trie([]string{"hello", "hello", "hell", "helicopter"})
+
+	for name, trie := range map[string]interface {
+		Autocompleter
+		Delete(string) bool
+	}{"Algorithm_1": trieA1, "Algorithm_2": trieA2} {
+		if !trie.Delete("hello") {
+			t.Errorf("%s: expected Delete to find 'hello'", name)
+		}
+		if got := trie.Autocomplete("hello", 1); len(got) != 1 || got[0].Frequency != 1 {
+			t.Errorf("%s: expected 'hello' to remain with frequency 1, got %v", name, got)
+		}
+		trie.Delete("hello")
+		if got := trie.Autocomplete("hello", 1); len(got) != 0 {
+			t.Errorf("%s: expected 'hello' to be gone, got %v", name, got)
+		}
+		if trie.Len() != 2 {
+			t.Errorf("%s: expected 2 words left, got %d", name, trie.Len())
+		}
+		if trie.Delete("hello") || trie.Delete("hel") || trie.Delete("xyz") {
+			t.Errorf("%s: expected Delete to fail for words not in the trie", name)
+		}
+	}
+
+	// The "o" node of "hello" is pruned, while "hell" keeps its branch.
+	if _, exists := trieA1.searchPrefix("hell").children['o']; exists {
+		t.Errorf("Expected the empty 'hello' branch to be pruned in Algorithm_1")
+	}
+	if _, exists := trieA2.searchPrefix("hell").children['o']; exists {
+		t.Errorf("Expected the empty 'hello' branch to be pruned in Algorithm_2")
+	}
+
+	trieA2.RemoveAll("helicopter")
+	if trieA2.searchPrefix("heli") != nil {
+		t.Errorf("Expected the whole 'icopter' branch to be pruned")
+	}
+	if err := trieA2.Validate(); err != nil {
+		t.Errorf("Expected a valid trie after deletions, got %v", err)
+	}
+}
+
+func TestRemoveAllIgnoresFrequency(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "hello", "hello", "help"})
+
+	if !trie.RemoveAll("hello") {
+		t.Fatalf("Expected RemoveAll to find 'hello'")
+	}
+	if trie.Frequency("hello") != 0 || trie.Len() != 1 {
+		t.Errorf("Expected 'hello' to be removed entirely")
+	}
+	if got := trie.AutocompleteTopK("hel", 5); len(got) != 1 || got[0] != "help" {
+		t.Errorf("Expected only 'help' to remain, got %v", got)
+	}
+}
+
+func TestRemoveResetsWindowAndDecay(t *testing.T) {
+	// "hello" keeps the node of "hell" in the trie after it is removed.
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	decayed := buildAlg2Trie([]string{"hell", "hell", "hell", "hell", "hello"})
+	decayed.now = func() time.Time { return now }
+	decayed.WithDecay(time.Hour)
+	now = now.Add(time.Hour)
+	decayed.Decay()
+	decayed.RemoveAll("hell")
+	decayed.Insert("hell")
+	if got := decayed.DecayedFrequency("hell"); got != 1 {
+		t.Errorf("Expected a reinserted word to restart its decayed weight at 1, got %v", got)
+	}
+
+	windowed := NewTriesA2()
+	windowed.EnableWindow(4)
+	for _, word := range []string{"hello", "hell", "hell"} {
+		windowed.Insert(word)
+	}
+	windowed.RemoveAll("hell")
+	windowed.Insert("hell")
+	if got := windowed.WindowedFrequency("hell"); got != 1 {
+		t.Errorf("Expected a reinserted word to restart its window count at 1, got %d", got)
+	}
+	// The removed occurrences leave the window without touching new ones.
+	windowed.Insert("help")
+	windowed.Insert("help")
+	if got := windowed.WindowedFrequency("hell"); got != 1 {
+		t.Errorf("Expected the reinserted occurrence to stay in the window, got %d", got)
+	}
+}
+
+func TestDeleteThenPruneBigrams(t *testing.T) {
+	corpus := []string{"new", "york", "city", "new", "jersey", "new", "york", "times"}
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(corpus)
+
+	trie.RemoveAll("jersey")
+	trie.RemoveAll("times")
+	trie.PruneBigrams()
+
+	if _, exists := trie.bigramTable["new"]["jersey"]; exists {
+		t.Errorf("Expected 'jersey' to be pruned from the bigram table")
+	}
+	if got := trie.bigramTable["new"]["_total"]; got != 2 {
+		t.Errorf("Expected total of 2 for 'new', got %d", got)
+	}
+}
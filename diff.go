@@ -0,0 +1,46 @@
+package autocomplete
+
+import "sort"
+
+// -----------------------------------------
+// Trie Diff
+// -----------------------------------------
+
+// wordFrequencies maps every stored word to its frequency.
+func (t *TriesA2) wordFrequencies() map[string]int {
+	var entries []Suggestion
+	collectEntriesA2(t.root, "", &entries)
+	frequencies := make(map[string]int, len(entries))
+	for _, e := range entries {
+		frequencies[e.Word] = e.Frequency
+	}
+	return frequencies
+}
+
+// Diff compares t against an older version of the dictionary and reports the
+// words only t contains, the words only old contains, and the words present
+// in both whose frequency differs. Each slice is sorted.
+func (t *TriesA2) Diff(old *TriesA2) (added, removed, changed []string) {
+	current := t.wordFrequencies()
+	previous := old.wordFrequencies()
+
+	for word, freq := range current {
+		oldFreq, exists := previous[word]
+		switch {
+		case !exists:
+			added = append(added, word)
+		case oldFreq != freq:
+			changed = append(changed, word)
+		}
+	}
+	for word := range previous {
+		if _, exists := current[word]; !exists {
+			removed = append(removed, word)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	return added, removed, changed
+}
@@ -0,0 +1,28 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := buildAlg2Trie([]string{"hello", "hell", "helicopter", "hero", "world"})
+	updated := buildAlg2Trie([]string{"hello", "hello", "hell", "hero", "world", "world", "war", "how"})
+
+	added, removed, changed := updated.Diff(old)
+
+	if want := []string{"how", "war"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("Added: expected %v, got %v", want, added)
+	}
+	if want := []string{"helicopter"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("Removed: expected %v, got %v", want, removed)
+	}
+	if want := []string{"hello", "world"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("Changed: expected %v, got %v", want, changed)
+	}
+
+	added, removed, changed = old.Diff(old)
+	if len(added)+len(removed)+len(changed) != 0 {
+		t.Errorf("Expected no differences between a trie and itself")
+	}
+}
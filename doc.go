@@ -0,0 +1,18 @@
+// Package autocomplete provides trie-based word completion.
+//
+// Four algorithms are available. TrieA1 (Algorithm_1) ranks completions by
+// the preceding words passed to AutocompleteWithContext or
+// AutocompleteContext, falling back to frequency when no context is known.
+// TriesA2 (Algorithm_2) ranks completions purely by frequency. TrieA3
+// (Algorithm_3) ranks like Algorithm_2 but is a radix trie that stores whole
+// edge labels, using far fewer nodes. TrieA4 (Algorithm_4) is a ternary
+// search tree with the same ranking. Trie, made with NewTrie, is TrieA1 under
+// the name for callers who just want the default, and every algorithm
+// implements Autocompleter. The demo in cmd/autocomplete compares them.
+//
+// Every algorithm returns suggestions in a deterministic order: by
+// descending score, then by descending frequency, then by word in
+// lexicographic (byte) order. Scores are
+// deterministic too, so the same trie answers the same query identically on
+// every run even though its nodes are walked in map order.
+package autocomplete
@@ -0,0 +1,552 @@
+package autocomplete
+
+import "strings"
+
+// -----------------------------------------
+// Double Metaphone
+// -----------------------------------------
+
+// DoubleMetaphone returns the two Double Metaphone keys of word, Lawrence
+// Philips' successor to Metaphone. It knows far more spellings than
+// Metaphone, including those of Germanic, Slavic, Romance and Greek names
+// common in English text, and where a spelling can be said two ways it
+// gives both: the primary key for the usual English pronunciation and the
+// alternate key for the other, so "Schmidt" (XMT, SMT) matches "Smith"
+// (SM0, XMT). For most words the two keys are equal.
+//
+// The keys are not cut to four characters as in Philips' original, so a
+// prefix's key is a prefix of its completions' keys. Letters other than A
+// to Z, Ç and Ñ are ignored, apart from spaces, which mark name particles
+// such as "van" and "san".
+func DoubleMetaphone(word string) (primary, alternate string) {
+	m := doubleMetaphone{word: []rune(strings.ToUpper(word))}
+	m.encode()
+	return string(m.primary), string(m.alternate)
+}
+
+// doubleMetaphoneKeys returns the distinct non-empty keys of word.
+func doubleMetaphoneKeys(word string) []string {
+	primary, alternate := DoubleMetaphone(word)
+	switch {
+	case primary == "":
+		return nil
+	case alternate == primary || alternate == "":
+		return []string{primary}
+	}
+	return []string{primary, alternate}
+}
+
+// doubleMetaphone is the state of one encoding: the upper-case word and the
+// two keys built from it.
+type doubleMetaphone struct {
+	word      []rune
+	primary   []rune
+	alternate []rune
+}
+
+// add appends code to both keys.
+func (m *doubleMetaphone) add(code string) {
+	m.addBoth(code, code)
+}
+
+// addBoth appends primary to the primary key and alternate to the
+// alternate one.
+func (m *doubleMetaphone) addBoth(primary, alternate string) {
+	m.primary = append(m.primary, []rune(primary)...)
+	m.alternate = append(m.alternate, []rune(alternate)...)
+}
+
+// at returns the letter at i, or 0 outside the word.
+func (m *doubleMetaphone) at(i int) rune {
+	if i < 0 || i >= len(m.word) {
+		return 0
+	}
+	return m.word[i]
+}
+
+// matches reports whether one of options starts at i; all options have the
+// same length.
+func (m *doubleMetaphone) matches(i int, options ...string) bool {
+	n := len([]rune(options[0]))
+	if i < 0 || i+n > len(m.word) {
+		return false
+	}
+	s := string(m.word[i : i+n])
+	for _, option := range options {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *doubleMetaphone) isVowel(i int) bool {
+	return strings.ContainsRune("AEIOUY", m.at(i))
+}
+
+func (m *doubleMetaphone) last() int {
+	return len(m.word) - 1
+}
+
+// slavoGermanic reports whether the word looks Germanic or Slavic, which
+// changes how several letters sound.
+func (m *doubleMetaphone) slavoGermanic() bool {
+	s := string(m.word)
+	return strings.ContainsAny(s, "WK") || strings.Contains(s, "CZ") || strings.Contains(s, "WITZ")
+}
+
+// germanic reports whether the word starts like a Germanic name.
+func (m *doubleMetaphone) germanic() bool {
+	return m.matches(0, "VAN ", "VON ") || m.matches(0, "SCH")
+}
+
+func (m *doubleMetaphone) encode() {
+	slavoGermanic := m.slavoGermanic()
+	i := 0
+	if m.matches(0, "GN", "KN", "PN", "WR", "PS") {
+		i = 1
+	}
+	for i < len(m.word) {
+		switch c := m.word[i]; c {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if i == 0 {
+				m.add("A")
+			}
+			i++
+		case 'B':
+			m.add("P")
+			i += m.skipDouble(i, "B")
+		case 'Ç':
+			m.add("S")
+			i++
+		case 'C':
+			i = m.encodeC(i)
+		case 'D':
+			i = m.encodeD(i)
+		case 'F', 'K', 'N':
+			m.add(string(c))
+			i += m.skipDouble(i, string(c))
+		case 'G':
+			i = m.encodeG(i, slavoGermanic)
+		case 'H':
+			// Only sounded first or between vowels.
+			if (i == 0 || m.isVowel(i-1)) && m.isVowel(i+1) {
+				m.add("H")
+				i += 2
+			} else {
+				i++
+			}
+		case 'J':
+			i = m.encodeJ(i, slavoGermanic)
+		case 'L':
+			i = m.encodeL(i)
+		case 'M':
+			m.add("M")
+			if m.at(i+1) == 'M' || m.matches(i-1, "UMB") && (i+1 == m.last() || m.matches(i+2, "ER")) {
+				// "dumb", "thumb": the B is silent.
+				i += 2
+			} else {
+				i++
+			}
+		case 'Ñ':
+			m.add("N")
+			i++
+		case 'P':
+			if m.at(i+1) == 'H' {
+				m.add("F")
+				i += 2
+			} else {
+				m.add("P")
+				i += m.skipDouble(i, "P", "B")
+			}
+		case 'Q':
+			m.add("K")
+			i += m.skipDouble(i, "Q")
+		case 'R':
+			// French final "-ier" is silent in the primary key.
+			if i == m.last() && !slavoGermanic && m.matches(i-2, "IE") && !m.matches(i-4, "ME", "MA") {
+				m.addBoth("", "R")
+			} else {
+				m.add("R")
+			}
+			i += m.skipDouble(i, "R")
+		case 'S':
+			i = m.encodeS(i, slavoGermanic)
+		case 'T':
+			i = m.encodeT(i)
+		case 'V':
+			m.add("F")
+			i += m.skipDouble(i, "V")
+		case 'W':
+			i = m.encodeW(i)
+		case 'X':
+			i = m.encodeX(i)
+		case 'Z':
+			i = m.encodeZ(i, slavoGermanic)
+		default:
+			i++
+		}
+	}
+}
+
+// skipDouble returns how far to advance past the letter at i: two if one
+// of next follows it, one otherwise.
+func (m *doubleMetaphone) skipDouble(i int, next ...string) int {
+	if m.matches(i+1, next...) {
+		return 2
+	}
+	return 1
+}
+
+func (m *doubleMetaphone) encodeC(i int) int {
+	switch {
+	case m.hardCH(i):
+		// "bacher", "macher", "chianti".
+		m.add("K")
+		return i + 2
+	case i == 0 && m.matches(i, "CAESAR"):
+		m.add("S")
+		return i + 2
+	case m.matches(i, "CH"):
+		return m.encodeCH(i)
+	case m.matches(i, "CZ") && !m.matches(i-2, "WICZ"):
+		// "Czerny".
+		m.addBoth("S", "X")
+		return i + 2
+	case m.matches(i+1, "CIA"):
+		// "focaccia".
+		m.add("X")
+		return i + 3
+	case m.matches(i, "CC") && !(i == 1 && m.at(0) == 'M'):
+		// Double C, but not "McClelland".
+		if m.matches(i+2, "I", "E", "H") && !m.matches(i+2, "HU") {
+			if i == 1 && m.at(0) == 'A' || m.matches(i-1, "UCCEE", "UCCES") {
+				// "accident", "accede", "succeed".
+				m.add("KS")
+			} else {
+				// "bacci", "bertucci".
+				m.add("X")
+			}
+			return i + 3
+		}
+		m.add("K")
+		return i + 2
+	case m.matches(i, "CK", "CG", "CQ"):
+		m.add("K")
+		return i + 2
+	case m.matches(i, "CI", "CE", "CY"):
+		if m.matches(i, "CIO", "CIE", "CIA") {
+			m.addBoth("S", "X")
+		} else {
+			m.add("S")
+		}
+		return i + 2
+	}
+	m.add("K")
+	switch {
+	case m.matches(i+1, " C", " Q", " G"):
+		// "Mac Caffrey", "Mac Gregor".
+		return i + 3
+	case m.matches(i+1, "C", "K", "Q") && !m.matches(i+1, "CE", "CI"):
+		return i + 2
+	}
+	return i + 1
+}
+
+// hardCH reports whether the C at i is a hard C before an H, as in the
+// Germanic "-acher" or the Italian "chia-".
+func (m *doubleMetaphone) hardCH(i int) bool {
+	switch {
+	case m.matches(i, "CHIA"):
+		return true
+	case i <= 1 || m.isVowel(i-2) || !m.matches(i-1, "ACH"):
+		return false
+	}
+	c := m.at(i + 2)
+	return c != 'I' && c != 'E' || m.matches(i-2, "BACHER", "MACHER")
+}
+
+func (m *doubleMetaphone) encodeCH(i int) int {
+	switch {
+	case i > 0 && m.matches(i, "CHAE"):
+		// "Michael".
+		m.addBoth("K", "X")
+	case i == 0 && (m.matches(i+1, "HARAC", "HARIS") || m.matches(i+1, "HOR", "HYM", "HIA", "HEM")) &&
+		!m.matches(0, "CHORE"):
+		// Greek roots: "character", "chorus", "chemistry".
+		m.add("K")
+	case m.germanic() || m.matches(i-2, "ORCHES", "ARCHIT", "ORCHID") || m.matches(i+2, "T", "S") ||
+		(i == 0 || m.matches(i-1, "A", "O", "U", "E")) &&
+			(m.matches(i+2, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || i+1 == m.last()):
+		// Germanic, Greek or otherwise a "kh" sound: "orchestra",
+		// "architect", "christ", "Bach".
+		m.add("K")
+	case i > 0:
+		if m.matches(0, "MC") {
+			// "McHugh".
+			m.add("K")
+		} else {
+			m.addBoth("X", "K")
+		}
+	default:
+		m.add("X")
+	}
+	return i + 2
+}
+
+func (m *doubleMetaphone) encodeD(i int) int {
+	switch {
+	case m.matches(i, "DG"):
+		if m.matches(i+2, "I", "E", "Y") {
+			// "edge".
+			m.add("J")
+			return i + 3
+		}
+		// "Edgar".
+		m.add("TK")
+		return i + 2
+	case m.matches(i, "DT", "DD"):
+		m.add("T")
+		return i + 2
+	}
+	m.add("T")
+	return i + 1
+}
+
+func (m *doubleMetaphone) encodeG(i int, slavoGermanic bool) int {
+	switch {
+	case m.at(i+1) == 'H':
+		return m.encodeGH(i)
+	case m.at(i+1) == 'N':
+		switch {
+		case i == 1 && m.isVowel(0) && !slavoGermanic:
+			m.addBoth("KN", "N")
+		case !m.matches(i+2, "EY") && m.at(i+1) != 'Y' && !slavoGermanic:
+			// "sign", "campagna".
+			m.addBoth("N", "KN")
+		default:
+			m.add("KN")
+		}
+		return i + 2
+	case m.matches(i+1, "LI") && !slavoGermanic:
+		// "tagliaro".
+		m.addBoth("KL", "L")
+		return i + 2
+	case i == 0 && (m.at(i+1) == 'Y' || m.matches(i+1, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		// "ges-", "gep-", "gel-", "gie-" at the start.
+		m.addBoth("K", "J")
+		return i + 2
+	case (m.matches(i+1, "ER") || m.at(i+1) == 'Y') && !m.matches(0, "DANGER", "RANGER", "MANGER") &&
+		!m.matches(i-1, "E", "I") && !m.matches(i-1, "RGY", "OGY"):
+		// "-ger-", "-gy-".
+		m.addBoth("K", "J")
+		return i + 2
+	case m.matches(i+1, "E", "I", "Y") || m.matches(i-1, "AGGI", "OGGI"):
+		switch {
+		case m.germanic() || m.matches(i+1, "ET"):
+			m.add("K")
+		case m.matches(i+1, "IER"):
+			m.add("J")
+		default:
+			// Italian "biaggi".
+			m.addBoth("J", "K")
+		}
+		return i + 2
+	}
+	m.add("K")
+	return i + m.skipDouble(i, "G")
+}
+
+func (m *doubleMetaphone) encodeGH(i int) int {
+	switch {
+	case i > 0 && !m.isVowel(i-1):
+		m.add("K")
+	case i == 0:
+		// "ghislane", "ghost".
+		if m.at(i+2) == 'I' {
+			m.add("J")
+		} else {
+			m.add("K")
+		}
+	case i > 1 && m.matches(i-2, "B", "H", "D") || i > 2 && m.matches(i-3, "B", "H", "D") ||
+		i > 3 && m.matches(i-4, "B", "H"):
+		// Silent, as in "hugh", "bough" and "broughton".
+	case i > 2 && m.at(i-1) == 'U' && m.matches(i-3, "C", "G", "L", "R", "T"):
+		// "laugh", "cough", "rough", "tough".
+		m.add("F")
+	case m.at(i-1) != 'I':
+		m.add("K")
+	}
+	return i + 2
+}
+
+func (m *doubleMetaphone) encodeJ(i int, slavoGermanic bool) int {
+	if m.matches(i, "JOSE") || m.matches(0, "SAN ") {
+		// Spanish: "Jose", "San Jacinto".
+		if i == 0 && m.at(i+4) == ' ' || len(m.word) == 4 || m.matches(0, "SAN ") {
+			m.add("H")
+		} else {
+			m.addBoth("J", "H")
+		}
+		return i + 1
+	}
+	switch {
+	case i == 0:
+		// "Yankelovich", "Jankelowicz".
+		m.addBoth("J", "A")
+	case m.isVowel(i-1) && !slavoGermanic && (m.at(i+1) == 'A' || m.at(i+1) == 'O'):
+		// Spanish pronunciation, as in "bajador".
+		m.addBoth("J", "H")
+	case i == m.last():
+		m.addBoth("J", "")
+	case !m.matches(i+1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.matches(i-1, "S", "K", "L"):
+		m.add("J")
+	}
+	return i + m.skipDouble(i, "J")
+}
+
+func (m *doubleMetaphone) encodeL(i int) int {
+	if m.at(i+1) != 'L' {
+		m.add("L")
+		return i + 1
+	}
+	n := len(m.word)
+	if i == n-3 && m.matches(i-1, "ILLO", "ILLA", "ALLE") ||
+		(m.matches(n-2, "AS", "OS") || m.matches(n-1, "A", "O")) && m.matches(i-1, "ALLE") {
+		// Spanish, as in "cabrillo" and "gallegos".
+		m.addBoth("L", "")
+	} else {
+		m.add("L")
+	}
+	return i + 2
+}
+
+func (m *doubleMetaphone) encodeS(i int, slavoGermanic bool) int {
+	switch {
+	case m.matches(i-1, "ISL", "YSL"):
+		// Silent, as in "island", "isle" and "carlisle".
+		return i + 1
+	case i == 0 && m.matches(i, "SUGAR"):
+		m.addBoth("X", "S")
+		return i + 1
+	case m.matches(i, "SH"):
+		if m.matches(i+1, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			// Germanic.
+			m.add("S")
+		} else {
+			m.add("X")
+		}
+		return i + 2
+	case m.matches(i, "SIO", "SIA") || m.matches(i, "SIAN"):
+		// Italian and Armenian.
+		if slavoGermanic {
+			m.add("S")
+		} else {
+			m.addBoth("S", "X")
+		}
+		return i + 3
+	case i == 0 && m.matches(i+1, "M", "N", "L", "W") || m.matches(i+1, "Z"):
+		// German and anglicized forms, so "Smith" matches "Schmidt" and
+		// "Snider" matches "Schneider"; also the Slavic "sz".
+		m.addBoth("S", "X")
+		return i + m.skipDouble(i, "Z")
+	case m.matches(i, "SC"):
+		return m.encodeSC(i)
+	case i == m.last() && m.matches(i-2, "AI", "OI"):
+		// French, as in "resnais" and "artois".
+		m.addBoth("", "S")
+	default:
+		m.add("S")
+	}
+	return i + m.skipDouble(i, "S", "Z")
+}
+
+func (m *doubleMetaphone) encodeSC(i int) int {
+	switch {
+	case m.at(i+2) == 'H':
+		switch {
+		case m.matches(i+3, "ER", "EN"):
+			// "schermerhorn", "schenker".
+			m.addBoth("X", "SK")
+		case m.matches(i+3, "OO", "UY", "ED", "EM"):
+			// Dutch, as in "school" and "schooner".
+			m.add("SK")
+		case i == 0 && !m.isVowel(3) && m.at(3) != 'W':
+			m.addBoth("X", "S")
+		default:
+			m.add("X")
+		}
+	case m.matches(i+2, "I", "E", "Y"):
+		m.add("S")
+	default:
+		m.add("SK")
+	}
+	return i + 3
+}
+
+func (m *doubleMetaphone) encodeT(i int) int {
+	switch {
+	case m.matches(i, "TION"), m.matches(i, "TIA", "TCH"):
+		m.add("X")
+		return i + 3
+	case m.matches(i, "TH") || m.matches(i, "TTH"):
+		if m.matches(i+2, "OM", "AM") || m.germanic() {
+			// "Thomas", "Thames".
+			m.add("T")
+		} else {
+			m.addBoth("0", "T")
+		}
+		return i + 2
+	}
+	m.add("T")
+	return i + m.skipDouble(i, "T", "D")
+}
+
+func (m *doubleMetaphone) encodeW(i int) int {
+	switch {
+	case m.matches(i, "WR"):
+		m.add("R")
+		return i + 2
+	case i == 0 && m.isVowel(i+1):
+		// "Wasserman" matches "Vasserman".
+		m.addBoth("A", "F")
+	case i == 0 && m.matches(i, "WH"):
+		// "Womo" matches "Uomo".
+		m.add("A")
+	case i == m.last() && m.isVowel(i-1) || m.matches(i-1, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.matches(0, "SCH"):
+		// "Arnow" matches "Arnoff".
+		m.addBoth("", "F")
+	case m.matches(i, "WICZ", "WITZ"):
+		// Polish, as in "filipowicz".
+		m.addBoth("TS", "FX")
+		return i + 4
+	}
+	return i + 1
+}
+
+func (m *doubleMetaphone) encodeX(i int) int {
+	if i == 0 {
+		// "Xavier".
+		m.add("S")
+		return i + 1
+	}
+	if !(i == m.last() && (m.matches(i-3, "IAU", "EAU") || m.matches(i-2, "AU", "OU"))) {
+		// Not the silent French final X of "breaux".
+		m.add("KS")
+	}
+	return i + m.skipDouble(i, "C", "X")
+}
+
+func (m *doubleMetaphone) encodeZ(i int, slavoGermanic bool) int {
+	if m.at(i+1) == 'H' {
+		// Chinese pinyin, as in "Zhao".
+		m.add("J")
+		return i + 2
+	}
+	if m.matches(i+1, "ZO", "ZI", "ZA") || slavoGermanic && i > 0 && m.at(i-1) != 'T' {
+		m.addBoth("S", "TS")
+	} else {
+		m.add("S")
+	}
+	return i + m.skipDouble(i, "Z")
+}
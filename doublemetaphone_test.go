@@ -0,0 +1,50 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDoubleMetaphone(t *testing.T) {
+	for word, want := range map[string][2]string{
+		"Smith":     {"SM0", "XMT"},
+		"Schmidt":   {"XMT", "SMT"},
+		"Thomas":    {"TMS", "TMS"},
+		"knight":    {"NT", "NT"},
+		"nite":      {"NT", "NT"},
+		"Xavier":    {"SF", "SFR"},
+		"Jose":      {"HS", "HS"},
+		"Michael":   {"MKL", "MXL"},
+		"Arnow":     {"ARN", "ARNF"},
+		"Czerny":    {"SRN", "XRN"},
+		"Wasserman": {"ASRMN", "FSRMN"},
+		"edge":      {"AJ", "AJ"},
+		"laugh":     {"LF", "LF"},
+		"school":    {"SKL", "SKL"},
+		"bacchus":   {"PKS", "PKS"},
+		"caesar":    {"SSR", "SSR"},
+		"":          {"", ""},
+	} {
+		if primary, alternate := DoubleMetaphone(word); primary != want[0] || alternate != want[1] {
+			t.Errorf("DoubleMetaphone(%q): expected %q and %q, got %q and %q", word, want[0], want[1], primary, alternate)
+		}
+	}
+}
+
+func TestWithDoubleMetaphone(t *testing.T) {
+	trie := buildAlg1Trie([]string{"night", "night", "schmidt", "smile", "hello"})
+	trie.WithDoubleMetaphone(0)
+
+	if got := Words(trie.Autocomplete("nite", 5)); !reflect.DeepEqual(got, []string{"night"}) {
+		t.Errorf("Expected [night], got %v", got)
+	}
+	// "smi" encodes as SM or XM; "schmidt" is found by its alternate key SMT.
+	if got := Words(trie.Autocomplete("smi", 5)); !reflect.DeepEqual(got, []string{"schmidt", "smile"}) {
+		t.Errorf("Expected [schmidt smile], got %v", got)
+	}
+
+	trie.WithPhonetic(Metaphone, 0)
+	if got := Words(trie.Autocomplete("smi", 5)); !reflect.DeepEqual(got, []string{"smile"}) {
+		t.Errorf("Expected Metaphone not to match 'schmidt', got %v", got)
+	}
+}
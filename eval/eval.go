@@ -0,0 +1,181 @@
+// Package eval measures how well the autocomplete algorithms predict the
+// words people actually type.
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	autocomplete "auto-complete"
+)
+
+// -----------------------------------------
+// Labeled Test Sets
+// -----------------------------------------
+
+// Case is one labeled query: after the words of Context, the user has typed
+// Prefix and meant Expected.
+type Case struct {
+	Context  []string `json:"context,omitempty"`
+	Prefix   string   `json:"prefix"`
+	Expected string   `json:"expected"`
+}
+
+// Suggester returns up to k ranked completions of prefix typed after
+// context. Suggesters that ignore context are compared on equal terms: the
+// context only helps those that use it.
+type Suggester func(context []string, prefix string, k int) []string
+
+// FromAutocompleter adapts any algorithm; context is ignored.
+func FromAutocompleter(a autocomplete.Autocompleter) Suggester {
+	return func(_ []string, prefix string, k int) []string {
+		return autocomplete.Words(a.Autocomplete(prefix, k))
+	}
+}
+
+// FromTrieA1 adapts Algorithm_1, ranking by the context through its bigram
+// and n-gram tables.
+func FromTrieA1(t *autocomplete.TrieA1) Suggester {
+	return func(context []string, prefix string, k int) []string {
+		return autocomplete.Words(t.AutocompleteContext(prefix, context, k))
+	}
+}
+
+// LoadCases reads a test set of one JSON-encoded Case per line, such as
+//
+//	{"context": ["new"], "prefix": "yo", "expected": "york"}
+//
+// Blank lines are skipped.
+func LoadCases(r io.Reader) ([]Case, error) {
+	var cases []Case
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var c Case
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		cases = append(cases, c)
+	}
+	return cases, scanner.Err()
+}
+
+// LoadCasesFile reads the test set in the file at path.
+func LoadCasesFile(path string) ([]Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadCases(f)
+}
+
+// -----------------------------------------
+// Metrics
+// -----------------------------------------
+
+// Result summarizes a Suggester's performance on a test set, every metric
+// averaged over the cases.
+type Result struct {
+	Cases int
+	K     int
+	// MRR is the mean reciprocal rank of the expected word, counting zero
+	// when it is not among the top k.
+	MRR float64
+	// PrecisionAtK is the share of the k suggestion slots holding the
+	// expected word; with one expected word per case it is at most 1/k.
+	PrecisionAtK float64
+	// RecallAtK is the share of cases whose expected word is in the top k.
+	RecallAtK float64
+	// KeystrokeSavings is the share of the expected words' characters the
+	// user need not type: typing one character at a time after the context,
+	// they accept the word, at the cost of one keystroke, as soon as it is
+	// among the top k.
+	KeystrokeSavings float64
+	// KeystrokesSaved is the average number of keys saved per case.
+	KeystrokesSaved float64
+	// AcceptRank is the average rank the expected word had when the user
+	// accepted it, over the cases where it was accepted before being typed
+	// in full.
+	AcceptRank float64
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("MRR %.3f  P@%d %.3f  R@%d %.3f  keystrokes saved %.1f%% (%.2f/word)  accept rank %.2f  (%d cases)",
+		r.MRR, r.K, r.PrecisionAtK, r.K, r.RecallAtK, 100*r.KeystrokeSavings, r.KeystrokesSaved, r.AcceptRank, r.Cases)
+}
+
+// Evaluate runs every case through suggest and averages the metrics.
+func Evaluate(cases []Case, suggest Suggester, k int) Result {
+	result := Result{Cases: len(cases), K: k}
+	if len(cases) == 0 || k <= 0 {
+		return result
+	}
+
+	typed, total, accepted := 0, 0, 0
+	for _, c := range cases {
+		if rank := Rank(suggest(c.Context, c.Prefix, k), c.Expected); rank > 0 {
+			result.MRR += 1 / float64(rank)
+			result.PrecisionAtK += 1 / float64(k)
+			result.RecallAtK++
+		}
+		keys, rank := typeWord(suggest, c.Context, c.Expected, k)
+		if rank > 0 {
+			result.AcceptRank += float64(rank)
+			accepted++
+		}
+		typed += keys
+		total += len([]rune(c.Expected))
+	}
+
+	n := float64(len(cases))
+	result.MRR /= n
+	result.PrecisionAtK /= n
+	result.RecallAtK /= n
+	result.KeystrokesSaved = float64(total-typed) / n
+	if total > 0 {
+		result.KeystrokeSavings = float64(total-typed) / float64(total)
+	}
+	if accepted > 0 {
+		result.AcceptRank /= float64(accepted)
+	}
+	return result
+}
+
+// Rank returns the 1-based position of expected in suggestions, or 0 when
+// it is missing.
+func Rank(suggestions []string, expected string) int {
+	for i, s := range suggestions {
+		if s == expected {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Keystrokes returns how many keys it takes to enter word after context:
+// the characters typed until word appears in the top k, plus one to accept
+// it, or every character when accepting would not save anything.
+func Keystrokes(suggest Suggester, context []string, word string, k int) int {
+	keys, _ := typeWord(suggest, context, word, k)
+	return keys
+}
+
+// typeWord simulates entering word as Keystrokes describes, also returning
+// the rank word had when accepted, or 0 if it was typed in full.
+func typeWord(suggest Suggester, context []string, word string, k int) (keys, rank int) {
+	runes := []rune(word)
+	for typed := 0; typed < len(runes)-1; typed++ {
+		if rank := Rank(suggest(context, string(runes[:typed]), k), word); rank > 0 {
+			return typed + 1, rank
+		}
+	}
+	return len(runes), 0
+}
@@ -0,0 +1,108 @@
+package eval
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	autocomplete "auto-complete"
+)
+
+// fixed suggests from a canned list, keeping the words that start with the
+// prefix.
+func fixed(words ...string) Suggester {
+	return func(_ []string, prefix string, k int) []string {
+		var out []string
+		for _, w := range words {
+			if strings.HasPrefix(w, prefix) && len(out) < k {
+				out = append(out, w)
+			}
+		}
+		return out
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEvaluate(t *testing.T) {
+	suggest := fixed("hello", "help", "hero", "world")
+	cases := []Case{
+		{Prefix: "he", Expected: "hello"}, // rank 1
+		{Prefix: "he", Expected: "help"},  // rank 2
+		{Prefix: "he", Expected: "hero"},  // beyond k
+		{Prefix: "w", Expected: "world"},  // rank 1
+	}
+
+	got := Evaluate(cases, suggest, 2)
+	if !approx(got.MRR, (1+0.5+0+1)/4) {
+		t.Errorf("Expected MRR 0.625, got %v", got.MRR)
+	}
+	if !approx(got.RecallAtK, 0.75) {
+		t.Errorf("Expected recall@2 0.75, got %v", got.RecallAtK)
+	}
+	if !approx(got.PrecisionAtK, 0.375) {
+		t.Errorf("Expected precision@2 0.375, got %v", got.PrecisionAtK)
+	}
+
+	// hello and help are offered before any typing, 1 key each; world after
+	// "w", 2 keys; hero only after "her", when accepting saves nothing, so 4
+	// keys. 8 of 18 characters typed.
+	if !approx(got.KeystrokeSavings, 10.0/18) {
+		t.Errorf("Expected keystroke savings 10/18, got %v", got.KeystrokeSavings)
+	}
+	if !approx(got.KeystrokesSaved, 2.5) {
+		t.Errorf("Expected 2.5 keys saved per word, got %v", got.KeystrokesSaved)
+	}
+	// hello, help and world were accepted at ranks 1, 2 and 1; hero was
+	// typed in full.
+	if !approx(got.AcceptRank, 4.0/3) {
+		t.Errorf("Expected an accept rank of 4/3, got %v", got.AcceptRank)
+	}
+}
+
+func TestKeystrokesNeverExceedWordLength(t *testing.T) {
+	if got := Keystrokes(fixed(), nil, "hi", 3); got != 2 {
+		t.Errorf("Expected an unpredicted word to cost its 2 characters, got %d", got)
+	}
+	if got := Keystrokes(fixed("a"), nil, "a", 3); got != 1 {
+		t.Errorf("Expected a one-letter word to cost 1 key, got %d", got)
+	}
+}
+
+func TestFromTrieA1UsesContext(t *testing.T) {
+	corpus := strings.Fields("new york new york new yorker yodel yodel yodel yodel")
+	a1 := autocomplete.NewTrieA1()
+	a1.BuildFromCorpus(corpus)
+	a2 := autocomplete.NewTriesA2()
+	for _, w := range corpus {
+		a2.Insert(w)
+	}
+
+	cases := []Case{{Context: []string{"new"}, Prefix: "yo", Expected: "york"}}
+	if got := Evaluate(cases, FromTrieA1(a1), 1); got.MRR != 1 {
+		t.Errorf("Expected Algorithm_1 to rank york first after 'new', got MRR %v", got.MRR)
+	}
+	if got := Evaluate(cases, FromAutocompleter(a2), 1); got.MRR != 0 {
+		t.Errorf("Expected Algorithm_2 to rank yodel first, got MRR %v", got.MRR)
+	}
+}
+
+func TestLoadCases(t *testing.T) {
+	input := `{"context": ["new"], "prefix": "yo", "expected": "york"}
+
+{"prefix": "he", "expected": "hello"}
+`
+	cases, err := LoadCases(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cases) != 2 || cases[0].Context[0] != "new" || cases[1].Expected != "hello" {
+		t.Errorf("Expected 2 cases, got %+v", cases)
+	}
+
+	if _, err := LoadCases(strings.NewReader("{\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected an error on line 1, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package eval
+
+// -----------------------------------------
+// Corpus Cross-Validation
+// -----------------------------------------
+
+// Split divides a word sequence into a training part, the first
+// 1-testFraction of it, and the held-out rest for testing. The split is
+// contiguous so both parts keep their word order, and with it the contexts
+// the test words occur in. testFraction is clamped to [0, 1].
+func Split(corpus []string, testFraction float64) (train, test []string) {
+	testFraction = min(max(testFraction, 0), 1)
+	cut := len(corpus) - int(float64(len(corpus))*testFraction)
+	return corpus[:cut], corpus[cut:]
+}
+
+// TypingCases turns a held-out word sequence into one case per word, to be
+// typed from scratch: an empty prefix, with up to contextLen preceding test
+// words as its context. Evaluating them measures how many keystrokes each
+// algorithm saves on text it was not built from.
+func TypingCases(test []string, contextLen int) []Case {
+	cases := make([]Case, len(test))
+	for i, word := range test {
+		from := max(i-contextLen, 0)
+		cases[i] = Case{Context: test[from:i], Expected: word}
+	}
+	return cases
+}
+
+// CrossValidate builds a Suggester from the training part of corpus with
+// build and evaluates it by typing the held-out part word by word.
+func CrossValidate(corpus []string, testFraction float64, contextLen, k int, build func(train []string) Suggester) Result {
+	train, test := Split(corpus, testFraction)
+	return Evaluate(TypingCases(test, contextLen), build(train), k)
+}
@@ -0,0 +1,61 @@
+package eval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	autocomplete "auto-complete"
+)
+
+func TestSplit(t *testing.T) {
+	corpus := strings.Fields("a b c d e f g h i j")
+	train, test := Split(corpus, 0.3)
+	if strings.Join(train, "") != "abcdefg" || strings.Join(test, "") != "hij" {
+		t.Errorf("Expected abcdefg / hij, got %v / %v", train, test)
+	}
+	if train, test := Split(corpus, 2); len(train) != 0 || len(test) != 10 {
+		t.Errorf("Expected the fraction to be clamped to 1, got %v / %v", train, test)
+	}
+}
+
+func TestTypingCases(t *testing.T) {
+	got := TypingCases(strings.Fields("new york city"), 1)
+	want := []Case{
+		{Context: []string{}, Expected: "new"},
+		{Context: []string{"new"}, Expected: "york"},
+		{Context: []string{"york"}, Expected: "city"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	// The last fifth repeats a pattern seen in training, so context-aware
+	// ranking predicts it better than frequency alone.
+	corpus := strings.Fields(strings.Repeat("the cat sat on the mat ", 6) + "theory theory theory theory " + strings.Repeat("the cat ", 5))
+
+	a1 := CrossValidate(corpus, 0.2, 1, 1, func(train []string) Suggester {
+		trie := autocomplete.NewTrieA1()
+		trie.BuildFromCorpus(train)
+		return FromTrieA1(trie)
+	})
+	a2 := CrossValidate(corpus, 0.2, 1, 1, func(train []string) Suggester {
+		trie := autocomplete.NewTriesA2()
+		for _, w := range train {
+			trie.Insert(w)
+		}
+		return FromAutocompleter(trie)
+	})
+
+	if a1.Cases != 10 || a2.Cases != 10 {
+		t.Fatalf("Expected 10 held-out words, got %d and %d", a1.Cases, a2.Cases)
+	}
+	if a1.KeystrokeSavings <= a2.KeystrokeSavings {
+		t.Errorf("Expected Algorithm_1 to save more keystrokes than Algorithm_2, got %v and %v", a1.KeystrokeSavings, a2.KeystrokeSavings)
+	}
+	if a1.AcceptRank != 1 {
+		t.Errorf("Expected every accepted word to be ranked first with k=1, got %v", a1.AcceptRank)
+	}
+}
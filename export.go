@@ -0,0 +1,39 @@
+package autocomplete
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// -----------------------------------------
+// Dictionary Export
+// -----------------------------------------
+
+// esCompletion is one entry of the Elasticsearch/OpenSearch completion
+// suggester input format.
+type esCompletion struct {
+	Input  []string `json:"input"`
+	Weight int      `json:"weight"`
+}
+
+// ExportES writes every word as newline-delimited JSON in the format expected
+// by the Elasticsearch/OpenSearch completion suggester, using the word's
+// frequency as its weight: {"input":["hello"],"weight":3}. Words are written
+// in lexicographic order.
+func (t *TriesA2) ExportES(w io.Writer) error {
+	var entries []Suggestion
+	collectEntriesA2(t.root, "", &entries)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Word < entries[j].Word
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, e := range entries {
+		if err := enc.Encode(esCompletion{Input: []string{e.Word}, Weight: e.Frequency}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
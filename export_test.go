@@ -0,0 +1,51 @@
+package autocomplete
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestExportES(t *testing.T) {
+	corpus := []string{"hello", "hello", "world", `say "hi"`, `back\slash`, "tab\there", "<tag>"}
+	trie := buildAlg2Trie(corpus)
+
+	var buf bytes.Buffer
+	if err := trie.ExportES(&buf); err != nil {
+		t.Fatalf("ExportES failed: %v", err)
+	}
+
+	want := map[string]int{
+		"hello":      2,
+		"world":      1,
+		`say "hi"`:   1,
+		`back\slash`: 1,
+		"tab\there":  1,
+		"<tag>":      1,
+	}
+	got := map[string]int{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry struct {
+			Input  []string `json:"input"`
+			Weight int      `json:"weight"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		if len(entry.Input) != 1 {
+			t.Fatalf("Expected one input per line, got %v", entry.Input)
+		}
+		got[entry.Input[0]] = entry.Weight
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("Expected %d lines, got %d", len(want), len(got))
+	}
+	for word, weight := range want {
+		if got[word] != weight {
+			t.Errorf("Word %q: expected weight %d, got %d", word, weight, got[word])
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package autocomplete
+
+import "strings"
+
+// -----------------------------------------
+// Fallback Suggestion Sources
+// -----------------------------------------
+
+// SuggestionSource supplies completions from outside the trie, such as a
+// static list of popular words or a remote service.
+type SuggestionSource interface {
+	Suggest(prefix string, k int) []Suggestion
+}
+
+// StaticSource suggests words from a fixed list, in list order.
+type StaticSource []string
+
+// Suggest returns up to k words from the list that start with prefix. They
+// carry a probability of zero since they come from outside the model.
+func (s StaticSource) Suggest(prefix string, k int) []Suggestion {
+	var suggestions []Suggestion
+	for _, word := range s {
+		if len(suggestions) >= k {
+			break
+		}
+		if strings.HasPrefix(word, prefix) {
+			suggestions = append(suggestions, Suggestion{Word: word})
+		}
+	}
+	return suggestions
+}
+
+// SetFallback registers a source that Autocomplete consults only when the
+// trie yields fewer than k suggestions, e.g. on a cold start. Its results are
+// appended after the trie's own and words the trie already returned are
+// skipped. Passing nil removes the fallback.
+func (t *TrieA1) SetFallback(source SuggestionSource) {
+	t.fallback = source
+	t.cache.clear()
+}
+
+func (t *TrieA1) fillFromFallback(prefix string, k int, suggestions []Suggestion) []Suggestion {
+	if t.fallback == nil || len(suggestions) >= k {
+		return suggestions
+	}
+
+	seen := make(map[string]bool, len(suggestions))
+	for _, s := range suggestions {
+		seen[s.Word] = true
+	}
+	for _, s := range t.fallback.Suggest(prefix, k) {
+		if len(suggestions) >= k {
+			break
+		}
+		if !seen[s.Word] && t.filter.allows(s.Word) {
+			seen[s.Word] = true
+			suggestions = append(suggestions, s)
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,34 @@
+package autocomplete
+
+import "testing"
+
+func TestFallbackFillsEmptyTrie(t *testing.T) {
+	trie := NewTrieA1()
+	trie.SetFallback(StaticSource{"hello", "help", "world", "hero"})
+
+	suggestions := trie.Autocomplete("he", 2)
+	if len(suggestions) != 2 || suggestions[0].Word != "hello" || suggestions[1].Word != "help" {
+		t.Errorf("Expected the fallback to supply 'hello' and 'help', got %v", suggestions)
+	}
+}
+
+func TestFallbackMergesAndDedupes(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hero", "hero", "helium"})
+	trie.SetFallback(StaticSource{"hero", "hello", "help"})
+
+	suggestions := trie.Autocomplete("he", 4)
+	want := []string{"hero", "helium", "hello", "help"}
+	if len(suggestions) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, suggestions)
+	}
+	for i, w := range want {
+		if suggestions[i].Word != w {
+			t.Errorf("Position %d: expected '%s', got '%s'", i, w, suggestions[i].Word)
+		}
+	}
+
+	// The fallback is not consulted when the trie has enough results.
+	if got := trie.Autocomplete("he", 2); len(got) != 2 || got[1].Word != "helium" {
+		t.Errorf("Expected only trie results when k is satisfied, got %v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package autocomplete
+
+import "strings"
+
+// -----------------------------------------
+// Blocklist and Filtering
+// -----------------------------------------
+
+// Filter reports whether word may be suggested.
+type Filter func(word string) bool
+
+// wordFilter hides words from results while they stay in the trie, so
+// their counts still shape context statistics. The zero value hides
+// nothing.
+type wordFilter struct {
+	blocked map[string]bool // lower-cased
+	keep    Filter
+}
+
+// active reports whether the filter hides any word at all.
+func (f wordFilter) active() bool {
+	return f.blocked != nil || f.keep != nil
+}
+
+func (f wordFilter) allows(word string) bool {
+	if f.blocked[strings.ToLower(word)] {
+		return false
+	}
+	return f.keep == nil || f.keep(word)
+}
+
+// apply filters entries in place to the words it allows.
+func (f wordFilter) apply(entries []Suggestion) []Suggestion {
+	if !f.active() {
+		return entries
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if f.allows(e.Word) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func newBlocklist(words []string) map[string]bool {
+	if len(words) == 0 {
+		return nil
+	}
+	blocked := make(map[string]bool, len(words))
+	for _, w := range words {
+		blocked[strings.ToLower(w)] = true
+	}
+	return blocked
+}
+
+// SetBlocklist hides words from every suggestion, ignoring case, even though
+// they remain in the trie and its context tables. It replaces any previous
+// blocklist; nil or an empty list clears it.
+func (t *TrieA1) SetBlocklist(words []string) {
+	t.filter.blocked = newBlocklist(words)
+	t.cache.clear()
+}
+
+// SetFilter hides every word for which keep returns false from suggestions,
+// in addition to the blocklist. nil removes the filter.
+func (t *TrieA1) SetFilter(keep Filter) {
+	t.filter.keep = keep
+	t.cache.clear()
+}
+
+// SetBlocklist hides words from every suggestion, ignoring case, even though
+// they remain in the trie. It replaces any previous blocklist; nil or an
+// empty list clears it.
+func (t *TriesA2) SetBlocklist(words []string) {
+	t.filter.blocked = newBlocklist(words)
+}
+
+// SetFilter hides every word for which keep returns false from suggestions,
+// in addition to the blocklist. nil removes the filter.
+func (t *TriesA2) SetFilter(keep Filter) {
+	t.filter.keep = keep
+}
@@ -0,0 +1,113 @@
+package autocomplete
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestBlocklistAndFilter(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "Heck", "heck", "help", "helium"}
+	a1, a2 := buildAlg1Trie(corpus), buildAlg2Trie(corpus)
+	a1.EnableCache()
+	a1.Autocomplete("he", 5)
+
+	a1.SetBlocklist([]string{"HECK"})
+	a2.SetBlocklist([]string{"HECK"})
+	noHelium := func(word string) bool { return !strings.HasPrefix(word, "heli") }
+	a1.SetFilter(noHelium)
+	a2.SetFilter(noHelium)
+
+	tries := map[string]interface {
+		Autocomplete(string, int) []Suggestion
+		Len() int
+	}{"Algorithm_1": a1, "Algorithm_2": a2}
+	for name, trie := range tries {
+		got := trie.Autocomplete("he", 5)
+		if words := Words(got); !reflect.DeepEqual(words, []string{"hello", "help"}) {
+			t.Errorf("%s: expected [hello help], got %v", name, words)
+		}
+		if got[0].Score != 0.75 {
+			t.Errorf("%s: expected hidden words to take no probability, got %v", name, got[0].Score)
+		}
+		if trie.Len() != 5 {
+			t.Errorf("%s: expected hidden words to stay stored, got %d words", name, trie.Len())
+		}
+	}
+
+	a1.SetBlocklist(nil)
+	a1.SetFilter(nil)
+	if got := a1.Autocomplete("he", 5); len(got) != 4 {
+		t.Errorf("Expected every word after clearing the blocklist and filter, got %v", got)
+	}
+}
+
+func TestBlocklistHidesFollowersAndFallback(t *testing.T) {
+	trie := NewTrieA1()
+	trie.BuildFromCorpus([]string{"oh", "heck", "oh", "heck", "oh", "hello"})
+	trie.SetFallback(StaticSource{"heck", "hexagon"})
+	trie.SetBlocklist([]string{"heck"})
+
+	if got := Words(trie.PredictNext([]string{"oh"}, 3)); !reflect.DeepEqual(got, []string{"hello"}) {
+		t.Errorf("Expected only hello after 'oh', got %v", got)
+	}
+	if got := Words(trie.Autocomplete("he", 3)); !reflect.DeepEqual(got, []string{"hello", "hexagon"}) {
+		t.Errorf("Expected the blocked fallback word to be skipped, got %v", got)
+	}
+}
+
+func TestBlocklistHidesEveryEntryPoint(t *testing.T) {
+	corpus := []string{"heck", "heck", "heck", "hecto", "hello", "hello", "help", "hex/a", "hex/b"}
+	a1, a2 := buildAlg1Trie(corpus), buildAlg2Trie(corpus)
+	a1.SetBlocklist([]string{"HECK"})
+	a2.SetBlocklist([]string{"HECK"})
+	personalizer := NewPersonalizer(a1)
+	personalizer.Record("alice", "heck")
+
+	nth, _ := a1.NthCompletion("he", 1)
+	var tiered []string
+	for _, tier := range a2.AutocompleteTiered("he", 10) {
+		tiered = append(tiered, tier.Words...)
+	}
+	var infix []string
+	for _, m := range a2.SearchInfix("ec") {
+		infix = append(infix, m.Word)
+	}
+	var segments []string
+	for _, s := range a2.AutocompleteSegment("he", '/', 10) {
+		segments = append(segments, s.Key)
+	}
+	suffix, _ := a2.CompleteSuffix("hec")
+
+	results := map[string][]string{
+		"NthCompletion":       {nth.Word},
+		"SampleAutocomplete":  Words(a1.SampleAutocomplete("he", 10, 1)),
+		"AutocompleteSorted":  a2.AutocompleteSorted("he"),
+		"AutocompleteTiered":  tiered,
+		"CompleteSuffix":      {"hec" + suffix},
+		"AutocompleteRegex":   a2.AutocompleteRegex("he", regexp.MustCompile("c"), 5),
+		"Search":              Words(a2.Search("ec", 5)),
+		"SearchInfix":         infix,
+		"AutocompleteFuzzy":   Words(a2.AutocompleteFuzzy("hek", 1, 10)),
+		"AutocompleteSegment": segments,
+		"AllTokens":           a2.AutocompleteAllTokens([]string{"ec"}, 5),
+		"CommonPrefix A1":     {a1.CommonPrefix("hec")},
+		"CommonPrefix A2":     {a2.CommonPrefix("hec")},
+		"DAWG":                Words(a2.Freeze().Autocomplete("he", 10)),
+		"AutocompleteForUser": Words(personalizer.AutocompleteForUser("alice", "he", 10)),
+	}
+	for name, words := range results {
+		if len(words) == 0 {
+			t.Errorf("%s: expected suggestions besides the blocked word, got none", name)
+		}
+		for _, word := range words {
+			if word == "heck" {
+				t.Errorf("%s: expected the blocked word to be hidden, got %v", name, words)
+			}
+		}
+	}
+	if got := a2.Freeze().Len(); got != 5 {
+		t.Errorf("Expected the DAWG to leave out the blocked word, got %d words", got)
+	}
+}
@@ -0,0 +1,123 @@
+package autocomplete
+
+import (
+	"slices"
+	"sort"
+)
+
+// -----------------------------------------
+// Finalized Top-K Lists
+// -----------------------------------------
+
+// Finalize stores at every node its k best completions, ranked and scored
+// as a plain frequency query ranks and scores them. Such queries, asking
+// for at most k suggestions, then cost a walk down the prefix and a copy of
+// the stored list instead of a search of the subtree. This trades a pass
+// over the trie and up to k suggestions per node of memory for query
+// latency, and suits large dictionaries that no longer change: inserting,
+// deleting, promoting, merging or loading words drops the lists, and
+// queries search the subtrees again until Finalize is called anew. A
+// non-positive k drops the lists.
+func (t *TrieA1) Finalize(k int) {
+	if k <= 0 {
+		t.unfinalize()
+		return
+	}
+	finalizeNode(t.root, "", k)
+	t.finalK = k
+}
+
+// Finalized returns the k of the last Finalize, or 0 if the words changed
+// since or Finalize was never called.
+func (t *TrieA1) Finalized() int {
+	return t.finalK
+}
+
+// finalizeNode stores the k best completions at node, whose path is prefix,
+// and every node below it. A node's list is the best k among its own word
+// and its children's lists, so each list is built from the ones below.
+//
+// The walk visits the nodes in post-order on its own stack, spelling words
+// in one path buffer as collectCompletionsLimit does, so deep tries grow
+// neither the goroutine stack nor a copy of the path per node. A node stays
+// on the stack while its children are finalized above it; their runes only
+// ever overwrite the buffer past its own.
+func finalizeNode(node *TrieNodeA1, prefix string, k int) {
+	type frame struct {
+		pathEntry
+		// expanded is set once the node's children are on the stack, so
+		// reaching it again means they all have their lists.
+		expanded bool
+	}
+	start := []rune(prefix)
+	path := slices.Clone(start)
+	stack := []frame{{pathEntry: pathEntry{node: node, length: len(start)}}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		path = path[:top.length]
+		if top.length > len(start) {
+			path[top.length-1] = top.char
+		}
+		if top.expanded {
+			finalizeList(top.node, path, k)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		top.expanded = true
+		// Room for the children's rune, as in collectCompletionsLimit.
+		path = slices.Grow(path, 1)
+		current := top.pathEntry
+		current.node.eachChild(func(char rune, child *TrieNodeA1) {
+			stack = append(stack, frame{pathEntry: pathEntry{node: child, char: char, length: current.length + 1}})
+		})
+	}
+}
+
+// finalizeList stores the k best completions at node, whose path is path,
+// once its children have theirs.
+func finalizeList(node *TrieNodeA1, path []rune, k int) {
+	var candidates []Suggestion
+	if node.isEnd {
+		candidates = append(candidates, Suggestion{Word: string(path), Frequency: node.frequency})
+	}
+	node.eachChild(func(_ rune, child *TrieNodeA1) {
+		candidates = append(candidates, child.top...)
+	})
+	// The children's lists carry scores relative to the children, so score
+	// every candidate against this node before ranking them.
+	total := float64(node.frequencySum)
+	for i := range candidates {
+		candidates[i].Score = float64(candidates[i].Frequency) / total
+	}
+	sort.Slice(candidates, func(i, j int) bool { return rankBefore(candidates[i], candidates[j]) })
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	node.top = slices.Clip(candidates)
+}
+
+// finalTop returns a copy of the first k suggestions stored at node by
+// Finalize, or false if Finalize stored fewer than k per node.
+func (t *TrieA1) finalTop(node *TrieNodeA1, k int) ([]Suggestion, bool) {
+	if k <= 0 || k > t.finalK {
+		return nil, false
+	}
+	return append([]Suggestion(nil), node.top[:min(k, len(node.top))]...), true
+}
+
+// unfinalize drops the lists of Finalize once the words they rank change.
+func (t *TrieA1) unfinalize() {
+	if t.finalK == 0 {
+		return
+	}
+	t.finalK = 0
+	// On its own stack, as finalizeNode walks, since this runs on every
+	// change to a finalized trie however deep.
+	stack := []*TrieNodeA1{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node.top = nil
+		node.eachChild(func(_ rune, child *TrieNodeA1) { stack = append(stack, child) })
+	}
+}
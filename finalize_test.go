@@ -0,0 +1,92 @@
+package autocomplete
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFinalizeMatchesSearch(t *testing.T) {
+	corpus := syntheticCorpus(3000)
+	searched := buildAlg1Trie(corpus)
+	finalized := buildAlg1Trie(corpus)
+	finalized.Finalize(5)
+
+	if finalized.Finalized() != 5 {
+		t.Fatalf("Expected the trie finalized for 5, got %d", finalized.Finalized())
+	}
+	for _, prefix := range []string{"", "a", "b1", "c28", "z", "q"} {
+		for _, k := range []int{0, 1, 3, 5, 8} {
+			want, got := searched.Autocomplete(prefix, k), finalized.Autocomplete(prefix, k)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("For prefix %q and k %d: expected %v, got %v", prefix, k, want, got)
+			}
+		}
+	}
+	if node := finalized.searchPrefix("a"); len(node.top) != 5 {
+		t.Errorf("Expected 5 stored completions, got %d", len(node.top))
+	}
+}
+
+func TestFinalizeDeepTrie(t *testing.T) {
+	// Far deeper than any word, to show the walk does not recurse.
+	long := strings.Repeat("ab", 50000)
+	trie := buildAlg1Trie([]string{long, long, long + "c", long[:1000] + "x"})
+	trie.Finalize(2)
+
+	want := []string{long, long + "c"}
+	if got := Words(trie.Autocomplete("a", 2)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the 2 long words, got %d words", len(got))
+	}
+	if got := Words(trie.Autocomplete(long[:999], 2)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the 2 long words below a deep prefix, got %d words", len(got))
+	}
+	if got := trie.Autocomplete(long[:1000]+"x", 2); len(got) != 1 || got[0].Word != long[:1000]+"x" {
+		t.Errorf("Expected only the word branching off deep down, got %d words", len(got))
+	}
+
+	// Inserting drops the lists all the way down, again without recursing.
+	trie.Insert(long + "c")
+	trie.Insert(long + "c")
+	if trie.Finalized() != 0 {
+		t.Errorf("Expected the insert to drop the lists, got k = %d", trie.Finalized())
+	}
+	trie.Finalize(2)
+	want = []string{long + "c", long}
+	if got := Words(trie.Autocomplete(long[:999], 2)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the reinserted word first after finalizing again, got %d words", len(got))
+	}
+}
+
+func TestFinalizeDroppedByChanges(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "help"})
+	trie.Finalize(3)
+
+	trie.Insert("helium")
+	trie.Insert("helium")
+	trie.Insert("helium")
+	if trie.Finalized() != 0 || trie.root.top != nil {
+		t.Fatalf("Expected Insert to drop the stored completions")
+	}
+	if got := trie.Autocomplete("hel", 1); got[0].Word != "helium" {
+		t.Errorf("Expected 'helium' first after the insert, got %v", got)
+	}
+
+	trie.Finalize(3)
+	trie.Delete("helium")
+	if trie.Finalized() != 0 {
+		t.Errorf("Expected Delete to drop the stored completions")
+	}
+
+	trie.Finalize(3)
+	trie.Promote("help")
+	if trie.Finalized() != 0 {
+		t.Errorf("Expected Promote to drop the stored completions")
+	}
+
+	trie.Finalize(3)
+	trie.Finalize(0)
+	if trie.Finalized() != 0 || trie.searchPrefix("hel").top != nil {
+		t.Errorf("Expected Finalize(0) to drop the stored completions")
+	}
+}
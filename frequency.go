@@ -0,0 +1,178 @@
+package autocomplete
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// -----------------------------------------
+// Word-Frequency Lists
+// -----------------------------------------
+
+// progressEvery is how many rows a FrequencyLoader reads between calls to
+// its Progress function.
+const progressEvery = 100000
+
+// FrequencyLoader reads pre-counted word-frequency lists, one word and its
+// count per row, such as the Google Books 1-grams or wordfreq exports, and
+// inserts every row with InsertWithWeight. A word listed on several rows,
+// as in the per-year Google files, accumulates the counts of all of them.
+//
+// Counts may be fractional, as relative frequencies are. Each row inserts
+// its count times Weight, rounded to the nearest whole occurrence; rows
+// that round to zero are skipped. Loading several lists into one trie, each
+// with its own Weight, blends them: a trusted list can count for more than
+// a noisy one, or lists of very different sizes can be brought to a common
+// scale.
+//
+// A first row whose count does not parse is taken to be a header and
+// skipped; any later such row is an error. Only word frequencies are
+// loaded: an Algorithm_1 trie built this way has no bigram table.
+type FrequencyLoader struct {
+	// Comma separates the fields of a row. Zero means ','. With '\t' rows
+	// are split on tabs only and quotes have no special meaning; otherwise
+	// rows are parsed as CSV.
+	Comma rune
+	// CountColumn is the zero-based column holding the count; the word is
+	// always column 0. Zero means column 1.
+	CountColumn int
+	// Weight scales every count read. Zero means 1; a negative Weight skips
+	// every row.
+	Weight float64
+	// Progress, if set, is called every 100,000 rows and once at the end
+	// with the number of rows and bytes read so far.
+	Progress func(rows int, bytes int64)
+}
+
+// LoadFrequencyCSV inserts the word,count rows of the CSV file at path into
+// trie.
+func LoadFrequencyCSV(path string, trie Autocompleter) error {
+	return FrequencyLoader{Comma: ','}.LoadFile(path, trie)
+}
+
+// LoadFrequencyTSV inserts the word<TAB>count rows of the file at path into
+// trie.
+func LoadFrequencyTSV(path string, trie Autocompleter) error {
+	return FrequencyLoader{Comma: '\t'}.LoadFile(path, trie)
+}
+
+// LoadFile inserts the rows of the file at path into trie.
+func (l FrequencyLoader) LoadFile(path string, trie Autocompleter) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := l.LoadReader(f, trie); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadReader inserts the rows read from r into trie. Rows inserted before
+// an error are kept.
+func (l FrequencyLoader) LoadReader(r io.Reader, trie Autocompleter) error {
+	comma, column, weight := l.Comma, l.CountColumn, l.Weight
+	if comma == 0 {
+		comma = ','
+	}
+	if column == 0 {
+		column = 1
+	}
+	if weight == 0 {
+		weight = 1
+	}
+
+	counter := &countingReader{r: r}
+	next := csvRows(counter, comma)
+	if comma == '\t' {
+		next = tsvRows(counter)
+	}
+
+	rows := 0
+	for {
+		fields, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		rows++
+		if l.Progress != nil && rows%progressEvery == 0 {
+			l.Progress(rows, counter.n)
+		}
+
+		if len(fields) <= column {
+			return fmt.Errorf("row %d: expected at least %d fields, got %d", rows, column+1, len(fields))
+		}
+		count, err := strconv.ParseFloat(strings.TrimSpace(fields[column]), 64)
+		if err != nil || math.IsNaN(count) || math.IsInf(count, 0) {
+			if rows == 1 {
+				continue
+			}
+			return fmt.Errorf("row %d: invalid count %q", rows, fields[column])
+		}
+		// InsertWithWeight ignores the rows rounding below one.
+		trie.InsertWithWeight(fields[0], roundWeight(count*weight))
+	}
+	if l.Progress != nil {
+		l.Progress(rows, counter.n)
+	}
+	return nil
+}
+
+// roundWeight rounds a fractional weight to whole occurrences, capped so it
+// cannot overflow a frequency. NaN and non-positive weights give zero.
+func roundWeight(weight float64) int {
+	if math.IsNaN(weight) || weight <= 0 {
+		return 0
+	}
+	return int(min(math.Round(weight), math.MaxInt32))
+}
+
+// csvRows returns an iterator over the CSV records of r.
+func csvRows(r io.Reader, comma rune) func() ([]string, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return cr.Read
+}
+
+// tsvRows returns an iterator over the tab-separated rows of r, skipping
+// blank lines.
+func tsvRows(r io.Reader) func() ([]string, error) {
+	br := bufio.NewReader(r)
+	return func() ([]string, error) {
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+				return nil, err
+			}
+			line = strings.TrimRight(line, "\r\n")
+			if line != "" {
+				return strings.Split(line, "\t"), nil
+			}
+		}
+	}
+}
+
+// countingReader counts the bytes read through it for progress reports.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
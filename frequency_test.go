@@ -0,0 +1,105 @@
+package autocomplete
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFrequencyCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freq.csv")
+	content := "word,count\nthe,5000\nthen,120\n\"they,re\",7\nthere,300\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, newTrie := range implementations() {
+		trie := newTrie()
+		if err := LoadFrequencyCSV(path, trie); err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if trie.Len() != 4 {
+			t.Errorf("%s: expected 4 words, got %d", name, trie.Len())
+		}
+		got := trie.Autocomplete("the", 4)
+		if words := Words(got); strings.Join(words, " ") != "the there then they,re" {
+			t.Errorf("%s: expected [the there then they,re], got %v", name, words)
+		}
+		if got[0].Frequency != 5000 {
+			t.Errorf("%s: expected 'the' to have frequency 5000, got %d", name, got[0].Frequency)
+		}
+	}
+}
+
+func TestFrequencyLoaderTSVColumnsAndProgress(t *testing.T) {
+	// Google 1-gram rows: word, year, match count, volume count.
+	input := "apple\t2000\t40\t3\r\napple\t2001\t60\t4\n\napply\t2000\t70\t5\n"
+	var rows int
+	var bytes int64
+	loader := FrequencyLoader{
+		Comma:       '\t',
+		CountColumn: 2,
+		Progress:    func(r int, b int64) { rows, bytes = r, b },
+	}
+
+	trie := NewTriesA2()
+	if err := loader.LoadReader(strings.NewReader(input), trie); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := trie.Frequency("apple"); got != 100 {
+		t.Errorf("Expected apple's yearly counts to add up to 100, got %d", got)
+	}
+	if got := trie.Frequency("apply"); got != 70 {
+		t.Errorf("Expected apply to have frequency 70, got %d", got)
+	}
+	if rows != 3 || bytes != int64(len(input)) {
+		t.Errorf("Expected a final progress report of 3 rows and %d bytes, got %d and %d", len(input), rows, bytes)
+	}
+}
+
+func TestFrequencyLoaderWeights(t *testing.T) {
+	// Relative frequencies from one list and raw counts from another.
+	books := "the\t0.05\nthen\t0.0004\nthere\t0.001\n"
+	chat := "the,12\nthx,30\nthere,3\n"
+
+	trie := NewTriesA2()
+	if err := (FrequencyLoader{Comma: '\t', Weight: 1e5}).LoadReader(strings.NewReader(books), trie); err != nil {
+		t.Fatal(err)
+	}
+	if err := (FrequencyLoader{Weight: 0.5}).LoadReader(strings.NewReader(chat), trie); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"the": 5006, "then": 40, "there": 102, "thx": 15}
+	for word, frequency := range want {
+		if got := trie.Frequency(word); got != frequency {
+			t.Errorf("For %s: expected frequency %d, got %d", word, frequency, got)
+		}
+	}
+
+	// Counts that scale below half an occurrence are skipped.
+	trie = NewTriesA2()
+	if err := (FrequencyLoader{Weight: 0.1}).LoadReader(strings.NewReader(chat), trie); err != nil {
+		t.Fatal(err)
+	}
+	if trie.Contains("there") || trie.Frequency("thx") != 3 {
+		t.Errorf("Expected 'there' to be skipped and 'thx' to have frequency 3, got %d words", trie.Len())
+	}
+}
+
+func TestFrequencyLoaderErrors(t *testing.T) {
+	cases := map[string]string{
+		"bad count":      "the,1\nthen,many\n",
+		"missing column": "the,1\nthen\n",
+		"infinite count": "the,1\nthen,Inf\n",
+	}
+	for name, input := range cases {
+		trie := NewTriesA2()
+		if err := (FrequencyLoader{}).LoadReader(strings.NewReader(input), trie); err == nil || !strings.Contains(err.Error(), "row 2") {
+			t.Errorf("For %s: expected an error on row 2, got %v", name, err)
+		}
+		if trie.Frequency("the") != 1 {
+			t.Errorf("For %s: expected rows before the error to be kept", name)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package autocomplete
+
+import "slices"
+
+// -----------------------------------------
+// Fuzzy Autocomplete
+// -----------------------------------------
+
+// AutocompleteFuzzy returns up to k words that complete prefix after at most
+// maxEdits typos, so "hleicop" still finds "helicopter". A typo is an
+// inserted, deleted or substituted character, or two swapped neighbouring
+// characters.
+//
+// A word's edit distance is the smallest distance between prefix and any
+// prefix of the word. Its score is its share of the candidates' total
+// frequency divided by 1+distance, so exact completions win unless a fuzzy
+// one is much more frequent. With WithKeyboard, substituting a neighbouring
+// key counts as only part of an edit. A negative maxEdits is treated as zero.
+func (t *TriesA2) AutocompleteFuzzy(prefix string, maxEdits, k int) []Suggestion {
+	if maxEdits < 0 {
+		maxEdits = 0
+	}
+	query := []rune(prefix)
+	budget := float64(maxEdits)
+
+	// Each trie level extends one row of the edit-distance table between the
+	// current path and the query; the previous two rows are kept for swaps.
+	firstRow := make([]float64, len(query)+1)
+	for j := range firstRow {
+		firstRow[j] = float64(j)
+	}
+
+	var matches []Suggestion
+	var distances []float64
+	record := func(node *NodeA2, path []rune, distance float64) {
+		if !node.isEndOfWord {
+			return
+		}
+		if word := string(path); t.filter.allows(word) {
+			matches = append(matches, Suggestion{Word: word, Frequency: node.frequency})
+			distances = append(distances, distance)
+		}
+	}
+
+	// collectAll gathers the subtree of a node that already matched once no
+	// deeper node can lower the distance any further.
+	var collectAll func(*NodeA2, []rune, float64)
+	collectAll = func(node *NodeA2, path []rune, distance float64) {
+		record(node, path, distance)
+		for char, child := range node.children {
+			collectAll(child, append(path, char), distance)
+		}
+	}
+
+	var dfs func(node *NodeA2, path []rune, prev, prevPrev []float64, best float64)
+	dfs = func(node *NodeA2, path []rune, prev, prevPrev []float64, best float64) {
+		if d := prev[len(query)]; d < best {
+			best = d
+		}
+		if slices.Min(prev) > budget || best == 0 {
+			if best <= budget {
+				collectAll(node, path, best)
+			}
+			return
+		}
+		record(node, path, best)
+
+		for char, child := range node.children {
+			dfs(child, append(path, char), nextWeightedRow(query, path, char, prev, prevPrev, t.keyboard.substitutionCost), prev, best)
+		}
+	}
+	dfs(t.root, nil, firstRow, nil, budget+1)
+
+	total := 0
+	for _, m := range matches {
+		total += m.Frequency
+	}
+	for i := range matches {
+		matches[i].Score = float64(matches[i].Frequency) / float64(total) / (1 + distances[i])
+	}
+	return topK(matches, k)
+}
+
+// nextEditRow extends the optimal string alignment table between query and
+// path by one rune: given the rows for path and for path without its last
+// rune, it returns the row for path followed by char. Entry j is the
+// distance between query[:j] and the extended path.
+func nextEditRow(query, path []rune, char rune, prev, prevPrev []int) []int {
+	return nextWeightedRow(query, path, char, prev, prevPrev, unitSubstitution)
+}
+
+// nextWeightedRow is nextEditRow with substitutions costing substitute(a,
+// b) for typing a where b was meant; every other edit costs 1.
+func nextWeightedRow[D int | float64](query, path []rune, char rune, prev, prevPrev []D, substitute func(a, b rune) D) []D {
+	row := make([]D, len(query)+1)
+	row[0] = prev[0] + 1
+	for j := 1; j <= len(query); j++ {
+		row[j] = min(prev[j]+1, row[j-1]+1, prev[j-1]+substitute(query[j-1], char))
+		if prevPrev != nil && j > 1 && query[j-2] == char && query[j-1] == path[len(path)-1] {
+			row[j] = min(row[j], prevPrev[j-2]+1)
+		}
+	}
+	return row
+}
+
+func unitSubstitution(a, b rune) int {
+	if a == b {
+		return 0
+	}
+	return 1
+}
+
+func minInts(values []int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		m = min(m, v)
+	}
+	return m
+}
@@ -0,0 +1,44 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAutocompleteFuzzy(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "hello", "hello", "help", "helicopter", "helicopter", "world"})
+
+	tests := []struct {
+		prefix   string
+		maxEdits int
+		want     []string
+	}{
+		{"hleicop", 1, []string{"helicopter"}},
+		{"wrld", 1, []string{"world"}},
+		{"wrld", 0, []string{}},
+		{"hel", 0, []string{"hello", "helicopter", "help"}},
+		{"hel", -1, []string{"hello", "helicopter", "help"}},
+		{"xyz", 2, []string{}},
+	}
+	for _, test := range tests {
+		got := Words(trie.AutocompleteFuzzy(test.prefix, test.maxEdits, 5))
+		if len(got) == 0 {
+			got = []string{}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("For %q with %d edits: expected %v, got %v", test.prefix, test.maxEdits, test.want, got)
+		}
+	}
+}
+
+func TestAutocompleteFuzzyPrefersExactMatches(t *testing.T) {
+	trie := buildAlg2Trie([]string{"cart", "care", "card", "card", "card", "card", "card"})
+
+	// cart is exact (1/7) and beats care, which is one edit away (1/7/2),
+	// but card is frequent enough to win despite its edit (5/7/2).
+	got := Words(trie.AutocompleteFuzzy("cart", 1, 3))
+	want := []string{"card", "cart", "care"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,42 @@
+package autocomplete
+
+import "auto-complete/corpus"
+
+// -----------------------------------------
+// Grapheme-Aware Completion
+// -----------------------------------------
+
+// WithGraphemes makes queries complete whole user-perceived characters: a
+// word is only a completion of prefix if prefix ends on one of its grapheme
+// cluster boundaries. Without it the trie matches rune by rune, so "e"
+// completes a decomposed "é" (e followed by a combining accent) and "👩"
+// completes the joined emoji "👩‍💻". Combine it with a corpus.CJKCharacters
+// tokenizer to index Chinese or Japanese text, which has no spaces between
+// words, character by character.
+func (t *TrieA1) WithGraphemes(enabled bool) *TrieA1 {
+	t.graphemes = enabled
+	t.cache.clear()
+	return t
+}
+
+// WithGraphemes makes queries complete whole user-perceived characters, as
+// TrieA1.WithGraphemes does.
+func (t *TriesA2) WithGraphemes(enabled bool) *TriesA2 {
+	t.graphemes = enabled
+	return t
+}
+
+// keepWholeGraphemes drops the completions in which prefix ends inside a
+// grapheme cluster.
+func keepWholeGraphemes(prefix string, completions []Suggestion) []Suggestion {
+	if prefix == "" {
+		return completions
+	}
+	kept := completions[:0]
+	for _, c := range completions {
+		if corpus.IsGraphemeBoundary(c.Word, len(prefix)) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
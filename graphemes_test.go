@@ -0,0 +1,55 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+
+	"auto-complete/corpus"
+)
+
+func TestWithGraphemes(t *testing.T) {
+	// The accent is a combining mark and the laptop is joined to the woman.
+	words := []string{"etude", "e\u0301cole", "👩\u200d💻", "👩", "👩🏽"}
+
+	a1 := NewTrieA1()
+	a2 := NewTriesA2()
+	for _, w := range words {
+		a1.Insert(w)
+		a2.Insert(w)
+	}
+	tests := []struct {
+		prefix    string
+		runes     []string
+		graphemes []string
+	}{
+		{"e", []string{"etude", "e\u0301cole"}, []string{"etude"}},
+		{"e\u0301", []string{"e\u0301cole"}, []string{"e\u0301cole"}},
+		{"👩", []string{"👩", "👩\u200d💻", "👩🏽"}, []string{"👩"}},
+		{"", []string{"etude", "e\u0301cole", "👩", "👩\u200d💻", "👩🏽"}, []string{"etude", "e\u0301cole", "👩", "👩\u200d💻", "👩🏽"}},
+	}
+	for _, graphemes := range []bool{false, true} {
+		a1.WithGraphemes(graphemes)
+		a2.WithGraphemes(graphemes)
+		for _, test := range tests {
+			want := test.runes
+			if graphemes {
+				want = test.graphemes
+			}
+			for name, trie := range map[string]Autocompleter{"a1": a1, "a2": a2} {
+				got := Words(trie.Autocomplete(test.prefix, 10))
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s with graphemes %v, for %q: expected %q, got %q", name, graphemes, test.prefix, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestCJKCorpus(t *testing.T) {
+	trie := NewTrieA1().WithGraphemes(true).WithTokenizer(corpus.CJKCharacters(corpus.Words))
+	trie.BuildFromCorpus([]string{"我喜欢中文", "我喜欢你", "我们"})
+
+	if got, want := Words(trie.AutocompleteWithContext("我", "", 2)), []string{"喜", "们"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q after 我, got %q", want, got)
+	}
+}
@@ -0,0 +1,45 @@
+package autocomplete
+
+import "container/heap"
+
+// -----------------------------------------
+// Bounded Top-k Selection
+// -----------------------------------------
+
+// suggestionHeap is a min-heap under rankBefore: the worst of the current
+// top k sits at the root, ready to be replaced by a better candidate.
+type suggestionHeap []Suggestion
+
+func (h suggestionHeap) Len() int           { return len(h) }
+func (h suggestionHeap) Less(i, j int) bool { return rankBefore(h[j], h[i]) }
+func (h suggestionHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *suggestionHeap) Push(x any)        { *h = append(*h, x.(Suggestion)) }
+func (h *suggestionHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// topK returns the k best candidates in rank order using a size-k heap, so
+// selecting from n candidates costs O(n log k) time and O(k) extra space
+// instead of sorting all n.
+func topK(candidates []Suggestion, k int) []Suggestion {
+	k = clampK(k, len(candidates))
+	h := make(suggestionHeap, 0, k)
+	for _, c := range candidates {
+		switch {
+		case len(h) < k:
+			heap.Push(&h, c)
+		case k > 0 && rankBefore(c, h[0]):
+			h[0] = c
+			heap.Fix(&h, 0)
+		}
+	}
+
+	result := make([]Suggestion, len(h))
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(&h).(Suggestion)
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package autocomplete
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestTopKMatchesFullSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	var candidates []Suggestion
+	for i := 0; i < 500; i++ {
+		// Few distinct scores so ties are exercised too.
+		candidates = append(candidates, Suggestion{Word: fmt.Sprintf("w%03d", i), Score: float64(rng.Intn(20))})
+	}
+
+	sorted := append([]Suggestion(nil), candidates...)
+	sort.Slice(sorted, func(i, j int) bool { return rankBefore(sorted[i], sorted[j]) })
+
+	for _, k := range []int{-1, 0, 1, 7, 100, 500, 1000} {
+		got := topK(candidates, k)
+		want := sorted[:clampK(k, len(sorted))]
+		if len(got) != len(want) {
+			t.Fatalf("k=%d: expected %d results, got %d", k, len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("k=%d position %d: expected %v, got %v", k, i, want[i], got[i])
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkAutocompleteHotPrefix(b *testing.B) {
+	trie := NewTrieA1()
+	for i := 0; i < 100000; i++ {
+		trie.Insert(fmt.Sprintf("a%d", i%50000))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie.Autocomplete("a", 10)
+	}
+}
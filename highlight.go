@@ -0,0 +1,106 @@
+package autocomplete
+
+import (
+	"slices"
+	"strings"
+)
+
+// -----------------------------------------
+// Match Highlighting
+// -----------------------------------------
+
+// Highlight tells a frontend which part of a suggestion matched the input,
+// so it can bold it without repeating the matching itself. Offsets count
+// runes, like InfixMatch's, and End is exclusive.
+type Highlight struct {
+	// Start and End delimit the part of Word the input was matched against.
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Edits are the offsets in Word of characters that differ from the
+	// input in a fuzzy match: substituted, inserted or swapped. Characters
+	// typed but missing from Word have no offset and are not listed. Empty
+	// for exact matches.
+	Edits []int `json:"edits,omitempty"`
+}
+
+// HighlightedSuggestion is a suggestion with its match metadata. It encodes
+// to JSON as the suggestion's fields plus "highlight".
+type HighlightedSuggestion struct {
+	Suggestion
+	Highlight Highlight `json:"highlight"`
+}
+
+// HighlightMatches annotates the results of a prefix query, exact or fuzzy,
+// with what input matched in each. A word starting with input matches over
+// input's length. Otherwise input is aligned with the prefix of the word
+// closest to it in edit distance, as AutocompleteFuzzy measures it,
+// preferring the longest such prefix, and the edits of that alignment are
+// reported.
+func HighlightMatches(input string, suggestions []Suggestion) []HighlightedSuggestion {
+	query := []rune(input)
+	highlighted := make([]HighlightedSuggestion, len(suggestions))
+	for i, s := range suggestions {
+		highlighted[i] = HighlightedSuggestion{Suggestion: s, Highlight: highlight(query, s.Word)}
+	}
+	return highlighted
+}
+
+func highlight(query []rune, word string) Highlight {
+	if strings.HasPrefix(word, string(query)) {
+		return Highlight{End: len(query)}
+	}
+	runes := []rune(word)
+
+	// d[i][j] is the optimal string alignment distance between the first i
+	// runes of the query and the first j runes of the word.
+	d := make([][]int, len(query)+1)
+	for i := range d {
+		d[i] = make([]int, len(runes)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(query); i++ {
+		for j := 1; j <= len(runes); j++ {
+			cost := 1
+			if query[i-1] == runes[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && query[i-1] == runes[j-2] && query[i-2] == runes[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	end := 0
+	last := d[len(query)]
+	for j := range last {
+		if last[j] <= last[end] {
+			end = j
+		}
+	}
+
+	// Walk the alignment back from the chosen end, matches first.
+	var edits []int
+	for i, j := len(query), end; i > 0 || j > 0; {
+		switch {
+		case i > 0 && j > 0 && query[i-1] == runes[j-1] && d[i][j] == d[i-1][j-1]:
+			i, j = i-1, j-1
+		case i > 1 && j > 1 && query[i-1] == runes[j-2] && query[i-2] == runes[j-1] && d[i][j] == d[i-2][j-2]+1:
+			edits = append(edits, j-1, j-2)
+			i, j = i-2, j-2
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+1:
+			edits = append(edits, j-1)
+			i, j = i-1, j-1
+		case j > 0 && d[i][j] == d[i][j-1]+1:
+			edits = append(edits, j-1)
+			j--
+		default:
+			i--
+		}
+	}
+	slices.Reverse(edits)
+	return Highlight{End: end, Edits: edits}
+}
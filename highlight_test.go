@@ -0,0 +1,56 @@
+package autocomplete
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHighlightMatches(t *testing.T) {
+	cases := []struct {
+		input, word string
+		want        Highlight
+	}{
+		{"he", "hello", Highlight{End: 2}},
+		{"", "hello", Highlight{End: 0}},
+		{"hé", "héllo", Highlight{End: 2}},
+		// Substitution: "hx" against "he".
+		{"hx", "hello", Highlight{End: 2, Edits: []int{1}}},
+		// Swap: "hleicop" against "helicop".
+		{"hleicop", "helicopter", Highlight{End: 7, Edits: []int{1, 2}}},
+		// Insertion: the word has an extra 'l'; the longest closest prefix
+		// wins over substituting "hell".
+		{"helo", "hello", Highlight{End: 5, Edits: []int{2}}},
+		// Deletion: the typed 'x' has no place in the word.
+		{"hxello", "hello", Highlight{End: 5}},
+	}
+	for _, c := range cases {
+		got := HighlightMatches(c.input, []Suggestion{{Word: c.word}})[0].Highlight
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("For %q in %q: expected %+v, got %+v", c.input, c.word, c.want, got)
+		}
+	}
+}
+
+func TestHighlightFuzzyResults(t *testing.T) {
+	trie := buildAlg2Trie([]string{"helicopter", "hello"})
+	got := HighlightMatches("hleicop", trie.AutocompleteFuzzy("hleicop", 2, 1))
+	if len(got) != 1 || got[0].Word != "helicopter" || got[0].Highlight.End != 7 {
+		t.Errorf("Expected helicopter highlighted over its first 7 runes, got %+v", got)
+	}
+}
+
+func TestHighlightedSuggestionJSON(t *testing.T) {
+	h := HighlightedSuggestion{
+		Suggestion: Suggestion{Word: "hello", Score: 0.5, Frequency: 2},
+		Highlight:  Highlight{End: 2},
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"word":"hello","score":0.5,"frequency":2,"highlight":{"start":0,"end":2}}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
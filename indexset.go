@@ -0,0 +1,239 @@
+package autocomplete
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// -----------------------------------------
+// Index Sets
+// -----------------------------------------
+
+// IndexSet manages many named Algorithm_1 tries, such as one per tenant,
+// language or dataset, so one process can serve them all. Every trie gets
+// the same configuration, indexes registered from disk are loaded on first
+// use, and each index keeps its own query count. An IndexSet is safe for
+// concurrent use, and so are the tries it hands out.
+type IndexSet struct {
+	mu        sync.RWMutex
+	configure func(*TrieA1)
+	indexes   map[string]*indexEntry
+}
+
+// ErrUnknownIndex is returned for a name that is not in the IndexSet.
+var ErrUnknownIndex = errors.New("autocomplete: unknown index")
+
+// indexEntry is one index of a set. mu guards loading: trie is set, once,
+// with it held, and err is why the last attempt to load it failed.
+type indexEntry struct {
+	path    string // snapshot to load from; empty for indexes added in memory
+	mu      sync.Mutex
+	loaded  bool
+	trie    *Concurrent
+	err     error
+	queries atomic.Int64
+}
+
+// IndexStats describes one index of an IndexSet. Loaded is false for an
+// index registered from disk and not used yet, whose other counts are then
+// zero; Error is why loading it failed, if it did.
+type IndexStats struct {
+	Loaded      bool   `json:"loaded"`
+	Words       int    `json:"words"`
+	Queries     int64  `json:"queries"`
+	CacheHits   int    `json:"cacheHits"`
+	CacheMisses int    `json:"cacheMisses"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NewIndexSet returns an empty set. configure, if not nil, is applied to
+// every trie as it is added or loaded, for shared settings such as
+// stopwords or the result cache.
+func NewIndexSet(configure func(*TrieA1)) *IndexSet {
+	return &IndexSet{configure: configure, indexes: make(map[string]*indexEntry)}
+}
+
+// Add makes trie available as name, replacing any index of that name. The
+// set takes ownership of trie; it must not be used directly afterwards.
+func (s *IndexSet) Add(name string, trie *TrieA1) {
+	s.put(name, &indexEntry{loaded: true, trie: s.wrap(trie)})
+}
+
+// Register makes the snapshot at path, written by TrieA1.Save, available as
+// name, replacing any index of that name. The file is only read when the
+// index is first used.
+func (s *IndexSet) Register(name, path string) {
+	s.put(name, &indexEntry{path: path})
+}
+
+// Reload reads the snapshot of the index called name again and swaps it in
+// without interrupting queries. An index that is not used yet, or was
+// added in memory, is left alone, while one whose last load failed is
+// loaded now. If reading fails the old trie keeps serving and the error is
+// returned.
+func (s *IndexSet) Reload(name string) error {
+	s.mu.RLock()
+	e, ok := s.indexes[name]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownIndex, name)
+	}
+	e.mu.Lock()
+	used := e.loaded || e.err != nil
+	e.mu.Unlock()
+	if e.path == "" || !used {
+		return nil
+	}
+
+	trie, err := s.read(e.path)
+	if err != nil {
+		return fmt.Errorf("autocomplete: reloading index %q: %w", name, err)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.loaded {
+		e.trie.Swap(trie)
+	} else {
+		e.trie, e.err, e.loaded = NewConcurrent(trie), nil, true
+	}
+	return nil
+}
+
+// Remove drops the index called name.
+func (s *IndexSet) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.indexes, name)
+}
+
+// Names returns the names of all indexes, sorted.
+func (s *IndexSet) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return sortedKeys(s.indexes)
+}
+
+// Get returns the index called name, loading it first if needed.
+func (s *IndexSet) Get(name string) (*Concurrent, error) {
+	e, err := s.open(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.trie, nil
+}
+
+// Autocomplete queries the index called name, counting the query in its
+// stats. A non-empty context ranks by the previous words as
+// AutocompleteContext does.
+func (s *IndexSet) Autocomplete(name, prefix string, context []string, k int) ([]Suggestion, error) {
+	e, err := s.open(name)
+	if err != nil {
+		return nil, err
+	}
+	e.queries.Add(1)
+
+	var suggestions []Suggestion
+	e.trie.View(func(a Autocompleter) {
+		suggestions = a.(*TrieA1).AutocompleteContext(prefix, context, k)
+	})
+	return suggestions, nil
+}
+
+// open returns the entry called name once its trie is loaded. A failed load
+// is tried again on the next call.
+func (s *IndexSet) open(name string) (*indexEntry, error) {
+	s.mu.RLock()
+	e, ok := s.indexes[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownIndex, name)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.loaded {
+		e.trie, e.err = s.load(e.path)
+		e.loaded = e.err == nil
+	}
+	if e.err != nil {
+		return nil, fmt.Errorf("autocomplete: loading index %q: %w", name, e.err)
+	}
+	return e, nil
+}
+
+// Stats reports on every index without loading any.
+func (s *IndexSet) Stats() map[string]IndexStats {
+	s.mu.RLock()
+	entries := make(map[string]*indexEntry, len(s.indexes))
+	for name, e := range s.indexes {
+		entries[name] = e
+	}
+	s.mu.RUnlock()
+
+	stats := make(map[string]IndexStats, len(entries))
+	for name, e := range entries {
+		stats[name] = e.stats()
+	}
+	return stats
+}
+
+func (e *indexEntry) stats() IndexStats {
+	e.mu.Lock()
+	loaded, trie, err := e.loaded, e.trie, e.err
+	e.mu.Unlock()
+
+	st := IndexStats{Queries: e.queries.Load()}
+	switch {
+	case err != nil:
+		st.Error = err.Error()
+	case loaded:
+		st.Loaded = true
+		trie.View(func(a Autocompleter) {
+			st.Words = a.Len()
+			st.CacheHits, st.CacheMisses = a.(*TrieA1).CacheStats()
+		})
+	}
+	return st
+}
+
+func (s *IndexSet) put(name string, e *indexEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.indexes[name] = e
+}
+
+func (s *IndexSet) wrap(trie *TrieA1) *Concurrent {
+	s.configureTrie(trie)
+	return NewConcurrent(trie)
+}
+
+func (s *IndexSet) configureTrie(trie *TrieA1) {
+	if s.configure != nil {
+		s.configure(trie)
+	}
+}
+
+func (s *IndexSet) load(path string) (*Concurrent, error) {
+	trie, err := s.read(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewConcurrent(trie), nil
+}
+
+// read loads and configures the snapshot at path.
+func (s *IndexSet) read(path string) (*TrieA1, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	trie := NewTrieA1()
+	if err := trie.Load(f); err != nil {
+		return nil, err
+	}
+	s.configureTrie(trie)
+	return trie, nil
+}
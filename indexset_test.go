@@ -0,0 +1,157 @@
+package autocomplete
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func saveAlg1Trie(t *testing.T, corpus []string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "index.bin")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := buildAlg1Trie(corpus).Save(f); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIndexSetRoutesByName(t *testing.T) {
+	set := NewIndexSet(nil)
+	set.Add("en", buildAlg1Trie([]string{"hello", "hello", "help"}))
+	set.Add("de", buildAlg1Trie([]string{"hallo", "haus", "haus"}))
+
+	en, err := set.Autocomplete("en", "h", nil, 1)
+	if err != nil || len(en) != 1 || en[0].Word != "hello" {
+		t.Errorf("Expected 'hello' from the en index, got %v (%v)", en, err)
+	}
+	de, err := set.Autocomplete("de", "h", nil, 1)
+	if err != nil || len(de) != 1 || de[0].Word != "haus" {
+		t.Errorf("Expected 'haus' from the de index, got %v (%v)", de, err)
+	}
+	if _, err := set.Autocomplete("fr", "h", nil, 1); err == nil {
+		t.Errorf("Expected an error for an unknown index")
+	}
+
+	names := set.Names()
+	if len(names) != 2 || names[0] != "de" || names[1] != "en" {
+		t.Errorf("Expected names [de en], got %v", names)
+	}
+	set.Remove("de")
+	if _, err := set.Get("de"); err == nil {
+		t.Errorf("Expected an error for a removed index")
+	}
+}
+
+func TestIndexSetLoadsLazily(t *testing.T) {
+	path := saveAlg1Trie(t, []string{"the", "cat", "the", "car", "the", "cat"})
+	set := NewIndexSet(nil)
+	set.Register("pets", path)
+
+	if st := set.Stats()["pets"]; st.Loaded || st.Words != 0 {
+		t.Errorf("Expected the index not to be loaded before first use, got %+v", st)
+	}
+
+	suggestions, err := set.Autocomplete("pets", "ca", []string{"the"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 2 || suggestions[0].Word != "cat" {
+		t.Errorf("Expected 'cat' first, got %v", suggestions)
+	}
+	if st := set.Stats()["pets"]; !st.Loaded || st.Words != 3 || st.Queries != 1 {
+		t.Errorf("Expected a loaded index of 3 words and 1 query, got %+v", st)
+	}
+}
+
+func TestIndexSetReportsLoadErrors(t *testing.T) {
+	set := NewIndexSet(nil)
+	set.Register("missing", filepath.Join(t.TempDir(), "missing.bin"))
+
+	if _, err := set.Get("missing"); err == nil {
+		t.Errorf("Expected an error loading a missing snapshot")
+	}
+	if st := set.Stats()["missing"]; st.Loaded || st.Error == "" {
+		t.Errorf("Expected the load error in the stats, got %+v", st)
+	}
+}
+
+func TestIndexSetSharedConfiguration(t *testing.T) {
+	path := saveAlg1Trie(t, []string{"hello", "hell"})
+	set := NewIndexSet(func(trie *TrieA1) { trie.EnableCache() })
+	set.Add("memory", buildAlg1Trie([]string{"hello"}))
+	set.Register("disk", path)
+
+	for _, name := range []string{"memory", "disk"} {
+		set.Autocomplete(name, "he", nil, 5)
+		set.Autocomplete(name, "he", nil, 5)
+		if st := set.Stats()[name]; st.CacheHits != 1 || st.CacheMisses != 1 {
+			t.Errorf("Expected index %s to cache its queries, got %+v", name, st)
+		}
+	}
+}
+
+func TestIndexSetReload(t *testing.T) {
+	path := saveAlg1Trie(t, []string{"hello"})
+	set := NewIndexSet(nil)
+	set.Register("en", path)
+	if err := set.Reload("en"); err != nil {
+		t.Errorf("Expected reloading an unused index to do nothing, got %v", err)
+	}
+	set.Autocomplete("en", "he", nil, 5)
+
+	updated := saveAlg1Trie(t, []string{"hello", "help", "help"})
+	if err := os.Rename(updated, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.Reload("en"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := set.Autocomplete("en", "he", nil, 5)
+	if len(got) != 2 || got[0].Word != "help" {
+		t.Errorf("Expected the reloaded words, got %v", got)
+	}
+	if st := set.Stats()["en"]; st.Queries != 2 {
+		t.Errorf("Expected the query count to survive the reload, got %+v", st)
+	}
+
+	os.Remove(path)
+	if err := set.Reload("en"); err == nil {
+		t.Errorf("Expected an error reloading a missing snapshot")
+	}
+	if got, _ := set.Autocomplete("en", "he", nil, 5); len(got) != 2 {
+		t.Errorf("Expected the old trie to keep serving after a failed reload, got %v", got)
+	}
+	if err := set.Reload("fr"); err == nil {
+		t.Errorf("Expected an error for an unknown index")
+	}
+}
+
+func TestIndexSetRetriesFailedLoads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "late.bin")
+	set := NewIndexSet(nil)
+	set.Register("get", path)
+	set.Register("reload", path)
+	for _, name := range []string{"get", "reload"} {
+		if _, err := set.Get(name); err == nil {
+			t.Fatalf("%s: expected an error before the snapshot exists", name)
+		}
+	}
+
+	if err := os.Rename(saveAlg1Trie(t, []string{"hello"}), path); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := set.Autocomplete("get", "he", nil, 5); err != nil || len(got) != 1 {
+		t.Errorf("Expected the next use to load the snapshot, got %v and %v", got, err)
+	}
+	if err := set.Reload("reload"); err != nil {
+		t.Fatalf("Expected Reload to load an index that failed to load, got %v", err)
+	}
+	if st := set.Stats()["reload"]; !st.Loaded || st.Words != 1 || st.Error != "" {
+		t.Errorf("Expected the reloaded index loaded with 1 word, got %+v", st)
+	}
+}
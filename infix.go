@@ -0,0 +1,172 @@
+package autocomplete
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Infix Search
+// -----------------------------------------
+
+// InfixMatch is a word containing the searched substring. Start and End are
+// rune offsets of the match within Word (End is exclusive), so a UI can
+// highlight Word[Start:End] after converting Word to runes.
+type InfixMatch struct {
+	Word  string
+	Start int
+	End   int
+}
+
+// SearchInfix returns every word containing substring anywhere, ranked by
+// frequency and then alphabetically. When substring occurs more than once
+// in a word only the first occurrence is reported.
+func (t *TriesA2) SearchInfix(substring string) []InfixMatch {
+	var entries []Suggestion
+	collectEntriesA2(t.root, "", &entries)
+	entries = t.filter.apply(entries)
+
+	sort.Slice(entries, func(i, j int) bool { return rankBefore(entries[i], entries[j]) })
+
+	matchLen := utf8.RuneCountInString(substring)
+	var matches []InfixMatch
+	for _, e := range entries {
+		idx := strings.Index(e.Word, substring)
+		if idx < 0 {
+			continue
+		}
+		start := utf8.RuneCountInString(e.Word[:idx])
+		matches = append(matches, InfixMatch{Word: e.Word, Start: start, End: start + matchLen})
+	}
+	return matches
+}
+
+// AutocompleteAllTokens returns up to k stored entries that contain every
+// token as a substring, in any order, ranked like SearchInfix. It suits
+// command palettes where "push git" should find "git push --force". With no
+// tokens it returns the globally most frequent entries.
+func (t *TriesA2) AutocompleteAllTokens(tokens []string, k int) []string {
+	if len(tokens) == 0 {
+		return t.AutocompleteTopK("", k)
+	}
+
+	results := []string{}
+	for _, m := range t.SearchInfix(tokens[0]) {
+		if len(results) >= k {
+			break
+		}
+		matchesAll := true
+		for _, token := range tokens[1:] {
+			if !strings.Contains(m.Word, token) {
+				matchesAll = false
+				break
+			}
+		}
+		if matchesAll {
+			results = append(results, m.Word)
+		}
+	}
+	return results
+}
+
+// infixIndex is a sorted array of every suffix of every word, starting at
+// each rune boundary, so the words containing a substring are those whose
+// suffixes start with it: one contiguous, binary-searchable run.
+type infixIndex struct {
+	words    []string
+	suffixes []suffixRef
+}
+
+type suffixRef struct {
+	word   int32 // index into words
+	offset int32 // byte offset of the suffix within the word
+}
+
+func (x *infixIndex) suffix(i int) string {
+	ref := x.suffixes[i]
+	return x.words[ref.word][ref.offset:]
+}
+
+// infixCache holds the infixIndex of a trie, built on first use under mu,
+// since queries may run concurrently, and dropped when words change.
+type infixCache struct {
+	mu    sync.Mutex
+	index *infixIndex
+}
+
+// get returns the index of t, building it first if needed.
+func (c *infixCache) get(t *TriesA2) *infixIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.index == nil {
+		c.index = t.buildInfixIndex()
+	}
+	return c.index
+}
+
+// reset drops the index so the next query rebuilds it.
+func (c *infixCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.index = nil
+}
+
+// resetInfix drops the Search index after words are added or removed. A
+// trie decoded into its zero value gets its cache here.
+func (t *TriesA2) resetInfix() {
+	if t.infix == nil {
+		t.infix = &infixCache{}
+		return
+	}
+	t.infix.reset()
+}
+
+func (t *TriesA2) buildInfixIndex() *infixIndex {
+	x := &infixIndex{}
+	collectWordsA2(t.root, "", &x.words)
+	for i, word := range x.words {
+		for offset := range word {
+			x.suffixes = append(x.suffixes, suffixRef{word: int32(i), offset: int32(offset)})
+		}
+	}
+	sort.Slice(x.suffixes, func(i, j int) bool { return x.suffix(i) < x.suffix(j) })
+	return x
+}
+
+// Search returns up to k words containing substring anywhere, so "copter"
+// finds "helicopter", ranked by their share of the matches' total frequency
+// like Autocomplete. Unlike SearchInfix it does not scan every word: the
+// first call builds a suffix index, which is reused until a word is added or
+// removed. The index holds one entry per rune of every word, so it suits
+// dictionaries that are queried far more often than they change. An empty
+// substring matches every word.
+func (t *TriesA2) Search(substring string, k int) []Suggestion {
+	if substring == "" {
+		return t.AutocompleteProb("", k)
+	}
+	x := t.infix.get(t)
+
+	start := sort.Search(len(x.suffixes), func(i int) bool { return x.suffix(i) >= substring })
+	seen := make(map[int32]bool)
+	var matches []Suggestion
+	total := 0
+	for i := start; i < len(x.suffixes) && strings.HasPrefix(x.suffix(i), substring); i++ {
+		id := x.suffixes[i].word
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !t.filter.allows(x.words[id]) {
+			continue
+		}
+		frequency := t.Frequency(x.words[id])
+		matches = append(matches, Suggestion{Word: x.words[id], Frequency: frequency})
+		total += frequency
+	}
+	for i := range matches {
+		matches[i].Score = float64(matches[i].Frequency) / float64(total)
+	}
+	return topK(matches, k)
+}
@@ -0,0 +1,135 @@
+package autocomplete
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSearchInfixOffsets(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "helicopter", "world"})
+
+	matches := trie.SearchInfix("ll")
+	if len(matches) != 1 {
+		t.Fatalf("Expected one match for 'll', got %v", matches)
+	}
+	m := matches[0]
+	if m.Word != "hello" || m.Start != 2 || m.End != 4 {
+		t.Errorf("Expected hello[2:4], got %s[%d:%d]", m.Word, m.Start, m.End)
+	}
+	if got := string([]rune(m.Word)[m.Start:m.End]); got != "ll" {
+		t.Errorf("Offsets point at '%s', expected 'll'", got)
+	}
+
+	matches = trie.SearchInfix("cop")
+	if len(matches) != 1 || matches[0].Word != "helicopter" || matches[0].Start != 4 {
+		t.Errorf("Expected 'cop' inside helicopter at rune 4, got %v", matches)
+	}
+}
+
+func TestSearchInfixFirstOccurrenceAndRunes(t *testing.T) {
+	trie := buildAlg2Trie([]string{"banana", "café au lait"})
+
+	matches := trie.SearchInfix("an")
+	if len(matches) != 1 || matches[0].Start != 1 || matches[0].End != 3 {
+		t.Errorf("Expected first occurrence of 'an' in banana at [1:3], got %v", matches)
+	}
+
+	// Offsets are in runes, not bytes: "é" is two bytes.
+	matches = trie.SearchInfix("au")
+	if len(matches) != 1 || matches[0].Start != 5 || matches[0].End != 7 {
+		t.Errorf("Expected rune offsets [5:7] for 'au', got %v", matches)
+	}
+}
+
+func TestAutocompleteAllTokens(t *testing.T) {
+	corpus := []string{
+		"git push", "git push", "git push", "git push --force", "git pull",
+		"docker push", "docker push", "git status", "git stash push",
+	}
+	trie := buildAlg2Trie(corpus)
+
+	got := trie.AutocompleteAllTokens([]string{"push", "git"}, 5)
+	want := []string{"git push", "git push --force", "git stash push"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if got := trie.AutocompleteAllTokens([]string{"push", "git"}, 1); !reflect.DeepEqual(got, []string{"git push"}) {
+		t.Errorf("Expected only the top match with k=1, got %v", got)
+	}
+	if got := trie.AutocompleteAllTokens([]string{"docker", "pull"}, 5); len(got) != 0 {
+		t.Errorf("Expected no matches for tokens without a common entry, got %v", got)
+	}
+	if got := trie.AutocompleteAllTokens(nil, 2); !reflect.DeepEqual(got, []string{"git push", "docker push"}) {
+		t.Errorf("Expected the global top 2 for an empty token list, got %v", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	trie := buildAlg2Trie([]string{"helicopter", "helicopter", "copter", "hello", "yellow", "yellow", "yellow", "mellow", "日本語"})
+
+	if got, want := Words(trie.Search("copter", 5)), []string{"helicopter", "copter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got, want := Words(trie.Search("ello", 2)), []string{"yellow", "hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got, want := Words(trie.Search("本", 5)), []string{"日本語"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := trie.Search("xyz", 5); len(got) != 0 {
+		t.Errorf("Expected no matches, got %v", got)
+	}
+
+	// Every word SearchInfix finds is found through the index too.
+	for _, sub := range []string{"l", "ll", "o", "er", "w"} {
+		var want []string
+		for _, m := range trie.SearchInfix(sub) {
+			want = append(want, m.Word)
+		}
+		if got := Words(trie.Search(sub, 100)); !reflect.DeepEqual(got, want) {
+			t.Errorf("For %q: expected %v, got %v", sub, want, got)
+		}
+	}
+}
+
+func TestSearchIndexFollowsUpdates(t *testing.T) {
+	trie := buildAlg2Trie([]string{"helicopter"})
+	trie.Search("copter", 5) // builds the index
+
+	trie.Insert("copter")
+	trie.Delete("helicopter")
+	if got, want := Words(trie.Search("copter", 5)), []string{"copter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v after updates, got %v", want, got)
+	}
+
+	// Frequencies are read live, so a repeat insert needs no rebuild.
+	trie.Insert("mycopter")
+	trie.Insert("mycopter")
+	if got := trie.Search("copter", 5); got[0].Word != "mycopter" || got[0].Frequency != 2 {
+		t.Errorf("Expected mycopter first with frequency 2, got %v", got)
+	}
+}
+
+func TestSearchConcurrentFirstUse(t *testing.T) {
+	trie := buildAlg2Trie([]string{"helicopter", "copter", "coptic", "hello"})
+
+	// Every reader may be the one to build the index; run with -race.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := trie.Search("copt", 5); len(got) != 3 {
+				t.Errorf("Expected 3 words containing 'copt', got %v", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	trie.Insert("adopt")
+	if got := trie.Search("opt", 5); len(got) != 4 {
+		t.Errorf("Expected the index rebuilt after an insert, got %v", got)
+	}
+}
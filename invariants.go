@@ -0,0 +1,128 @@
+package autocomplete
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// -----------------------------------------
+// Structural Invariants
+// -----------------------------------------
+
+// sortedRunes returns the child keys of a node in ascending order so
+// traversals that report problems do so deterministically.
+func sortedRunes(children map[rune]*NodeA2) []rune {
+	keys := make([]rune, 0, len(children))
+	for char := range children {
+		keys = append(keys, char)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// Validate checks the structural invariants of the trie and returns an error
+// describing every violation, each with the path to the offending node:
+//
+//   - the root is never a word
+//   - every word has a positive frequency
+//   - nodes that are not words carry no frequency
+//   - every node other than the root leads to at least one word
+//   - child pointers are never nil
+//   - every node counts the words ending at or below it
+func (t *TriesA2) Validate() error {
+	var errs []error
+	if t.root.isEndOfWord {
+		errs = append(errs, errors.New(`node "": root is marked as a word`))
+	}
+
+	// walk returns the number of words ending at or below node.
+	var walk func(node *NodeA2, path string) int
+	walk = func(node *NodeA2, path string) int {
+		if node.isEndOfWord && node.frequency <= 0 {
+			errs = append(errs, fmt.Errorf("node %q: word has frequency %d", path, node.frequency))
+		}
+		if !node.isEndOfWord && node.frequency != 0 {
+			errs = append(errs, fmt.Errorf("node %q: non-word has frequency %d", path, node.frequency))
+		}
+		words := 0
+		if node.isEndOfWord {
+			words++
+		}
+		for _, char := range sortedRunes(node.children) {
+			child := node.children[char]
+			childPath := path + string(char)
+			if child == nil {
+				errs = append(errs, fmt.Errorf("node %q: nil child", childPath))
+				continue
+			}
+			words += walk(child, childPath)
+		}
+		if words == 0 && path != "" && len(node.children) == 0 {
+			errs = append(errs, fmt.Errorf("node %q: dead-end branch with no words", path))
+		}
+		if words != node.words {
+			errs = append(errs, fmt.Errorf("node %q: counts %d words below it, found %d", path, node.words, words))
+		}
+		return words
+	}
+	walk(t.root, "")
+
+	return errors.Join(errs...)
+}
+
+// Repair fixes the violations Validate reports: words with a non-positive
+// frequency stop being words, stray frequencies on non-words are cleared, nil
+// children are dropped and branches that no longer lead to any word are
+// pruned. It returns the number of nodes removed.
+func (t *TriesA2) Repair() int {
+	removed := 0
+
+	// repair returns whether node still leads to a word.
+	var repair func(node *NodeA2) bool
+	repair = func(node *NodeA2) bool {
+		if node.isEndOfWord && node.frequency <= 0 {
+			node.isEndOfWord = false
+		}
+		if !node.isEndOfWord {
+			node.frequency = 0
+		}
+		for char, child := range node.children {
+			if child == nil {
+				delete(node.children, char)
+				continue
+			}
+			if !repair(child) {
+				removed += countNodesA2(child)
+				delete(node.children, char)
+			}
+		}
+		return node.isEndOfWord || len(node.children) > 0
+	}
+
+	t.root.isEndOfWord = false
+	repair(t.root)
+	t.size = recountWordsA2(t.root)
+	return removed
+}
+
+func recountWordsA2(node *NodeA2) int {
+	node.words = 0
+	if node.isEndOfWord {
+		node.words++
+	}
+	for _, child := range node.children {
+		node.words += recountWordsA2(child)
+	}
+	return node.words
+}
+
+func countNodesA2(node *NodeA2) int {
+	count := 1
+	for _, child := range node.children {
+		if child != nil {
+			count += countNodesA2(child)
+		}
+	}
+	return count
+}
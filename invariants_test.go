@@ -0,0 +1,48 @@
+package autocomplete
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateHealthyTrie(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "hell", "helicopter", "hero", "world"})
+	if err := trie.Validate(); err != nil {
+		t.Errorf("Expected a freshly built trie to be valid, got: %v", err)
+	}
+}
+
+func TestValidateAndRepairBrokenTrie(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "hero", "world"})
+
+	// A word whose frequency dropped to zero without being unmarked.
+	trie.searchPrefix("hero").frequency = 0
+	// An orphan chain "wx" -> "wxy" that leads to no word.
+	w := trie.searchPrefix("w")
+	w.children['x'] = &NodeA2{children: map[rune]*NodeA2{
+		'y': {children: make(map[rune]*NodeA2)},
+	}}
+	// A stray frequency on an interior node.
+	trie.searchPrefix("hel").frequency = 3
+
+	err := trie.Validate()
+	if err == nil {
+		t.Fatalf("Expected Validate to report the broken trie")
+	}
+	for _, want := range []string{`"hero"`, `"wxy"`, `"hel"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got: %v", want, err)
+		}
+	}
+
+	// The "ro" tail of "hero" and the "xy" chain are pruned.
+	if removed := trie.Repair(); removed != 4 {
+		t.Errorf("Expected Repair to remove 4 nodes, got %d", removed)
+	}
+	if err := trie.Validate(); err != nil {
+		t.Errorf("Expected a valid trie after Repair, got: %v", err)
+	}
+	if got := trie.AutocompleteSorted(""); strings.Join(got, ",") != "hello,world" {
+		t.Errorf("Expected only the remaining words after Repair, got %v", got)
+	}
+}
@@ -0,0 +1,102 @@
+package autocomplete
+
+import "container/heap"
+
+// -----------------------------------------
+// Lazy Completion Iterator
+// -----------------------------------------
+
+// Completions yields the completions of a prefix one at a time in ranked
+// order, for paging past the top k. The candidates are collected and scored
+// once when the iterator is created; each Next then costs O(log n), so
+// reading the first m of n completions takes O(n + m log n) instead of a
+// fresh O(n log k) query per page.
+//
+// An iterator is a snapshot: words inserted after it was created are not
+// yielded, and it must not be used concurrently with changes to the trie's
+// ranking settings.
+type Completions struct {
+	pending rankedHeap
+	emit    func(Suggestion) Suggestion
+}
+
+// Next returns the next best completion, or false when there are no more.
+func (c *Completions) Next() (Suggestion, bool) {
+	if c == nil || len(c.pending) == 0 {
+		return Suggestion{}, false
+	}
+	s := heap.Pop(&c.pending).(Suggestion)
+	if c.emit != nil {
+		s = c.emit(s)
+	}
+	return s, true
+}
+
+// Page returns up to n further completions.
+func (c *Completions) Page(n int) []Suggestion {
+	var page []Suggestion
+	for len(page) < n {
+		s, ok := c.Next()
+		if !ok {
+			break
+		}
+		page = append(page, s)
+	}
+	return page
+}
+
+// Remaining returns how many completions Next has yet to yield.
+func (c *Completions) Remaining() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.pending)
+}
+
+func newCompletions(scored []Suggestion) *Completions {
+	c := &Completions{pending: rankedHeap(scored)}
+	heap.Init(&c.pending)
+	return c
+}
+
+// Completions returns an iterator over every completion of prefix ranked as
+// Autocomplete ranks them, within the scan limit and result options.
+// Fallback suggestions are not included.
+func (t *TrieA1) Completions(prefix string) *Completions {
+	runes := []rune(prefix)
+	node := t.searchRunes(runes)
+	if node == nil {
+		return newCompletions(nil)
+	}
+	scored, _ := t.scoredCompletions(node, runes, prefix, query{})
+	c := newCompletions(scored)
+	if t.precision > 0 {
+		c.emit = func(s Suggestion) Suggestion {
+			one := []Suggestion{s}
+			t.roundProbabilities(one)
+			return one[0]
+		}
+	}
+	return c
+}
+
+// Completions returns an iterator over every completion of prefix ranked as
+// Autocomplete ranks them, within the result options.
+func (t *TriesA2) Completions(prefix string) *Completions {
+	return newCompletions(t.scoredCompletions(prefix, query{}))
+}
+
+// rankedHeap is a max-heap under rankBefore: the best remaining candidate
+// sits at the root.
+type rankedHeap []Suggestion
+
+func (h rankedHeap) Len() int           { return len(h) }
+func (h rankedHeap) Less(i, j int) bool { return rankBefore(h[i], h[j]) }
+func (h rankedHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *rankedHeap) Push(x any)        { *h = append(*h, x.(Suggestion)) }
+func (h *rankedHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,56 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompletionsMatchAutocomplete(t *testing.T) {
+	corpus := []string{
+		"hello", "hello", "hello", "hello", "hell", "hell", "hell",
+		"helicopter", "helicopter", "hero", "help", "world",
+	}
+	tries := map[string]interface {
+		Autocomplete(string, int) []Suggestion
+		Completions(string) *Completions
+	}{
+		"Algorithm_1": buildAlg1Trie(corpus),
+		"Algorithm_2": buildAlg2Trie(corpus),
+	}
+
+	for name, trie := range tries {
+		want := trie.Autocomplete("he", 10)
+		it := trie.Completions("he")
+		if it.Remaining() != 5 {
+			t.Errorf("%s: expected 5 completions pending, got %d", name, it.Remaining())
+		}
+
+		// Pages join up to the full ranking.
+		got := append(it.Page(2), it.Page(2)...)
+		got = append(got, it.Page(2)...)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+		if _, ok := it.Next(); ok || it.Remaining() != 0 {
+			t.Errorf("%s: expected the iterator to be exhausted", name)
+		}
+
+		if _, ok := trie.Completions("xyz").Next(); ok {
+			t.Errorf("%s: expected no completions for an unknown prefix", name)
+		}
+	}
+}
+
+func TestCompletionsRoundsAfterRanking(t *testing.T) {
+	trie := buildAlg1Trie([]string{"ab", "ab", "ab", "ac", "ac", "ad"})
+	trie.SetPrecision(1)
+
+	first, _ := trie.Completions("a").Next()
+	if first.Word != "ab" || first.Score != 0.5 {
+		t.Errorf("Expected ab with a rounded score of 0.5, got %v", first)
+	}
+	second := trie.Completions("a").Page(2)[1]
+	if second.Word != "ac" || second.Score != 0.3 {
+		t.Errorf("Expected ac with a rounded score of 0.3, got %v", second)
+	}
+}
@@ -0,0 +1,118 @@
+package autocomplete
+
+import (
+	"math"
+	"unicode"
+)
+
+// -----------------------------------------
+// Keyboard-Aware Typos
+// -----------------------------------------
+
+// adjacentKeyCost is what substituting a neighbouring key costs in fuzzy
+// matching, against 1 for any other substitution.
+const adjacentKeyCost = 0.5
+
+// adjacentKeyDistance is how far apart, in key widths, two keys may be and
+// still count as neighbours: the keys beside, above and below each other.
+const adjacentKeyDistance = 1.5
+
+// KeyboardRow is one row of a KeyboardLayout: its keys from left to right
+// and how far, in key widths, the first key sits from the left edge.
+type KeyboardRow struct {
+	Keys   string
+	Offset float64
+}
+
+// KeyboardLayout places keys on a grid so fuzzy matching can tell a slip
+// onto a neighbouring key from an unrelated typo. Keys are compared case
+// insensitively; runes not on the layout are never neighbours.
+type KeyboardLayout struct {
+	keys map[rune]keyPosition
+}
+
+type keyPosition struct {
+	x, y float64
+}
+
+// NewKeyboardLayout returns the layout with the given rows, top first.
+func NewKeyboardLayout(rows ...KeyboardRow) *KeyboardLayout {
+	l := &KeyboardLayout{keys: make(map[rune]keyPosition)}
+	for y, row := range rows {
+		x := row.Offset
+		for _, key := range row.Keys {
+			l.keys[unicode.ToLower(key)] = keyPosition{x: x, y: float64(y)}
+			x++
+		}
+	}
+	return l
+}
+
+// Rows are staggered as on a standard ANSI keyboard.
+var (
+	// QWERTY is the US QWERTY layout.
+	QWERTY = NewKeyboardLayout(
+		KeyboardRow{"1234567890-=", 0},
+		KeyboardRow{"qwertyuiop[]", 0.5},
+		KeyboardRow{"asdfghjkl;'", 0.75},
+		KeyboardRow{"zxcvbnm,./", 1.25},
+	)
+
+	// AZERTY is the French AZERTY layout.
+	AZERTY = NewKeyboardLayout(
+		KeyboardRow{"&é\"'(-è_çà)=", 0},
+		KeyboardRow{"azertyuiop^$", 0.5},
+		KeyboardRow{"qsdfghjklmù", 0.75},
+		KeyboardRow{"<wxcvbn,;:!", 0.25},
+	)
+
+	// Dvorak is the US Dvorak layout.
+	Dvorak = NewKeyboardLayout(
+		KeyboardRow{"1234567890[]", 0},
+		KeyboardRow{"',.pyfgcrl/=", 0.5},
+		KeyboardRow{"aoeuidhtns-", 0.75},
+		KeyboardRow{";qjkxbmwvz", 1.25},
+	)
+)
+
+// Distance returns how far apart keys a and b are in key widths, or +Inf if
+// either is not on the layout.
+func (l *KeyboardLayout) Distance(a, b rune) float64 {
+	pa, ok := l.keys[unicode.ToLower(a)]
+	if !ok {
+		return math.Inf(1)
+	}
+	pb, ok := l.keys[unicode.ToLower(b)]
+	if !ok {
+		return math.Inf(1)
+	}
+	return math.Hypot(pa.x-pb.x, pa.y-pb.y)
+}
+
+// Adjacent reports whether a and b are different, neighbouring keys.
+func (l *KeyboardLayout) Adjacent(a, b rune) bool {
+	d := l.Distance(a, b)
+	return d > 0 && d <= adjacentKeyDistance
+}
+
+// substitutionCost is what typing a instead of b costs: nothing for the same
+// key, adjacentKeyCost for a neighbour and 1 otherwise. A nil layout has no
+// neighbours.
+func (l *KeyboardLayout) substitutionCost(a, b rune) float64 {
+	switch {
+	case a == b:
+		return 0
+	case l != nil && l.Adjacent(a, b):
+		return adjacentKeyCost
+	}
+	return 1
+}
+
+// WithKeyboard makes AutocompleteFuzzy charge only adjacentKeyCost for
+// substituting a key's neighbour on layout, so a query's likely slips rank
+// above unrelated typos: "jello" is nearer "hello" than "cello" on QWERTY.
+// A nil layout makes every substitution cost a full edit again.
+func (t *TriesA2) WithKeyboard(layout *KeyboardLayout) *TriesA2 {
+	t.keyboard = layout
+	return t
+}
@@ -0,0 +1,64 @@
+package autocomplete
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestKeyboardLayoutAdjacent(t *testing.T) {
+	tests := []struct {
+		a, b rune
+		want bool
+	}{
+		{'j', 'h', true},
+		{'J', 'k', true},
+		{'s', 'w', true},
+		{'s', 'e', true},
+		{'s', 'x', true},
+		{'s', 'r', false},
+		{'j', 'c', false},
+		{'q', 'q', false},
+		{'q', 'é', false},
+	}
+	for _, test := range tests {
+		if got := QWERTY.Adjacent(test.a, test.b); got != test.want {
+			t.Errorf("Expected Adjacent(%q, %q) to be %v, got %v", test.a, test.b, test.want, got)
+		}
+	}
+	if d := QWERTY.Distance('a', '€'); !math.IsInf(d, 1) {
+		t.Errorf("Expected an infinite distance to a key not on the layout, got %v", d)
+	}
+}
+
+func TestAutocompleteFuzzyWithKeyboard(t *testing.T) {
+	trie := buildAlg2Trie([]string{"cello", "hello"})
+
+	// Without a layout both are one substitution away and tie; on QWERTY j
+	// is next to h but far from c.
+	if got, want := Words(trie.AutocompleteFuzzy("jello", 1, 2)), []string{"cello", "hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v without a layout, got %v", want, got)
+	}
+	trie.WithKeyboard(QWERTY)
+	if got, want := Words(trie.AutocompleteFuzzy("jello", 1, 2)), []string{"hello", "cello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v on QWERTY, got %v", want, got)
+	}
+
+	// Dvorak puts j beside k and q instead.
+	trie.WithKeyboard(Dvorak)
+	if got, want := Words(trie.AutocompleteFuzzy("jello", 1, 2)), []string{"cello", "hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v on Dvorak, got %v", want, got)
+	}
+}
+
+func TestAutocompleteFuzzyKeyboardStretchesBudget(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello"}).WithKeyboard(QWERTY)
+
+	// Two neighbouring-key slips cost one edit between them.
+	if got := Words(trie.AutocompleteFuzzy("jrllo", 1, 5)); !reflect.DeepEqual(got, []string{"hello"}) {
+		t.Errorf("Expected [hello] for two adjacent slips, got %v", got)
+	}
+	if got := Words(trie.AutocompleteFuzzy("crllo", 1, 5)); len(got) != 0 {
+		t.Errorf("Expected no match for a far substitution plus a slip, got %v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package autocomplete
+
+// -----------------------------------------
+// Learning From Selections
+// -----------------------------------------
+
+// Promote reinforces a word the user selected by adding one to its
+// frequency. Unlike Insert it never creates nodes: it returns false and
+// changes nothing if word is not already in the trie.
+func (t *TrieA1) Promote(word string) bool {
+	node := t.searchPrefix(word)
+	if node == nil || !node.isEnd {
+		return false
+	}
+	node.frequency++
+	t.countInsert(word, 1, node.frequency, false)
+	t.unfinalize()
+	t.cache.clear()
+	return true
+}
+
+// Promote reinforces a word the user selected by adding one occurrence of
+// it, counted in the decayed weight and recency window like an inserted
+// one. Unlike Insert it never creates nodes: it returns false and changes
+// nothing if word is not already in the trie.
+func (t *TriesA2) Promote(word string) bool {
+	node := t.searchPrefix(word)
+	if node == nil || !node.isEndOfWord {
+		return false
+	}
+	node.frequency++
+	if t.halfLife > 0 {
+		node.weight++
+	}
+	t.recordInWindow(node)
+	return true
+}
+
+// RecordBigram counts one occurrence of word following prev, as if the pair
+// had appeared in the corpus passed to BuildBigramTable. Pairs involving a
+// stopword or a word rejected by validation are ignored.
+func (t *TrieA1) RecordBigram(prev, word string) {
+	t.BuildBigramTable([]string{prev, word})
+}
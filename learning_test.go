@@ -0,0 +1,77 @@
+package autocomplete
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromoteRaisesRank(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "help"})
+
+	if got := trie.Autocomplete("hel", 1); got[0].Word != "hello" {
+		t.Fatalf("Expected 'hello' first before promotion, got %v", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if !trie.Promote("help") {
+			t.Fatalf("Expected Promote to succeed for an existing word")
+		}
+	}
+	if got := trie.Autocomplete("hel", 1); got[0].Word != "help" {
+		t.Errorf("Expected 'help' first after promotion, got %v", got)
+	}
+}
+
+func TestPromoteMissingWord(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello"})
+
+	if trie.Promote("hero") {
+		t.Errorf("Expected Promote to fail for a missing word")
+	}
+	// A prefix of a word is not a word.
+	if trie.Promote("hell") {
+		t.Errorf("Expected Promote to fail for a prefix that is not a word")
+	}
+	if trie.searchPrefix("hero") != nil {
+		t.Errorf("Promote must not create nodes")
+	}
+}
+
+func TestRecordBigram(t *testing.T) {
+	trie := buildAlg1Trie([]string{"new", "york"})
+
+	trie.RecordBigram("new", "jersey")
+	trie.RecordBigram("new", "york")
+
+	if got := trie.bigramTable["new"]["york"]; got != 2 {
+		t.Errorf("Expected 2 occurrences of 'new york', got %d", got)
+	}
+	if got := trie.bigramTable["new"]["_total"]; got != 3 {
+		t.Errorf("Expected total of 3 for 'new', got %d", got)
+	}
+}
+
+func TestPromoteA2(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "help", "help"})
+
+	if !trie.Promote("hello") || !trie.Promote("hello") {
+		t.Fatalf("Expected Promote to succeed for an existing word")
+	}
+	if got := trie.Frequency("hello"); got != 3 {
+		t.Errorf("Expected frequency 3 after two promotions, got %d", got)
+	}
+	if trie.Promote("hero") || trie.Promote("hel") {
+		t.Errorf("Expected Promote to fail for a missing word or a bare prefix")
+	}
+	if trie.Len() != 2 {
+		t.Errorf("Expected Promote not to add words, got %d", trie.Len())
+	}
+
+	trie.EnableWindow(5)
+	trie.WithDecay(time.Hour)
+	trie.Promote("help")
+	if trie.WindowedFrequency("help") != 1 || trie.DecayedFrequency("help") != 3 {
+		t.Errorf("Expected the promotion in the window and decayed weight, got %d and %v",
+			trie.WindowedFrequency("help"), trie.DecayedFrequency("help"))
+	}
+}
@@ -0,0 +1,23 @@
+package autocomplete
+
+import "sort"
+
+// -----------------------------------------
+// Alphabetical Listing
+// -----------------------------------------
+
+// AutocompleteSorted returns every completion of prefix in lexicographic
+// order. Unlike Autocomplete it ignores frequency and applies no top-k limit,
+// which suits dictionary browsers and alphabetical dropdowns.
+func (t *TriesA2) AutocompleteSorted(prefix string) []string {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return []string{}
+	}
+
+	var entries []Suggestion
+	collectEntriesA2(node, prefix, &entries)
+	results := Words(t.filter.apply(entries))
+	sort.Strings(results)
+	return results
+}
@@ -0,0 +1,30 @@
+package autocomplete
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAutocompleteSorted(t *testing.T) {
+	var corpus []string
+	for i := 0; i < 15; i++ {
+		corpus = append(corpus, fmt.Sprintf("he%02d", 14-i))
+	}
+	corpus = append(corpus, "hello", "hero", "world")
+	trie := buildAlg2Trie(corpus)
+
+	results := trie.AutocompleteSorted("he")
+
+	if len(results) != 17 {
+		t.Fatalf("Expected all 17 completions beyond the top-10 cutoff, got %d", len(results))
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i-1] >= results[i] {
+			t.Errorf("Results not strictly increasing at %d: '%s' >= '%s'", i, results[i-1], results[i])
+		}
+	}
+
+	if got := trie.AutocompleteSorted("xyz"); len(got) != 0 {
+		t.Errorf("Expected no completions for unknown prefix, got %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+package autocomplete
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"strings"
+)
+
+// -----------------------------------------
+// Binary and JSON Marshaling
+// -----------------------------------------
+
+// Both tries implement encoding.BinaryMarshaler with the snapshot format of
+// Save, which also makes them encodable with encoding/gob, and json.Marshaler
+// with a readable document of words and counts. Unmarshaling replaces the
+// stored data and keeps settings, like Load.
+var (
+	_ encoding.BinaryMarshaler   = (*TrieA1)(nil)
+	_ encoding.BinaryUnmarshaler = (*TrieA1)(nil)
+	_ json.Marshaler             = (*TrieA1)(nil)
+	_ json.Unmarshaler           = (*TrieA1)(nil)
+	_ encoding.BinaryMarshaler   = (*TriesA2)(nil)
+	_ encoding.BinaryUnmarshaler = (*TriesA2)(nil)
+	_ json.Marshaler             = (*TriesA2)(nil)
+	_ json.Unmarshaler           = (*TriesA2)(nil)
+)
+
+// MarshalBinary encodes the trie as a snapshot.
+func (t *TrieA1) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := t.Save(&buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary replaces the trie with a snapshot.
+func (t *TrieA1) UnmarshalBinary(data []byte) error {
+	return t.Load(bytes.NewReader(data))
+}
+
+// MarshalBinary encodes the trie as a snapshot.
+func (t *TriesA2) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := t.Save(&buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary replaces the trie with a snapshot.
+func (t *TriesA2) UnmarshalBinary(data []byte) error {
+	return t.Load(bytes.NewReader(data))
+}
+
+// trieA1JSON is the JSON form of TrieA1. Follower counts omit "_total",
+// which is recomputed when decoding.
+type trieA1JSON struct {
+	Words        map[string]int            `json:"words"`
+	Bigrams      map[string]map[string]int `json:"bigrams,omitempty"`
+	NgramOrder   int                       `json:"ngramOrder,omitempty"`
+	Ngrams       []ngramJSON               `json:"ngrams,omitempty"`
+	PhraseLength int                       `json:"phraseLength,omitempty"`
+	Phrases      map[string]int            `json:"phrases,omitempty"`
+}
+
+type ngramJSON struct {
+	Context []string       `json:"context"`
+	Next    map[string]int `json:"next"`
+}
+
+type triesA2JSON struct {
+	Words map[string]int `json:"words"`
+}
+
+// MarshalJSON encodes the words with their frequencies, the bigram and
+// n-gram tables and the phrase index.
+func (t *TrieA1) MarshalJSON() ([]byte, error) {
+	doc := trieA1JSON{
+		Words:        make(map[string]int, t.size),
+		Bigrams:      withoutTotals(t.bigramTable),
+		NgramOrder:   t.ngramOrder,
+		PhraseLength: t.phraseLength,
+	}
+	for _, c := range t.collectCompletions(t.root, nil) {
+		doc.Words[c.Word] = c.Frequency
+	}
+	for _, key := range sortedKeys(t.ngramTable) {
+		doc.Ngrams = append(doc.Ngrams, ngramJSON{
+			Context: strings.Split(key, ngramSeparator),
+			Next:    withoutTotal(t.ngramTable[key]),
+		})
+	}
+	if t.phrases != nil {
+		doc.Phrases = t.phrases.wordFrequencies()
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON replaces the trie with a document written by MarshalJSON.
+// Words with a non-positive count are ignored.
+func (t *TrieA1) UnmarshalJSON(data []byte) error {
+	var doc trieA1JSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	t.root, t.size = t.newNode(), 0
+	for word, count := range doc.Words {
+		if word == "" || count <= 0 {
+			continue
+		}
+		node := t.root
+		for _, char := range word {
+			node = node.addChild(char)
+		}
+		node.isEnd, node.frequency = true, count
+		t.countInsert(word, count, count, true)
+		t.size++
+	}
+
+	t.bigramTable = withTotals(doc.Bigrams)
+	t.ngramOrder, t.ngramTable = doc.NgramOrder, make(map[string]map[string]int)
+	for _, n := range doc.Ngrams {
+		t.ngramTable[strings.Join(n.Context, ngramSeparator)] = withTotal(n.Next)
+	}
+	t.phraseLength, t.phrases = doc.PhraseLength, nil
+	if doc.Phrases != nil {
+		t.phrases = NewTriesA2()
+		t.phrases.setFrequencies(doc.Phrases)
+	}
+	t.history = nil
+	t.resetKeyIndexes()
+	t.unfinalize()
+	t.cache.clear()
+	return nil
+}
+
+// MarshalJSON encodes the words with their frequencies.
+func (t *TriesA2) MarshalJSON() ([]byte, error) {
+	return json.Marshal(triesA2JSON{Words: t.wordFrequencies()})
+}
+
+// UnmarshalJSON replaces the trie with a document written by MarshalJSON.
+// Words with a non-positive count are ignored.
+func (t *TriesA2) UnmarshalJSON(data []byte) error {
+	var doc triesA2JSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	t.setFrequencies(doc.Words)
+	if t.window != nil {
+		t.EnableWindow(len(t.window))
+	}
+	if t.halfLife > 0 {
+		t.WithDecay(t.halfLife)
+	}
+	return nil
+}
+
+// setFrequencies replaces the contents of the trie with words at the given
+// frequencies.
+func (t *TriesA2) setFrequencies(words map[string]int) {
+	t.root = &NodeA2{children: make(map[rune]*NodeA2)}
+	t.size = 0
+	t.resetInfix()
+	for word, count := range words {
+		if word == "" || count <= 0 {
+			continue
+		}
+		node := t.root
+		for _, char := range word {
+			child, ok := node.children[char]
+			if !ok {
+				child = &NodeA2{children: make(map[rune]*NodeA2)}
+				node.children[char] = child
+			}
+			node = child
+		}
+		t.countNewWord(word)
+		node.isEndOfWord, node.frequency = true, count
+		t.size++
+	}
+}
+
+func withoutTotals(table map[string]map[string]int) map[string]map[string]int {
+	out := make(map[string]map[string]int, len(table))
+	for context, followers := range table {
+		out[context] = withoutTotal(followers)
+	}
+	return out
+}
+
+func withoutTotal(followers map[string]int) map[string]int {
+	out := make(map[string]int, len(followers))
+	for word, count := range followers {
+		if word != "_total" {
+			out[word] = count
+		}
+	}
+	return out
+}
+
+func withTotals(table map[string]map[string]int) map[string]map[string]int {
+	out := make(map[string]map[string]int, len(table))
+	for context, followers := range table {
+		out[context] = withTotal(followers)
+	}
+	return out
+}
+
+func withTotal(followers map[string]int) map[string]int {
+	out := map[string]int{"_total": 0}
+	for word, count := range followers {
+		if word == "_total" || count <= 0 {
+			continue
+		}
+		out[word] = count
+		out["_total"] += count
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package autocomplete
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func marshalTestA1() *TrieA1 {
+	corpus := []string{"new", "york", "city", "new", "york", "state", "new", "jersey"}
+	trie := NewTrieA1()
+	for _, w := range corpus {
+		trie.Insert(w)
+	}
+	trie.BuildNgramTable(corpus, 3)
+	trie.IndexPhrases(corpus, 2)
+	return trie
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	a1, a2 := marshalTestA1(), buildAlg2Trie([]string{"hello", "hello", "help"})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(struct {
+		A1 *TrieA1
+		A2 *TriesA2
+	}{a1, a2}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var decoded struct {
+		A1 *TrieA1
+		A2 *TriesA2
+	}
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if got, want := decoded.A1.PredictNext([]string{"new", "york"}, 2), a1.PredictNext([]string{"new", "york"}, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Algorithm_1: expected %v, got %v", want, got)
+	}
+	if got, want := decoded.A2.Autocomplete("he", 2), a2.Autocomplete("he", 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Algorithm_2: expected %v, got %v", want, got)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	a1 := marshalTestA1()
+	data, err := json.Marshal(a1)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var doc map[string]any
+	json.Unmarshal(data, &doc)
+	if doc["words"].(map[string]any)["new"] != 3.0 {
+		t.Errorf("Expected readable word counts, got %s", data)
+	}
+
+	decoded := NewTrieA1()
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.root, a1.root) || decoded.Len() != a1.Len() {
+		t.Errorf("Expected the decoded trie to match")
+	}
+	if !reflect.DeepEqual(decoded.bigramTable, a1.bigramTable) || !reflect.DeepEqual(decoded.ngramTable, a1.ngramTable) {
+		t.Errorf("Expected the decoded context tables to match")
+	}
+	if got, want := decoded.AutocompletePhrase("new j", 1), a1.AutocompletePhrase("new j", 1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected phrases %v, got %v", want, got)
+	}
+
+	a2 := buildAlg2Trie([]string{"hello", "hello", "help"})
+	data, err = json.Marshal(a2)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"words":{"hello":2,"help":1}}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+	decodedA2 := NewTriesA2()
+	if err := json.Unmarshal(data, decodedA2); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if added, removed, changed := decodedA2.Diff(a2); len(added)+len(removed)+len(changed) != 0 {
+		t.Errorf("Expected no differences after a JSON round trip")
+	}
+}
@@ -0,0 +1,26 @@
+package autocomplete
+
+// -----------------------------------------
+// Bounded Traversal
+// -----------------------------------------
+
+// SetMaxScan limits every query to collecting at most n words under the
+// prefix before ranking, trading completeness for bounded latency on short
+// prefixes over huge tries. With a limit in place the top k is approximate:
+// the best words may sit in the part of the subtree that was never visited.
+// The words collected are always the first n in rune order. Zero or a
+// negative n removes the limit.
+func (t *TrieA1) SetMaxScan(n int) {
+	if n < 0 {
+		n = 0
+	}
+	t.maxScan = n
+	t.cache.clear()
+}
+
+// AutocompleteBounded is Autocomplete that also reports whether the
+// SetMaxScan limit cut the traversal short. It bypasses the cache so the flag
+// always reflects an actual traversal.
+func (t *TrieA1) AutocompleteBounded(prefix string, k int) ([]Suggestion, bool) {
+	return t.autocompleteBounded([]rune(prefix), prefix, query{}, k)
+}
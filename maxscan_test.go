@@ -0,0 +1,55 @@
+package autocomplete
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMaxScanTruncatesLargeTrie(t *testing.T) {
+	trie := NewTrieA1()
+	for i := 0; i < 100000; i++ {
+		trie.Insert(fmt.Sprintf("w%06d", i))
+	}
+
+	start := time.Now()
+	full, truncated := trie.AutocompleteBounded("w", 10)
+	fullTime := time.Since(start)
+	if truncated || len(full) != 10 {
+		t.Fatalf("Expected a complete scan without a limit, got truncated=%v and %d results", truncated, len(full))
+	}
+
+	trie.SetMaxScan(50)
+	start = time.Now()
+	bounded, truncated := trie.AutocompleteBounded("w", 10)
+	boundedTime := time.Since(start)
+	if !truncated {
+		t.Errorf("Expected truncated=true with MaxScan=50")
+	}
+	if len(bounded) != 10 {
+		t.Errorf("Expected 10 results from a truncated scan, got %d", len(bounded))
+	}
+	t.Logf("Full scan: %v, bounded scan: %v", fullTime, boundedTime)
+
+	// A subtree smaller than the limit is scanned completely.
+	if _, truncated := trie.AutocompleteBounded("w00001", 10); truncated {
+		t.Errorf("Expected no truncation when the subtree fits within MaxScan")
+	}
+}
+
+func TestMaxScanKeepsTheFirstWordsInRuneOrder(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hex", "hen", "hem", "her", "heap", "heal"})
+	trie.SetMaxScan(3)
+
+	got := Words(trie.Autocomplete("he", 10))
+	want := []string{"heal", "heap", "hem"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package autocomplete
+
+// -----------------------------------------
+// Metrics and Evaluation Code
+// -----------------------------------------
+
+// MeasureSuggestionQuality compares returned suggestions to an ideal set.
+// Here, we define "ideal" as a set of words we expect to see at the top.
+// We measure how many top expected words are present in the suggestions.
+func MeasureSuggestionQuality(got []string, ideal []string) float64 {
+	if len(ideal) == 0 {
+		return 1.0 // If no ideal set given, can't measure quality – assume perfect
+	}
+	hitCount := 0
+	for _, idw := range ideal {
+		for _, gw := range got {
+			if gw == idw {
+				hitCount++
+				break
+			}
+		}
+	}
+	return float64(hitCount) / float64(len(ideal))
+}
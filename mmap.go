@@ -0,0 +1,189 @@
+package autocomplete
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// -----------------------------------------
+// Memory-Mapped Index
+// -----------------------------------------
+
+// Index file layout, all integers little-endian:
+//
+//	header       magic "ACI1", uint32 #nodes, uint32 #edges, uint32 #words,
+//	             uint32 root
+//	nodes        per node: uint32 first edge, uint32 #edges, uint32 #words,
+//	             uint32 isEnd
+//	edges        per edge: int32 rune, uint32 target node
+//	frequencies  per word in position order: uint64 frequency
+//
+// Every record has a fixed size, so a node, an edge or a frequency is read
+// straight from its offset and the file never has to be decoded as a whole.
+const magicIndex = "ACI1"
+
+const (
+	indexHeaderSize = 20
+	indexNodeSize   = 16
+	indexEdgeSize   = 8
+	indexFreqSize   = 8
+)
+
+// WriteIndex writes the DAWG to w in the format read by OpenIndex.
+func (d *DAWG) WriteIndex(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, indexHeaderSize)
+	buf = append(buf, magicIndex...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(d.nodes)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(d.edges)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(d.frequencies)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(d.root))
+	bw.Write(buf)
+
+	for _, n := range d.nodes {
+		var isEnd uint32
+		if n.isEnd {
+			isEnd = 1
+		}
+		buf = buf[:0]
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(n.first))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(n.n))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(n.words))
+		buf = binary.LittleEndian.AppendUint32(buf, isEnd)
+		bw.Write(buf)
+	}
+	for _, e := range d.edges {
+		buf = buf[:0]
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(e.char))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(e.to))
+		bw.Write(buf)
+	}
+	for _, f := range d.frequencies {
+		buf = binary.LittleEndian.AppendUint64(buf[:0], uint64(f))
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// MappedIndex queries an index file written by DAWG.WriteIndex in place.
+// Where the platform supports it the file is memory-mapped, so opening it
+// costs almost no heap memory, pages are loaded only when a query touches
+// them and processes opening the same file share one copy in the page cache.
+//
+// OpenIndex checks the header and the file size but not every offset, so
+// the file must come from WriteIndex. A MappedIndex is safe for concurrent
+// queries; it must not be used after Close.
+type MappedIndex struct {
+	data   []byte
+	nodes  []byte
+	edges  []byte
+	freqs  []byte
+	words  int
+	root   int32
+	unmap  func([]byte) error
+	closed bool
+}
+
+// OpenIndex maps the index file at path.
+func OpenIndex(path string) (*MappedIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < indexHeaderSize {
+		return nil, fmt.Errorf("%w: index file too short", errBadSnapshot)
+	}
+	data, unmap, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMappedIndex(data)
+	if err != nil {
+		unmap(data)
+		return nil, err
+	}
+	m.unmap = unmap
+	return m, nil
+}
+
+func newMappedIndex(data []byte) (*MappedIndex, error) {
+	if string(data[:len(magicIndex)]) != magicIndex {
+		return nil, fmt.Errorf("%w: expected magic %q, got %q", errBadSnapshot, magicIndex, data[:len(magicIndex)])
+	}
+	header := data[len(magicIndex):indexHeaderSize]
+	nodes := uint64(binary.LittleEndian.Uint32(header[0:]))
+	edges := uint64(binary.LittleEndian.Uint32(header[4:]))
+	words := uint64(binary.LittleEndian.Uint32(header[8:]))
+	root := uint64(binary.LittleEndian.Uint32(header[12:]))
+
+	edgesAt := indexHeaderSize + nodes*indexNodeSize
+	freqsAt := edgesAt + edges*indexEdgeSize
+	size := freqsAt + words*indexFreqSize
+	if size != uint64(len(data)) {
+		return nil, fmt.Errorf("%w: index is %d bytes, header describes %d", errBadSnapshot, len(data), size)
+	}
+	if root >= nodes {
+		return nil, fmt.Errorf("%w: root node %d out of range", errBadSnapshot, root)
+	}
+	return &MappedIndex{
+		data:  data,
+		nodes: data[indexHeaderSize:edgesAt],
+		edges: data[edgesAt:freqsAt],
+		freqs: data[freqsAt:],
+		words: int(words),
+		root:  int32(root),
+	}, nil
+}
+
+// Len returns the number of distinct words in the index.
+func (m *MappedIndex) Len() int {
+	return m.words
+}
+
+// Autocomplete returns the same suggestions as DAWG.Autocomplete on the
+// DAWG the index was written from.
+func (m *MappedIndex) Autocomplete(prefix string, k int) []Suggestion {
+	return dawgAutocomplete(m, m.root, prefix, k)
+}
+
+// Close unmaps the file. Suggestions already returned stay valid.
+func (m *MappedIndex) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	m.nodes, m.edges, m.freqs = nil, nil, nil
+	return m.unmap(m.data)
+}
+
+func (m *MappedIndex) node(i int32) dawgNode {
+	b := m.nodes[int(i)*indexNodeSize:]
+	return dawgNode{
+		first: int32(binary.LittleEndian.Uint32(b[0:])),
+		n:     int32(binary.LittleEndian.Uint32(b[4:])),
+		words: int32(binary.LittleEndian.Uint32(b[8:])),
+		isEnd: binary.LittleEndian.Uint32(b[12:]) != 0,
+	}
+}
+
+func (m *MappedIndex) edge(i int32) dawgEdge {
+	b := m.edges[int(i)*indexEdgeSize:]
+	return dawgEdge{
+		char: rune(binary.LittleEndian.Uint32(b[0:])),
+		to:   int32(binary.LittleEndian.Uint32(b[4:])),
+	}
+}
+
+func (m *MappedIndex) frequency(index int) int {
+	return int(binary.LittleEndian.Uint64(m.freqs[index*indexFreqSize:]))
+}
@@ -0,0 +1,71 @@
+package autocomplete
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeIndexFile(t *testing.T, dawg *DAWG) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "index.aci")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dawg.WriteIndex(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMappedIndexMatchesDAWG(t *testing.T) {
+	trie := buildAlg2Trie([]string{
+		"walking", "talking", "talking", "walked", "talked",
+		"héllo", "hello", "hello", "help", "he",
+	})
+	dawg := trie.Freeze()
+	index, err := OpenIndex(writeIndexFile(t, dawg))
+	if err != nil {
+		t.Fatalf("Expected the index to open, got %v", err)
+	}
+	defer index.Close()
+
+	if index.Len() != dawg.Len() {
+		t.Errorf("Expected %d words, got %d", dawg.Len(), index.Len())
+	}
+	for _, prefix := range []string{"", "h", "hé", "hel", "t", "walk", "x"} {
+		want, got := dawg.Autocomplete(prefix, 10), index.Autocomplete(prefix, 10)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("For prefix %q: expected %v, got %v", prefix, want, got)
+		}
+	}
+}
+
+func TestOpenIndexRejectsBadFiles(t *testing.T) {
+	path := writeIndexFile(t, buildAlg2Trie([]string{"hello", "help"}).Freeze())
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("XXXX"), data[4:]...),
+		"truncated": data[:len(data)-1],
+	}
+	for name, content := range cases {
+		bad := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(bad, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenIndex(bad); !errors.Is(err, errBadSnapshot) {
+			t.Errorf("For %s file: expected an invalid snapshot error, got %v", name, err)
+		}
+	}
+}
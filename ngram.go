@@ -0,0 +1,135 @@
+package autocomplete
+
+import "strings"
+
+// -----------------------------------------
+// N-gram Context Model
+// -----------------------------------------
+
+// ngramSeparator joins context words into ngramTable keys. It is a control
+// character so it cannot collide with the spaces of multi-word entries.
+const ngramSeparator = "\x1f"
+
+// BuildNgramTable counts, for every position in corpus, the word that follows
+// each of the preceding contexts of one up to n-1 words. Single-word contexts
+// go to the bigram table exactly as BuildBigramTable would count them, so
+// BuildNgramTable(corpus, 2) is the same as BuildBigramTable(corpus). A
+// context never spans a stopword or a word rejected by validation. n below 2
+// is treated as 2.
+func (t *TrieA1) BuildNgramTable(corpus []string, n int) {
+	t.countNgrams(t.tokenize(corpus), n)
+}
+
+// countNgrams is BuildNgramTable over an already tokenized corpus.
+func (t *TrieA1) countNgrams(corpus []string, n int) {
+	n = max(n, 2)
+	t.countBigrams(corpus)
+	t.ngramOrder = n
+	if t.ngramTable == nil {
+		t.ngramTable = make(map[string]map[string]int)
+	}
+
+	words := t.contextSequence(t.contextWords(corpus))
+	for i := 1; i < len(words); i++ {
+		if words[i] == "" || words[i-1] == "" {
+			continue
+		}
+		for length := 2; length < n && i-length >= 0 && words[i-length] != ""; length++ {
+			countFollower(t.ngramTable, strings.Join(words[i-length:i], ngramSeparator), words[i])
+		}
+	}
+	t.cache.clear()
+}
+
+// AutocompleteContext completes prefix ranked by how often each completion
+// followed the last N-1 words of context, where N is the order passed to
+// BuildNgramTable (2 if it was never called). If that context never occurred
+// it backs off to ever shorter contexts, and to plain frequency when not even
+// the previous word was seen.
+func (t *TrieA1) AutocompleteContext(prefix string, context []string, k int) []Suggestion {
+	// Only the words lookupContext can use distinguish cache entries.
+	context = t.trimContext(context)
+	key := cacheKey{prefix: prefix, context: strings.Join(context, ngramSeparator), k: k}
+	if cached, ok := t.cache.get(key); ok {
+		return cached
+	}
+	suggestions, _ := t.autocompleteBounded([]rune(prefix), prefix, query{context: context}, k)
+	result := t.fillFromFallback(prefix, k, suggestions)
+	t.cache.put(key, result)
+	return result
+}
+
+// lookupContext returns the follower counts of the longest trailing part of
+// context known to the model, or nil if there is none.
+func (t *TrieA1) lookupContext(context []string) map[string]int {
+	context = t.trimContext(context)
+	words := make([]string, 0, len(context))
+	for _, word := range context {
+		word, ok := normalizeWord(word, t.strict)
+		if t.contextStopwords[word] && t.bridgeStopwords {
+			continue
+		}
+		if !ok || t.stopwords[word] || t.contextStopwords[word] {
+			// Contexts never span an unusable word, so only what follows it counts.
+			words = words[:0]
+			continue
+		}
+		words = append(words, word)
+	}
+
+	for ; len(words) > 1; words = words[1:] {
+		if contextData, exists := t.ngramTable[strings.Join(words, ngramSeparator)]; exists {
+			return contextData
+		}
+	}
+	if len(words) == 1 {
+		return t.bigramTable[words[0]]
+	}
+	return nil
+}
+
+// trimContext returns the trailing part of context the model can use: the
+// last N-1 words, not counting bridged context stop words.
+func (t *TrieA1) trimContext(context []string) []string {
+	need := max(t.ngramOrder, 2) - 1
+	start := len(context)
+	for start > 0 && need > 0 {
+		start--
+		if word, _ := normalizeWord(context[start], t.strict); !t.bridgeStopwords || !t.contextStopwords[word] {
+			need--
+		}
+	}
+	return context[start:]
+}
+
+// PredictNext returns the k words most likely to follow context, before any
+// of the next word is typed, like the suggestion strip of a phone keyboard.
+// Scores are conditional probabilities from the longest known trailing part
+// of context, as in AutocompleteContext. With no usable context it returns
+// the most frequent words.
+func (t *TrieA1) PredictNext(context []string, k int) []Suggestion {
+	if followers := t.topFollowers(context, k); len(followers) > 0 {
+		return followers
+	}
+	return t.Autocomplete("", k)
+}
+
+// topFollowers returns the k words most likely to follow context, scored by
+// their conditional probability. Followers no longer in the trie are
+// skipped.
+func (t *TrieA1) topFollowers(context []string, k int) []Suggestion {
+	contextData := t.lookupContext(context)
+	total := float64(contextData["_total"])
+	var followers []Suggestion
+	for word, count := range contextData {
+		if word == "_total" || count == 0 {
+			continue
+		}
+		node := t.searchPrefix(word)
+		if node == nil || !node.isEnd || !t.filter.allows(word) {
+			continue
+		}
+		followers = append(followers, Suggestion{Word: word, Score: float64(count) / total, Frequency: node.frequency})
+	}
+	return topK(followers, k)
+}
@@ -0,0 +1,133 @@
+package autocomplete
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAutocompleteContextTrigram(t *testing.T) {
+	// After "the" both "cat" and "car" follow, but after "drove the" only
+	// "car" does and after "fed the" only "cat" does.
+	corpus := []string{
+		"fed", "the", "cat", "fed", "the", "cat", "fed", "the", "cat",
+		"drove", "the", "car",
+	}
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(corpus)
+	trie.BuildNgramTable(corpus, 3)
+
+	tests := []struct {
+		context []string
+		want    string
+	}{
+		{[]string{"fed", "the"}, "cat"},
+		{[]string{"drove", "the"}, "car"},
+		{[]string{"I", "drove", "the"}, "car"}, // only the last N-1 words count
+		{[]string{"unseen", "the"}, "cat"},     // backs off to the bigram "the"
+		{nil, "cat"},                           // no context: frequency
+	}
+	for _, test := range tests {
+		got := trie.AutocompleteContext("ca", test.context, 2)
+		if len(got) == 0 || got[0].Word != test.want {
+			t.Errorf("For context %v: expected %q first, got %v", test.context, test.want, got)
+		}
+	}
+
+	got := trie.AutocompleteContext("ca", []string{"drove", "the"}, 2)
+	if got[0].Score != 1 {
+		t.Errorf("Expected probability 1 for car after \"drove the\", got %v", got[0].Score)
+	}
+}
+
+func TestBuildNgramTableOrderTwoMatchesBigrams(t *testing.T) {
+	corpus := []string{"hello", "world", "hello", "there"}
+	bigrams := NewTrieA1()
+	bigrams.BuildBigramTable(corpus)
+	ngrams := NewTrieA1()
+	ngrams.BuildNgramTable(corpus, 2)
+
+	if !reflect.DeepEqual(ngrams.bigramTable, bigrams.bigramTable) {
+		t.Errorf("Expected bigram table %v, got %v", bigrams.bigramTable, ngrams.bigramTable)
+	}
+	if len(ngrams.ngramTable) != 0 {
+		t.Errorf("Expected no longer contexts for order 2, got %v", ngrams.ngramTable)
+	}
+}
+
+func TestNgramTableSurvivesSnapshotAndMerge(t *testing.T) {
+	corpus := []string{"a", "b", "c", "a", "b", "d", "a", "b", "c"}
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(corpus)
+	trie.BuildNgramTable(corpus, 3)
+
+	var buf bytes.Buffer
+	if err := trie.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := NewTrieA1()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.ngramOrder != 3 || !reflect.DeepEqual(loaded.ngramTable, trie.ngramTable) {
+		t.Errorf("Expected order 3 and table %v, got order %d and %v", trie.ngramTable, loaded.ngramOrder, loaded.ngramTable)
+	}
+
+	merged := NewTrieA1()
+	merged.Merge(trie)
+	if merged.ngramOrder != 3 || !reflect.DeepEqual(merged.ngramTable, trie.ngramTable) {
+		t.Errorf("Expected Merge to copy the n-gram table")
+	}
+}
+
+func TestAutocompleteWithContext(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "hello", "hell", "helicopter", "hell", "help"})
+
+	// Without context "hello" is the most frequent; after "hell" only
+	// "helicopter" and "help" ever followed.
+	if got := trie.Autocomplete("hel", 1); got[0].Word != "hello" {
+		t.Errorf("Expected hello without context, got %v", got)
+	}
+	got := trie.AutocompleteWithContext("hell", "hel", 2)
+	if want := []string{"helicopter", "help"}; !reflect.DeepEqual(Words(got), want) {
+		t.Errorf("Expected %v after hell, got %v", want, got)
+	}
+	if got := trie.AutocompleteWithContext("", "hel", 1); got[0].Word != "hello" {
+		t.Errorf("Expected an empty previous word to rank by frequency, got %v", got)
+	}
+	if got := trie.AutocompleteWithContext("world", "hel", 1); got[0].Word != "hello" {
+		t.Errorf("Expected an unseen previous word to rank by frequency, got %v", got)
+	}
+}
+
+func TestPredictNext(t *testing.T) {
+	corpus := []string{"see", "you", "soon", "see", "you", "later", "see", "you", "soon", "thank", "you", "all"}
+	trie := NewTrieA1()
+	for _, w := range corpus {
+		trie.Insert(w)
+	}
+	trie.BuildNgramTable(corpus, 3)
+
+	got := trie.PredictNext([]string{"see", "you"}, 2)
+	if want := []string{"soon", "later"}; !reflect.DeepEqual(Words(got), want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got[0].Score != 2.0/3 || got[0].Frequency != 2 {
+		t.Errorf("Expected soon to score 2/3 with frequency 2, got %v", got[0])
+	}
+
+	if got := Words(trie.PredictNext([]string{"thank"}, 2)); !reflect.DeepEqual(got, []string{"you"}) {
+		t.Errorf("Expected [you] after thank, got %v", got)
+	}
+
+	// Without context the most frequent words are predicted.
+	if got := Words(trie.PredictNext(nil, 2)); !reflect.DeepEqual(got, []string{"you", "see"}) {
+		t.Errorf("Expected [you see] without context, got %v", got)
+	}
+
+	// Followers deleted from the trie are not predicted.
+	trie.Delete("later")
+	if got := Words(trie.PredictNext([]string{"see", "you"}, 2)); !reflect.DeepEqual(got, []string{"soon"}) {
+		t.Errorf("Expected [soon] after deleting later, got %v", got)
+	}
+}
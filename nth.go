@@ -0,0 +1,56 @@
+package autocomplete
+
+import "math/rand"
+
+// -----------------------------------------
+// N-th Ranked Completion
+// -----------------------------------------
+
+// NthCompletion returns the n-th best completion of prefix (1-based) in the
+// same order and with the same scores Autocomplete gives, honouring the
+// result options, scorer, filters and click-through counts, or false if
+// Autocomplete would return fewer than n. It uses quickselect, so it runs in
+// expected linear time in the number of completions instead of sorting all
+// of them.
+func (t *TrieA1) NthCompletion(prefix string, n int) (Suggestion, bool) {
+	if n < 1 || t.options.limit(n) < n {
+		return Suggestion{}, false
+	}
+	candidates := t.candidates(prefix)
+	if n > len(candidates) {
+		return Suggestion{}, false
+	}
+	nth := []Suggestion{quickselect(candidates, n-1)}
+	t.roundProbabilities(nth)
+	return nth[0], true
+}
+
+// quickselect returns the element that would be at index target if
+// suggestions were sorted with rankBefore. It reorders suggestions in place.
+func quickselect(suggestions []Suggestion, target int) Suggestion {
+	lo, hi := 0, len(suggestions)-1
+	for lo < hi {
+		// Move a random pivot to the end and partition around it.
+		p := lo + rand.Intn(hi-lo+1)
+		suggestions[p], suggestions[hi] = suggestions[hi], suggestions[p]
+		pivot := suggestions[hi]
+		store := lo
+		for i := lo; i < hi; i++ {
+			if rankBefore(suggestions[i], pivot) {
+				suggestions[i], suggestions[store] = suggestions[store], suggestions[i]
+				store++
+			}
+		}
+		suggestions[store], suggestions[hi] = suggestions[hi], suggestions[store]
+
+		switch {
+		case target == store:
+			return suggestions[store]
+		case target < store:
+			hi = store - 1
+		default:
+			lo = store + 1
+		}
+	}
+	return suggestions[lo]
+}
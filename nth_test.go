@@ -0,0 +1,73 @@
+package autocomplete
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNthCompletionMatchesRankedList(t *testing.T) {
+	var corpus []string
+	for i := 0; i < 40; i++ {
+		word := fmt.Sprintf("he%02d", i)
+		// A mix of distinct and tied frequencies.
+		for j := 0; j <= i%7; j++ {
+			corpus = append(corpus, word)
+		}
+	}
+	trie := buildAlg1Trie(corpus)
+	ranked := trie.Autocomplete("he", 100)
+
+	for _, n := range []int{1, 2, 5, 17, 39, 40} {
+		got, ok := trie.NthCompletion("he", n)
+		if !ok {
+			t.Fatalf("Expected a completion at n=%d", n)
+		}
+		if got != ranked[n-1] {
+			t.Errorf("n=%d: NthCompletion returned %v, ranked list has %v", n, got, ranked[n-1])
+		}
+	}
+}
+
+func TestNthCompletionFollowsScorerAndOptions(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "help", "help", "helium"}
+	// Shorter words first, the reverse of the frequency ranking.
+	byLength := ScorerFunc(func(candidate string, _ Context) float64 { return 1 / float64(len(candidate)) })
+
+	tests := []struct {
+		name string
+		trie *TrieA1
+	}{
+		{"scorer", buildAlg1Trie(corpus).WithScorer(byLength)},
+		{"min frequency", buildAlg1Trie(corpus).WithOptions(Options{MinFrequency: 2})},
+		{"max results", buildAlg1Trie(corpus).WithOptions(Options{MaxResults: 2})},
+	}
+	for _, test := range tests {
+		ranked := test.trie.Autocomplete("he", 10)
+		for n := 1; n <= 4; n++ {
+			got, ok := test.trie.NthCompletion("he", n)
+			if n > len(ranked) {
+				if ok {
+					t.Errorf("%s: expected nothing at n=%d past %v, got %v", test.name, n, Words(ranked), got)
+				}
+				continue
+			}
+			if !ok || got != ranked[n-1] {
+				t.Errorf("%s: n=%d: expected %v, got %v (%v)", test.name, n, ranked[n-1], got, ok)
+			}
+		}
+	}
+}
+
+func TestNthCompletionOutOfRange(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hell", "hero"})
+
+	if _, ok := trie.NthCompletion("he", 4); ok {
+		t.Errorf("Expected false when n exceeds the number of completions")
+	}
+	if _, ok := trie.NthCompletion("he", 0); ok {
+		t.Errorf("Expected false for n=0")
+	}
+	if _, ok := trie.NthCompletion("xyz", 1); ok {
+		t.Errorf("Expected false for an unknown prefix")
+	}
+}
@@ -0,0 +1,60 @@
+package autocomplete
+
+import "strings"
+
+// -----------------------------------------
+// Streaming Ingestion
+// -----------------------------------------
+
+// ObserveWord learns one word of a running stream, such as a live query log:
+// it inserts the word and counts it as the follower of the words observed
+// just before it, in the bigram table and, after BuildNgramTable, in the
+// n-gram table up to its order. A stopword or a word rejected by validation
+// breaks the stream, so no context spans it; a context stop word is inserted
+// but only breaks the stream if it is not bridged.
+func (t *TrieA1) ObserveWord(word string) {
+	normalized, ok := normalizeWord(word, t.strict)
+	if !ok || t.stopwords[normalized] {
+		t.Insert(word) // counts the rejection; stopwords are skipped
+		t.history = t.history[:0]
+		return
+	}
+	t.Insert(normalized)
+	if t.contextStopwords[normalized] {
+		if !t.bridgeStopwords {
+			t.history = t.history[:0]
+		}
+		return
+	}
+
+	for length := 1; length <= len(t.history); length++ {
+		context := t.history[len(t.history)-length:]
+		if length == 1 {
+			countFollower(t.bigramTable, context[0], normalized)
+			continue
+		}
+		if t.ngramTable == nil {
+			t.ngramTable = make(map[string]map[string]int)
+		}
+		countFollower(t.ngramTable, strings.Join(context, ngramSeparator), normalized)
+	}
+
+	t.history = append(t.history, normalized)
+	if keep := max(t.ngramOrder, 2) - 1; len(t.history) > keep {
+		t.history = append(t.history[:0], t.history[len(t.history)-keep:]...)
+	}
+	t.cache.clear()
+}
+
+// ObserveSequence learns words as one self-contained sequence, for example a
+// single query or sentence: the first word is not counted as following the
+// words observed before, and the next ObserveWord starts a fresh stream.
+// Observing a whole corpus this way gives the same model as inserting every
+// word and then calling BuildNgramTable with the current order.
+func (t *TrieA1) ObserveSequence(words []string) {
+	t.history = t.history[:0]
+	for _, word := range words {
+		t.ObserveWord(word)
+	}
+	t.history = t.history[:0]
+}
@@ -0,0 +1,52 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObserveSequenceMatchesBatchBuild(t *testing.T) {
+	corpus := []string{"fed", "the", "cat", "", "fed", "the", "dog", "drove", "the", "car"}
+
+	batch := NewTrieA1()
+	for _, w := range corpus {
+		batch.Insert(w)
+	}
+	batch.BuildNgramTable(corpus, 3)
+
+	streamed := NewTrieA1()
+	streamed.BuildNgramTable(nil, 3)
+	streamed.ObserveSequence(corpus)
+
+	if !reflect.DeepEqual(streamed.root, batch.root) || streamed.Len() != batch.Len() {
+		t.Errorf("Expected the streamed trie to match the batch build")
+	}
+	if !reflect.DeepEqual(streamed.bigramTable, batch.bigramTable) {
+		t.Errorf("Expected bigram table %v, got %v", batch.bigramTable, streamed.bigramTable)
+	}
+	if !reflect.DeepEqual(streamed.ngramTable, batch.ngramTable) {
+		t.Errorf("Expected n-gram table %v, got %v", batch.ngramTable, streamed.ngramTable)
+	}
+}
+
+func TestObserveWordLearnsLiveContext(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "help"})
+
+	for _, w := range []string{"please", "help", "please", "help"} {
+		trie.ObserveWord(w)
+	}
+	if got := trie.AutocompleteWithContext("please", "hel", 1); len(got) != 1 || got[0].Word != "help" {
+		t.Errorf("Expected help after please, got %v", got)
+	}
+	// "help" -> "please" was observed across the two calls as well.
+	if trie.bigramTable["help"]["please"] != 1 {
+		t.Errorf("Expected help followed by please once, got %d", trie.bigramTable["help"]["please"])
+	}
+
+	// Sequences do not link to each other.
+	trie.ObserveSequence([]string{"one"})
+	trie.ObserveSequence([]string{"two"})
+	if _, linked := trie.bigramTable["one"]; linked {
+		t.Errorf("Expected separate sequences not to be linked")
+	}
+}
@@ -0,0 +1,98 @@
+package autocomplete
+
+// -----------------------------------------
+// Result Options
+// -----------------------------------------
+
+// Options are result limits and cutoffs applied the same way by
+// Algorithm_1 and Algorithm_2 to Autocomplete and the queries built on it.
+// The zero value of every field means no limit.
+type Options struct {
+	// MaxResults caps k, however many results a caller asks for.
+	MaxResults int
+	// MinScore drops suggestions scoring below it.
+	MinScore float64
+	// MinFrequency drops words seen fewer times before scoring, so rare
+	// words neither appear nor take a share of the probability.
+	MinFrequency int
+	// MaxDepth skips completions more than MaxDepth characters longer than
+	// the prefix. The traversal stops at that depth, which also bounds the
+	// work done for very short prefixes.
+	MaxDepth int
+}
+
+// WithOptions sets the result options. It returns t so it can be chained
+// after NewTrieA1.
+func (t *TrieA1) WithOptions(o Options) *TrieA1 {
+	t.options = o
+	t.cache.clear()
+	return t
+}
+
+// WithOptions sets the result options. It returns t so it can be chained
+// after NewTriesA2.
+func (t *TriesA2) WithOptions(o Options) *TriesA2 {
+	t.options = o
+	return t
+}
+
+// query carries the parameters of a single query beyond prefix and k.
+type query struct {
+	context []string   // preceding words, most recent last
+	cancel  *canceller // nil if the query cannot be cancelled
+	only    Filter     // nil, or the words the query is restricted to
+}
+
+// restrict filters entries in place to the words the query is restricted
+// to.
+func (q query) restrict(entries []Suggestion) []Suggestion {
+	if q.only == nil {
+		return entries
+	}
+	return wordFilter{keep: q.only}.apply(entries)
+}
+
+// limit applies MaxResults to k.
+func (o Options) limit(k int) int {
+	if o.MaxResults > 0 && k > o.MaxResults {
+		return o.MaxResults
+	}
+	return k
+}
+
+// depth returns how many levels below the prefix node a traversal may
+// descend, or -1 for no limit.
+func (o Options) depth() int {
+	if o.MaxDepth > 0 {
+		return o.MaxDepth
+	}
+	return -1
+}
+
+// keepFrequent filters entries in place to those meeting MinFrequency.
+func (o Options) keepFrequent(entries []Suggestion) []Suggestion {
+	if o.MinFrequency <= 0 {
+		return entries
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Frequency >= o.MinFrequency {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// keepScoring filters suggestions in place to those meeting MinScore.
+func (o Options) keepScoring(suggestions []Suggestion) []Suggestion {
+	if o.MinScore <= 0 {
+		return suggestions
+	}
+	kept := suggestions[:0]
+	for _, s := range suggestions {
+		if s.Score >= o.MinScore {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,60 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	corpus := []string{
+		"he", "hello", "hello", "hello", "hello", "hell", "hell", "hell",
+		"helicopter", "helicopter", "help",
+	}
+	tries := map[string]interface {
+		Autocomplete(string, int) []Suggestion
+	}{
+		"Algorithm_1": buildAlg1Trie(corpus),
+		"Algorithm_2": buildAlg2Trie(corpus),
+	}
+	set := func(trie any, o Options) {
+		switch trie := trie.(type) {
+		case *TrieA1:
+			trie.WithOptions(o)
+		case *TriesA2:
+			trie.WithOptions(o)
+		}
+	}
+
+	cases := []struct {
+		name    string
+		options Options
+		want    []string
+	}{
+		{"none", Options{}, []string{"hello", "hell", "helicopter", "he", "help"}},
+		{"max results", Options{MaxResults: 2}, []string{"hello", "hell"}},
+		{"min frequency", Options{MinFrequency: 2}, []string{"hello", "hell", "helicopter"}},
+		// Without help and he, hello has 4 of 9 occurrences.
+		{"min frequency and score", Options{MinFrequency: 2, MinScore: 0.3}, []string{"hello", "hell"}},
+		{"min score", Options{MinScore: 0.3}, []string{"hello"}},
+		{"max depth", Options{MaxDepth: 2}, []string{"hell", "he", "help"}},
+	}
+	for name, trie := range tries {
+		for _, c := range cases {
+			set(trie, c.options)
+			if got := Words(trie.Autocomplete("he", 10)); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("%s, %s: expected %v, got %v", name, c.name, c.want, got)
+			}
+		}
+	}
+}
+
+func TestOptionsInvalidateCache(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "hell"})
+	trie.EnableCache()
+	trie.Autocomplete("he", 5)
+
+	trie.WithOptions(Options{MaxResults: 1})
+	if got := trie.Autocomplete("he", 5); len(got) != 1 {
+		t.Errorf("Expected the new options to apply to a cached prefix, got %v", got)
+	}
+}
@@ -0,0 +1,168 @@
+package autocomplete
+
+import (
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Merging and Parallel Build
+// -----------------------------------------
+
+// Merge adds every word, bigram and n-gram count of other into t. other is
+// left unchanged and shares no nodes with t afterwards.
+func (t *TrieA1) Merge(other *TrieA1) {
+	t.size += mergeNodesA1(t.root, other.root)
+	mergeCounts(t.bigramTable, other.bigramTable)
+	if other.ngramTable != nil {
+		if t.ngramTable == nil {
+			t.ngramTable = make(map[string]map[string]int)
+		}
+		mergeCounts(t.ngramTable, other.ngramTable)
+	}
+	t.ngramOrder = max(t.ngramOrder, other.ngramOrder)
+	t.unfinalize()
+	t.resetKeyIndexes()
+	t.cache.clear()
+}
+
+// mergeCounts adds every follower count of src into dst.
+func mergeCounts(dst, src map[string]map[string]int) {
+	for context, followers := range src {
+		if _, exists := dst[context]; !exists {
+			dst[context] = map[string]int{"_total": 0}
+		}
+		for word, count := range followers {
+			dst[context][word] += count
+		}
+	}
+}
+
+// mergeNodesA1 merges src into dst and returns how many words were new to dst.
+func mergeNodesA1(dst, src *TrieNodeA1) int {
+	added := 0
+	if src.isEnd {
+		if !dst.isEnd {
+			added++
+		}
+		dst.isEnd = true
+		dst.frequency += src.frequency
+	}
+	src.eachChild(func(char rune, srcChild *TrieNodeA1) {
+		added += mergeNodesA1(dst.addChild(char), srcChild)
+	})
+	dst.recount()
+	return added
+}
+
+// BuildFromCorpusParallel produces the same trie and bigram table as
+// BuildFromCorpus, but builds them on shards worker goroutines. Words are
+// sharded by their first rune, and bigrams by the first rune of the word
+// they follow, so the workers' sub-tries and tables never overlap and
+// merging them only attaches their subtrees to the root. A non-positive
+// shards uses one worker per CPU.
+func (t *TrieA1) BuildFromCorpusParallel(corpus []string, shards int) {
+	corpus = t.tokenize(corpus)
+	if shards <= 0 {
+		shards = runtime.NumCPU()
+	}
+	if shards > len(corpus) {
+		shards = len(corpus)
+	}
+	if shards <= 1 {
+		t.buildFromTokens(corpus)
+		return
+	}
+
+	words, positions := t.shardCorpus(corpus, shards)
+	followers := t.contextFollowers(words)
+
+	parts := make([]*TrieA1, shards)
+	var wg sync.WaitGroup
+	for shard := range parts {
+		part := NewTrieA1().WithCompactNodes(t.compactNodes)
+		parts[shard] = part
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, chunk := range positions {
+				for _, i := range chunk[shard] {
+					part.insertNormalized(words[i], 1)
+					if followers[i] != "" {
+						countFollower(part.bigramTable, words[i], followers[i])
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, part := range parts {
+		part.root.eachChild(func(char rune, child *TrieNodeA1) {
+			added := child.words
+			if existing := t.root.child(char); existing != nil {
+				added = mergeNodesA1(existing, child)
+			} else {
+				t.root.setChild(char, child)
+			}
+			t.size += added
+		})
+		t.root.recount()
+		for context, followers := range part.bigramTable {
+			existing, ok := t.bigramTable[context]
+			if !ok {
+				t.bigramTable[context] = followers
+				continue
+			}
+			for word, count := range followers {
+				existing[word] += count
+			}
+		}
+	}
+	t.resetKeyIndexes()
+	t.unfinalize()
+	t.cache.clear()
+}
+
+// shardCorpus normalizes every word of corpus as Insert would, leaving ""
+// for rejected words and stopwords, and assigns the position of every other
+// word to the shard of its first rune. Both happen concurrently over
+// contiguous chunks of corpus, so positions holds one list per chunk and
+// shard, each in corpus order.
+func (t *TrieA1) shardCorpus(corpus []string, shards int) (words []string, positions [][][]int) {
+	words = make([]string, len(corpus))
+	size := (len(corpus) + shards - 1) / shards
+	positions = make([][][]int, (len(corpus)+size-1)/size)
+	rejected := make([]int, len(positions))
+
+	var wg sync.WaitGroup
+	for c := range positions {
+		lo, hi := c*size, min((c+1)*size, len(corpus))
+		positions[c] = make([][]int, shards)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				word, ok := normalizeWord(corpus[i], t.strict)
+				if !ok {
+					rejected[c]++
+					continue
+				}
+				if t.stopwords[word] {
+					continue
+				}
+				words[i] = word
+				first, _ := utf8.DecodeRuneInString(word)
+				shard := int(uint32(first) % uint32(shards))
+				positions[c][shard] = append(positions[c][shard], i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, n := range rejected {
+		t.rejected += n
+	}
+	return words, positions
+}
@@ -0,0 +1,102 @@
+package autocomplete
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func syntheticCorpus(n int) []string {
+	rng := rand.New(rand.NewSource(1))
+	corpus := make([]string, n)
+	for i := range corpus {
+		// Vary the first letter so the words spread over several shards.
+		id := rng.Intn(n/10 + 1)
+		corpus[i] = fmt.Sprintf("%c%d", 'a'+id%26, id)
+	}
+	return corpus
+}
+
+func TestMerge(t *testing.T) {
+	a := buildAlg1Trie([]string{"hello", "hell", "hello"})
+	b := buildAlg1Trie([]string{"hello", "hero"})
+
+	a.Merge(b)
+
+	if node := a.searchPrefix("hello"); node == nil || node.frequency != 3 {
+		t.Errorf("Expected merged frequency 3 for 'hello'")
+	}
+	if !a.Contains("hero") || a.Len() != 3 {
+		t.Errorf("Expected 'hero' after merge and 3 distinct words, got %d", a.Len())
+	}
+	if a.bigramTable["hello"]["hero"] != 1 || a.bigramTable["hello"]["_total"] != 2 {
+		t.Errorf("Unexpected merged bigrams for 'hello': %v", a.bigramTable["hello"])
+	}
+	if b.Contains("hell") {
+		t.Errorf("Merge must not modify the other trie")
+	}
+}
+
+func TestBuildFromCorpusParallelMatchesSequential(t *testing.T) {
+	corpus := syntheticCorpus(5000)
+
+	sequential := NewTrieA1()
+	sequential.BuildFromCorpus(corpus)
+
+	for _, shards := range []int{2, 3, 7, 64} {
+		parallel := NewTrieA1()
+		parallel.BuildFromCorpusParallel(corpus, shards)
+
+		if !reflect.DeepEqual(sequential.root, parallel.root) {
+			t.Errorf("%d shards: trie differs from the sequential build", shards)
+		}
+		if !reflect.DeepEqual(sequential.bigramTable, parallel.bigramTable) {
+			t.Errorf("%d shards: bigram table differs from the sequential build", shards)
+		}
+	}
+}
+
+func TestBuildFromCorpusParallelSettingsAndExistingWords(t *testing.T) {
+	corpus := append(syntheticCorpus(2000), " b1 ", "  ", "the", "c2", "", "d3")
+	build := func(parallel bool) *TrieA1 {
+		trie := NewTrieA1()
+		trie.SetStrict(true)
+		trie.SetStopwords([]string{"the", "f5"})
+		trie.BuildFromCorpus([]string{"b1", "c2", "zebra"})
+		if parallel {
+			trie.BuildFromCorpusParallel(corpus, 4)
+		} else {
+			trie.BuildFromCorpus(corpus)
+		}
+		return trie
+	}
+	sequential, parallel := build(false), build(true)
+
+	if !reflect.DeepEqual(sequential.root, parallel.root) {
+		t.Errorf("Trie differs from the sequential build")
+	}
+	if !reflect.DeepEqual(sequential.bigramTable, parallel.bigramTable) {
+		t.Errorf("Bigram table differs from the sequential build")
+	}
+	if sequential.Len() != parallel.Len() || sequential.Rejected() != parallel.Rejected() {
+		t.Errorf("Expected %d words and %d rejected, got %d and %d",
+			sequential.Len(), sequential.Rejected(), parallel.Len(), parallel.Rejected())
+	}
+}
+
+func BenchmarkBuildFromCorpus(b *testing.B) {
+	corpus := syntheticCorpus(200000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewTrieA1().BuildFromCorpus(corpus)
+	}
+}
+
+func BenchmarkBuildFromCorpusParallel(b *testing.B) {
+	corpus := syntheticCorpus(200000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewTrieA1().BuildFromCorpusParallel(corpus, 0)
+	}
+}
@@ -0,0 +1,94 @@
+package autocomplete
+
+import "slices"
+
+// -----------------------------------------
+// Word Metadata
+// -----------------------------------------
+
+// Payload is metadata attached to a stored word, turning suggestions into
+// entity suggestions: a completion of "par" can be the city Paris with its
+// ID rather than just a string.
+type Payload struct {
+	Category   string            `json:"category,omitempty"`
+	ID         int64             `json:"id,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// HasTag reports whether tag is the payload's category or one of its tags.
+func (p Payload) HasTag(tag string) bool {
+	return p.Category == tag || slices.Contains(p.Tags, tag)
+}
+
+// hasAnyTag reports whether the payload has at least one of tags.
+func (p Payload) hasAnyTag(tags []string) bool {
+	return slices.ContainsFunc(tags, p.HasTag)
+}
+
+// taggedWords restricts a query to the words whose payload has one of tags.
+func taggedWords(payloads map[string]Payload, tags []string) Filter {
+	return func(word string) bool {
+		p, ok := payloads[word]
+		return ok && p.hasAnyTag(tags)
+	}
+}
+
+// InsertWithPayload inserts word like Insert and attaches p to it,
+// replacing any payload attached before. Nothing is attached if Insert
+// ignores the word. Payloads are dropped when the word is removed or a
+// snapshot is loaded, and are not part of snapshots.
+func (t *TrieA1) InsertWithPayload(word string, p Payload) {
+	t.Insert(word)
+	word, ok := normalizeWord(word, t.strict)
+	if node := t.searchPrefix(word); !ok || node == nil || !node.isEnd {
+		return
+	}
+	if t.payloads == nil {
+		t.payloads = make(map[string]Payload)
+	}
+	t.payloads[word] = p
+}
+
+// Payload returns the metadata attached to word, if any.
+func (t *TrieA1) Payload(word string) (Payload, bool) {
+	p, ok := t.payloads[word]
+	return p, ok
+}
+
+// AutocompleteTagged is Autocomplete restricted to words whose payload has
+// at least one of tags as its category or a tag; probabilities are shares
+// among those words. Results are not cached.
+func (t *TrieA1) AutocompleteTagged(prefix string, k int, tags ...string) []Suggestion {
+	suggestions, _ := t.autocompleteBounded([]rune(prefix), prefix, query{only: taggedWords(t.payloads, tags)}, k)
+	return suggestions
+}
+
+// InsertWithPayload inserts word like Insert and attaches p to it,
+// replacing any payload attached before. Nothing is attached if Insert
+// ignores the word. Payloads are dropped when the word is removed or a
+// snapshot is loaded, and are not part of snapshots.
+func (t *TriesA2) InsertWithPayload(word string, p Payload) {
+	t.Insert(word)
+	word, ok := normalizeWord(word, t.strict)
+	if node := t.searchPrefix(word); !ok || node == nil || !node.isEndOfWord {
+		return
+	}
+	if t.payloads == nil {
+		t.payloads = make(map[string]Payload)
+	}
+	t.payloads[word] = p
+}
+
+// Payload returns the metadata attached to word, if any.
+func (t *TriesA2) Payload(word string) (Payload, bool) {
+	p, ok := t.payloads[word]
+	return p, ok
+}
+
+// AutocompleteTagged is Autocomplete restricted to words whose payload has
+// at least one of tags as its category or a tag; probabilities are shares
+// among those words.
+func (t *TriesA2) AutocompleteTagged(prefix string, k int, tags ...string) []Suggestion {
+	return t.autocompleteProb(prefix, k, query{only: taggedWords(t.payloads, tags)})
+}
@@ -0,0 +1,50 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPayloadsAndTags(t *testing.T) {
+	type entityTrie interface {
+		Insert(word string)
+		InsertWithPayload(word string, p Payload)
+		Payload(word string) (Payload, bool)
+		AutocompleteTagged(prefix string, k int, tags ...string) []Suggestion
+		Delete(word string) bool
+	}
+	tries := map[string]entityTrie{"Algorithm_1": NewTrieA1(), "Algorithm_2": NewTriesA2()}
+
+	for name, trie := range tries {
+		trie.InsertWithPayload("paris", Payload{Category: "city", ID: 42})
+		trie.InsertWithPayload("paris", Payload{Category: "city", ID: 42, Tags: []string{"capital"}})
+		trie.InsertWithPayload("parker", Payload{Category: "person", ID: 7})
+		trie.InsertWithPayload("parma", Payload{Category: "city", ID: 9})
+		trie.Insert("parse")
+		trie.Insert("parse")
+		trie.Insert("parse")
+
+		if p, ok := trie.Payload("paris"); !ok || p.ID != 42 || !p.HasTag("capital") {
+			t.Errorf("%s: expected the latest payload for paris, got %+v, %v", name, p, ok)
+		}
+		if _, ok := trie.Payload("parse"); ok {
+			t.Errorf("%s: expected no payload for a plain insert", name)
+		}
+
+		cities := trie.AutocompleteTagged("par", 5, "city")
+		if words := Words(cities); !reflect.DeepEqual(words, []string{"paris", "parma"}) {
+			t.Errorf("%s: expected [paris parma], got %v", name, words)
+		}
+		if cities[0].Score != 2.0/3 {
+			t.Errorf("%s: expected paris to hold 2/3 of the cities, got %v", name, cities[0].Score)
+		}
+		if words := Words(trie.AutocompleteTagged("par", 5, "capital", "person")); !reflect.DeepEqual(words, []string{"paris", "parker"}) {
+			t.Errorf("%s: expected [paris parker] for either tag, got %v", name, words)
+		}
+
+		trie.Delete("parma")
+		if _, ok := trie.Payload("parma"); ok {
+			t.Errorf("%s: expected the payload to go with the word", name)
+		}
+	}
+}
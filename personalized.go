@@ -0,0 +1,200 @@
+package autocomplete
+
+import (
+	"sort"
+	"strings"
+)
+
+// -----------------------------------------
+// Per-User Personalization
+// -----------------------------------------
+
+// PersonalizedTrie layers one user's frequency boosts over a shared
+// Algorithm_2 dictionary. The base is only ever read, so any number of
+// PersonalizedTries can wrap the same *TriesA2 without copying it, as long
+// as nothing writes to the base while they query it.
+type PersonalizedTrie struct {
+	base   *TriesA2
+	boosts map[string]int
+}
+
+// NewPersonalizedTrie returns an overlay with no boosts over base.
+func NewPersonalizedTrie(base *TriesA2) *PersonalizedTrie {
+	return &PersonalizedTrie{base: base, boosts: make(map[string]int)}
+}
+
+// Boost adds delta to the user's frequency for word. Words the base does not
+// contain are suggested to this user alone; a negative delta can lower or
+// hide a base word for them.
+func (p *PersonalizedTrie) Boost(word string, delta int) {
+	p.boosts[word] += delta
+	if p.boosts[word] == 0 {
+		delete(p.boosts, word)
+	}
+}
+
+// Autocomplete returns up to k completions of prefix ranked by base
+// frequency plus the user's boost, with that sum as each suggestion's
+// Frequency and its share of the candidates' total as its Score. Words
+// whose sum is not positive, and words the base's blocklist or filter
+// hides, are left out.
+func (p *PersonalizedTrie) Autocomplete(prefix string, k int) []Suggestion {
+	frequencies := make(map[string]int)
+	if node := p.base.searchPrefix(prefix); node != nil {
+		var entries []Suggestion
+		collectEntriesA2(node, prefix, &entries)
+		for _, e := range entries {
+			frequencies[e.Word] = e.Frequency
+		}
+	}
+	for word, delta := range p.boosts {
+		if strings.HasPrefix(word, prefix) {
+			frequencies[word] += delta
+		}
+	}
+
+	var candidates []Suggestion
+	total := 0
+	for word, frequency := range frequencies {
+		if frequency > 0 && p.base.filter.allows(word) {
+			candidates = append(candidates, Suggestion{Word: word, Frequency: frequency})
+			total += frequency
+		}
+	}
+	for i := range candidates {
+		candidates[i].Score = float64(candidates[i].Frequency) / float64(total)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return rankBefore(candidates[i], candidates[j]) })
+	return candidates[:clampK(k, len(candidates))]
+}
+
+// Personalizer keeps a small overlay per user on top of a shared Algorithm_1
+// model: how often the user chose each word, and which words they chose
+// after which. Queries blend the user's overlay with the global ranking, so
+// one user's habits reach the top of their own suggestions without touching
+// the shared trie. Like the tries, it is not safe for concurrent use.
+type Personalizer struct {
+	global     *TrieA1
+	userWeight float64
+	users      map[string]*userOverlay
+}
+
+// userOverlay is one user's history of chosen words.
+type userOverlay struct {
+	frequencies map[string]int
+	bigrams     map[string]map[string]int
+	last        string // most recently chosen word, the context for the next
+}
+
+// defaultUserWeight gives the user's history and the global model equal say.
+const defaultUserWeight = 0.5
+
+// NewPersonalizer returns a Personalizer over global with no user history.
+// The global trie is only read.
+func NewPersonalizer(global *TrieA1) *Personalizer {
+	return &Personalizer{global: global, userWeight: defaultUserWeight, users: make(map[string]*userOverlay)}
+}
+
+// SetUserWeight sets how much a user's own history counts against the global
+// ranking: a score is (1-w)*global + w*user. Values outside [0, 1] are
+// clamped.
+func (p *Personalizer) SetUserWeight(w float64) {
+	p.userWeight = min(max(w, 0), 1)
+}
+
+// Record notes that userID chose word, counting it for the user and as the
+// follower of the word they chose before.
+func (p *Personalizer) Record(userID, word string) {
+	u := p.user(userID)
+	u.frequencies[word]++
+	if u.last != "" {
+		countFollower(u.bigrams, u.last, word)
+	}
+	u.last = word
+}
+
+// Boost adds delta to how often userID chose word, as if they had chosen it
+// delta more times, without making it the context of their next choice. A
+// negative delta lowers the count; a word whose count drops to zero or below
+// is dropped from the user's history.
+func (p *Personalizer) Boost(userID, word string, delta int) {
+	u := p.user(userID)
+	u.frequencies[word] += delta
+	if u.frequencies[word] <= 0 {
+		delete(u.frequencies, word)
+	}
+}
+
+// user returns the overlay of userID, creating an empty one if needed.
+func (p *Personalizer) user(userID string) *userOverlay {
+	u, ok := p.users[userID]
+	if !ok {
+		u = &userOverlay{frequencies: make(map[string]int), bigrams: make(map[string]map[string]int)}
+		p.users[userID] = u
+	}
+	return u
+}
+
+// Forget drops everything recorded for userID.
+func (p *Personalizer) Forget(userID string) {
+	delete(p.users, userID)
+}
+
+// AutocompleteForUser returns up to k completions of prefix for userID. Each
+// completion's global score is blended with its share of the user's history:
+// the words the user chose after their last chosen word if there are any
+// among the completions, otherwise all words they chose. Words only the user
+// ever chose are suggested too. Users without history get the global
+// ranking.
+func (p *Personalizer) AutocompleteForUser(userID, prefix string, k int) []Suggestion {
+	var global []Suggestion
+	if node := p.global.searchPrefix(prefix); node != nil {
+		completions := p.global.filter.apply(p.global.collectCompletions(node, []rune(prefix)))
+		global = p.global.scoreCompletions(prefix, completions)
+	}
+	u, ok := p.users[userID]
+	if !ok {
+		return topK(global, k)
+	}
+
+	scores := make(map[string]Suggestion, len(global))
+	for _, s := range global {
+		s.Score *= 1 - p.userWeight
+		scores[s.Word] = s
+	}
+
+	counts := u.matching(prefix, u.bigrams[u.last], p.global.filter)
+	if len(counts) == 0 {
+		counts = u.matching(prefix, u.frequencies, p.global.filter)
+	}
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	for word, count := range counts {
+		s, ok := scores[word]
+		if !ok {
+			s = Suggestion{Word: word}
+		}
+		s.Score += p.userWeight * float64(count) / float64(total)
+		scores[word] = s
+	}
+
+	merged := make([]Suggestion, 0, len(scores))
+	for _, s := range scores {
+		merged = append(merged, s)
+	}
+	return topK(merged, k)
+}
+
+// matching returns the entries of counts whose word starts with prefix and
+// passes filter.
+func (u *userOverlay) matching(prefix string, counts map[string]int, filter wordFilter) map[string]int {
+	matched := make(map[string]int)
+	for word, count := range counts {
+		if word != "_total" && strings.HasPrefix(word, prefix) && filter.allows(word) {
+			matched[word] = count
+		}
+	}
+	return matched
+}
@@ -0,0 +1,102 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPersonalizedTrieOverlays(t *testing.T) {
+	base := buildAlg2Trie([]string{"hello", "hello", "help", "hero"})
+
+	alice := NewPersonalizedTrie(base)
+	alice.Boost("hero", 5)
+	bob := NewPersonalizedTrie(base)
+	bob.Boost("help", 5)
+	bob.Boost("helium", 1)
+
+	if got, want := Words(alice.Autocomplete("he", 3)), []string{"hero", "hello", "help"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Alice: expected %v, got %v", want, got)
+	}
+	if got, want := Words(bob.Autocomplete("he", 4)), []string{"help", "hello", "helium", "hero"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Bob: expected %v, got %v", want, got)
+	}
+	if got := bob.Autocomplete("help", 1); got[0].Frequency != 6 || got[0].Score != 1 {
+		t.Errorf("Expected 'help' with base plus boost frequency 6, got %+v", got[0])
+	}
+
+	// The shared base is untouched.
+	if base.Frequency("hero") != 1 || base.Frequency("helium") != 0 {
+		t.Errorf("Personal boosts must not modify the base trie")
+	}
+	if got := base.AutocompleteTopK("he", 1); got[0] != "hello" {
+		t.Errorf("Expected base ranking to be unchanged, got %v", got)
+	}
+}
+
+func TestPersonalizer(t *testing.T) {
+	global := buildAlg1Trie([]string{"hello", "hello", "hello", "hello", "help", "help", "hero"})
+	p := NewPersonalizer(global)
+
+	p.Record("alice", "hero")
+	p.Record("alice", "hero")
+	p.Record("carol", "helium")
+	for _, w := range []string{"say", "help", "say", "help", "say", "hello", "say"} {
+		p.Record("dave", w)
+	}
+
+	tests := []struct {
+		user string
+		want []string
+	}{
+		{"alice", []string{"hero", "hello", "help"}},
+		{"bob", []string{"hello", "help", "hero"}},     // no history: global ranking
+		{"carol", []string{"helium", "hello", "help"}}, // user-only word
+		{"dave", []string{"help", "hello", "hero"}},    // chose help after "say" most
+	}
+	for _, test := range tests {
+		if got := Words(p.AutocompleteForUser(test.user, "he", 3)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.user, test.want, got)
+		}
+	}
+
+	if got := Words(global.Autocomplete("he", 1)); got[0] != "hello" || global.Contains("helium") {
+		t.Errorf("Expected the global trie to be untouched, got %v", got)
+	}
+
+	p.SetUserWeight(0)
+	if got := Words(p.AutocompleteForUser("alice", "he", 1)); got[0] != "hello" {
+		t.Errorf("Expected a zero user weight to give the global ranking, got %v", got)
+	}
+	p.SetUserWeight(1)
+	p.Forget("alice")
+	if got := Words(p.AutocompleteForUser("alice", "he", 1)); got[0] != "hello" {
+		t.Errorf("Expected Forget to drop the user's history, got %v", got)
+	}
+}
+
+func TestPersonalizerBoost(t *testing.T) {
+	global := buildAlg1Trie([]string{"hello", "hello", "help", "hero"})
+	p := NewPersonalizer(global)
+
+	p.Boost("alice", "hero", 5)
+	p.Boost("bob", "help", 5)
+	p.Boost("bob", "helium", 1)
+
+	if got, want := Words(p.AutocompleteForUser("alice", "he", 3)), []string{"hero", "hello", "help"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Alice: expected %v, got %v", want, got)
+	}
+	if got, want := Words(p.AutocompleteForUser("bob", "he", 4)), []string{"help", "hello", "hero", "helium"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Bob: expected %v, got %v", want, got)
+	}
+
+	// Taking a boost back restores the global ranking.
+	p.Boost("alice", "hero", -5)
+	if got, want := Words(p.AutocompleteForUser("alice", "he", 3)), []string{"hello", "help", "hero"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Alice after unboosting: expected %v, got %v", want, got)
+	}
+
+	// The shared trie is untouched.
+	if global.Frequency("hero") != 1 || global.Contains("helium") {
+		t.Errorf("Personal boosts must not modify the global trie")
+	}
+}
@@ -0,0 +1,355 @@
+package autocomplete
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// -----------------------------------------
+// Phonetic Matching
+// -----------------------------------------
+
+// PhoneticEncoder maps a word to a key shared by words that sound alike,
+// such as Metaphone or Soundex.
+type PhoneticEncoder func(word string) string
+
+// WithPhonetic adds words that sound like the query to its results, so
+// "nite" also suggests "night". Every word is indexed under its encode key
+// and a query adds the words whose keys start with the key of its prefix.
+// Their scores are multiplied by 1-penalty, so a penalty of 0 ranks them
+// like exact completions and 1 only lets them fill up results that have
+// room. The index is built on the first query and rebuilt after words are
+// added or removed. A nil encode turns phonetic matching off.
+func (t *TrieA1) WithPhonetic(encode PhoneticEncoder, penalty float64) *TrieA1 {
+	if encode == nil {
+		return t.withPhoneticKeys(nil, penalty)
+	}
+	return t.withPhoneticKeys(func(word string) []string { return []string{encode(word)} }, penalty)
+}
+
+// WithDoubleMetaphone is WithPhonetic with DoubleMetaphone, indexing every
+// word under both its primary and its alternate key and matching a prefix
+// by either of its own, so "Smith" also suggests "Schmidt".
+func (t *TrieA1) WithDoubleMetaphone(penalty float64) *TrieA1 {
+	return t.withPhoneticKeys(doubleMetaphoneKeys, penalty)
+}
+
+func (t *TrieA1) withPhoneticKeys(keys func(string) []string, penalty float64) *TrieA1 {
+	t.phonetic = nil
+	if keys != nil {
+		t.phonetic = &keyIndex{encode: keys, weight: 1 - min(max(penalty, 0), 1)}
+	}
+	t.cache.clear()
+	return t
+}
+
+// keyIndex holds every word of a trie sorted by the keys derived from it,
+// such as its phonetic codes or its romanization, and multiplies the scores
+// of the words it matches by weight. It is built lazily under mu, since queries may
+// run concurrently. All methods are safe to call on a nil index, which
+// matches nothing.
+type keyIndex struct {
+	encode func(string) []string
+	weight float64
+
+	// encodeQuery gives a prefix its keys if it is not encoded like words.
+	encodeQuery func(string) []string
+
+	mu      sync.Mutex
+	built   bool
+	entries []keyEntry
+}
+
+type keyEntry struct {
+	key  string
+	word string
+}
+
+// reset drops the index so the next query rebuilds it.
+func (x *keyIndex) reset() {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.built, x.entries = false, nil
+}
+
+// emptyCopy returns an unbuilt index with the same settings, or nil for a
+// nil index.
+func (x *keyIndex) emptyCopy() *keyIndex {
+	if x == nil {
+		return nil
+	}
+	return &keyIndex{encode: x.encode, weight: x.weight, encodeQuery: x.encodeQuery}
+}
+
+// resetKeyIndexes drops the phonetic and transliteration indexes after words
+// are added or removed.
+func (t *TrieA1) resetKeyIndexes() {
+	t.phonetic.reset()
+	t.transliteration.reset()
+}
+
+// matches returns the words with a key starting with one of the keys of
+// prefix, building the index from t first if needed.
+func (x *keyIndex) matches(t *TrieA1, prefix string) []string {
+	if x == nil {
+		return nil
+	}
+	encodeQuery := x.encode
+	if x.encodeQuery != nil {
+		encodeQuery = x.encodeQuery
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.built {
+		x.entries = x.entries[:0]
+		for _, word := range t.collectCompletions(t.root, nil) {
+			for _, key := range x.encode(word.Word) {
+				if key != "" {
+					x.entries = append(x.entries, keyEntry{key: key, word: word.Word})
+				}
+			}
+		}
+		sort.Slice(x.entries, func(i, j int) bool {
+			if x.entries[i].key != x.entries[j].key {
+				return x.entries[i].key < x.entries[j].key
+			}
+			return x.entries[i].word < x.entries[j].word
+		})
+		x.built = true
+	}
+
+	var words []string
+	seen := make(map[string]bool)
+	for _, key := range encodeQuery(prefix) {
+		if key == "" {
+			continue
+		}
+		for i := sort.Search(len(x.entries), func(i int) bool { return x.entries[i].key >= key }); i < len(x.entries); i++ {
+			if !strings.HasPrefix(x.entries[i].key, key) {
+				break
+			}
+			// A word listed under two matching keys is returned once.
+			if word := x.entries[i].word; !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// alternateCandidates returns the words that sound like prefix or whose
+// romanization starts with it but that do not start with prefix itself,
+// with their frequencies, and the score weight of each.
+func (t *TrieA1) alternateCandidates(prefix string) ([]Suggestion, map[string]float64) {
+	var candidates []Suggestion
+	var weights map[string]float64
+	for _, x := range []*keyIndex{t.transliteration, t.phonetic} {
+		for _, word := range x.matches(t, prefix) {
+			if _, seen := weights[word]; seen || strings.HasPrefix(word, prefix) {
+				continue
+			}
+			if node := t.searchPrefix(word); node != nil && node.isEnd {
+				if weights == nil {
+					weights = make(map[string]float64)
+				}
+				candidates = append(candidates, Suggestion{Word: word, Frequency: node.frequency})
+				weights[word] = x.weight
+			}
+		}
+	}
+	return candidates, weights
+}
+
+// weightAlternates multiplies the scores of the alternate matches among
+// scored by their weights.
+func weightAlternates(scored []Suggestion, weights map[string]float64) {
+	if len(weights) == 0 {
+		return
+	}
+	for i := range scored {
+		if weight, ok := weights[scored[i].Word]; ok {
+			scored[i].Score *= weight
+		}
+	}
+}
+
+// Metaphone returns the Metaphone key of word, Lawrence Philips' phonetic
+// code for English: letters that sound alike share a code, most vowels and
+// silent letters are dropped, and "0" stands for "th". Letters other than
+// A to Z are ignored. Words such as "night" and "nite", or "phone" and
+// "fone", get the same key.
+func Metaphone(word string) string {
+	w := make([]byte, 0, len(word))
+	for _, r := range strings.ToUpper(word) {
+		if r >= 'A' && r <= 'Z' {
+			w = append(w, byte(r))
+		}
+	}
+	if len(w) == 0 {
+		return ""
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	isVowel := func(c byte) bool { return strings.IndexByte("AEIOU", c) >= 0 }
+	frontVowel := func(c byte) bool { return c == 'E' || c == 'I' || c == 'Y' }
+
+	// Initial letter combinations with a silent or changed first letter.
+	switch string(w[:min(2, len(w))]) {
+	case "AE", "GN", "KN", "PN", "WR":
+		w = w[1:]
+	case "WH":
+		w = append([]byte{'W'}, w[2:]...)
+	}
+	if w[0] == 'X' {
+		w[0] = 'S'
+	}
+
+	var key []byte
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		if c == at(i-1) && c != 'C' {
+			continue
+		}
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				key = append(key, c)
+			}
+		case 'B':
+			if !(at(i-1) == 'M' && i == len(w)-1) {
+				key = append(key, 'B')
+			}
+		case 'C':
+			switch {
+			case at(i+1) == 'I' && at(i+2) == 'A':
+				key = append(key, 'X')
+			case at(i+1) == 'H':
+				if at(i-1) == 'S' {
+					key = append(key, 'K')
+				} else {
+					key = append(key, 'X')
+				}
+			case frontVowel(at(i + 1)):
+				if at(i-1) != 'S' {
+					key = append(key, 'S')
+				}
+			default:
+				key = append(key, 'K')
+			}
+		case 'D':
+			if at(i+1) == 'G' && frontVowel(at(i+2)) {
+				key = append(key, 'J')
+			} else {
+				key = append(key, 'T')
+			}
+		case 'G':
+			switch {
+			case at(i+1) == 'H' && i+2 < len(w) && !isVowel(at(i+2)):
+				// Silent, as in "night".
+			case at(i+1) == 'N' && (i+2 == len(w) || string(w[i+1:]) == "NED"):
+				// Silent, as in "sign" and "signed".
+			case at(i-1) == 'D' && frontVowel(at(i+1)):
+				// Already sounded by the D, as in "edge".
+			case frontVowel(at(i+1)) && at(i-1) != 'G':
+				key = append(key, 'J')
+			default:
+				key = append(key, 'K')
+			}
+		case 'H':
+			if strings.IndexByte("CGPST", at(i-1)) < 0 && isVowel(at(i+1)) {
+				key = append(key, 'H')
+			}
+		case 'K':
+			if at(i-1) != 'C' {
+				key = append(key, 'K')
+			}
+		case 'P':
+			if at(i+1) == 'H' {
+				key = append(key, 'F')
+			} else {
+				key = append(key, 'P')
+			}
+		case 'Q':
+			key = append(key, 'K')
+		case 'S':
+			if at(i+1) == 'H' || at(i+1) == 'I' && (at(i+2) == 'O' || at(i+2) == 'A') {
+				key = append(key, 'X')
+			} else {
+				key = append(key, 'S')
+			}
+		case 'T':
+			switch {
+			case at(i+1) == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				key = append(key, 'X')
+			case at(i+1) == 'H':
+				key = append(key, '0')
+			case at(i+1) == 'C' && at(i+2) == 'H':
+				// Silent, as in "watch".
+			default:
+				key = append(key, 'T')
+			}
+		case 'V':
+			key = append(key, 'F')
+		case 'W', 'Y':
+			if isVowel(at(i + 1)) {
+				key = append(key, c)
+			}
+		case 'X':
+			key = append(key, 'K', 'S')
+		case 'Z':
+			key = append(key, 'S')
+		default: // F, J, L, M, N, R
+			key = append(key, c)
+		}
+	}
+	return string(key)
+}
+
+// Soundex returns the American Soundex code of word: its first letter and
+// three digits for the consonant sounds that follow, such as "R163" for both
+// "Robert" and "Rupert". Letters other than A to Z are ignored.
+func Soundex(word string) string {
+	const codes = "01230120022455012623010202" // A to Z
+	var key []byte
+	var last byte
+	for _, r := range word {
+		r = unicode.ToUpper(r)
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+		code := codes[r-'A']
+		if key == nil {
+			key, last = append(key, byte(r)), code
+			continue
+		}
+		if code != '0' && code != last {
+			key = append(key, code)
+			if len(key) == 4 {
+				break
+			}
+		}
+		// H and W do not separate equal codes; vowels do.
+		if r != 'H' && r != 'W' {
+			last = code
+		}
+	}
+	if key == nil {
+		return ""
+	}
+	for len(key) < 4 {
+		key = append(key, '0')
+	}
+	return string(key)
+}
@@ -0,0 +1,76 @@
+package autocomplete
+
+import "testing"
+
+func TestMetaphone(t *testing.T) {
+	for word, want := range map[string]string{
+		"night":     "NT",
+		"nite":      "NT",
+		"knight":    "NT",
+		"phone":     "FN",
+		"fone":      "FN",
+		"thumb":     "0M",
+		"school":    "SKL",
+		"which":     "WX",
+		"xylophone": "SLFN",
+		"science":   "SNS",
+		"nation":    "NXN",
+		"edge":      "EJ",
+		"":          "",
+	} {
+		if got := Metaphone(word); got != want {
+			t.Errorf("Metaphone(%q): expected %q, got %q", word, want, got)
+		}
+	}
+}
+
+func TestSoundex(t *testing.T) {
+	for word, want := range map[string]string{
+		"Robert":   "R163",
+		"Rupert":   "R163",
+		"Ashcraft": "A261",
+		"Tymczak":  "T522",
+		"Pfister":  "P236",
+		"Honeyman": "H555",
+		"Lee":      "L000",
+		"42":       "",
+	} {
+		if got := Soundex(word); got != want {
+			t.Errorf("Soundex(%q): expected %q, got %q", word, want, got)
+		}
+	}
+}
+
+func TestWithPhonetic(t *testing.T) {
+	corpus := []string{"night", "night", "night", "knight", "note", "note", "nitro", "hello"}
+	trie := buildAlg1Trie(corpus)
+	if got := trie.Autocomplete("nite", 5); len(got) != 0 {
+		t.Errorf("Expected no phonetic matches unless enabled, got %v", got)
+	}
+
+	trie.WithPhonetic(Metaphone, 0)
+	got := Words(trie.Autocomplete("nite", 5))
+	want := []string{"night", "note", "knight", "nitro"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	if got := trie.Autocomplete("nit", 1); len(got) != 1 || got[0].Word != "night" {
+		t.Errorf("Expected the unpenalized 'night' to outrank 'nitro', got %v", got)
+	}
+	trie.WithPhonetic(Metaphone, 0.9)
+	if got := trie.Autocomplete("nit", 1); len(got) != 1 || got[0].Word != "nitro" {
+		t.Errorf("Expected the exact completion 'nitro' first with a high penalty, got %v", got)
+	}
+
+	trie.InsertWithWeight("gnat", 10)
+	if got := trie.Autocomplete("nite", 1); len(got) != 1 || got[0].Word != "gnat" {
+		t.Errorf("Expected a word inserted after the index was built to match, got %v", got)
+	}
+}
@@ -0,0 +1,128 @@
+package autocomplete
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// -----------------------------------------
+// Phrase Autocomplete
+// -----------------------------------------
+
+// defaultPhraseLength is the longest phrase AutocompletePhrase generates when
+// IndexPhrases was never called.
+const defaultPhraseLength = 3
+
+// IndexPhrases records every run of two to maxLen consecutive words of corpus
+// as a phrase, such as "new york" and "new york city", so AutocompletePhrase
+// can suggest them whole. Phrases never span a word rejected by validation.
+// maxLen also bounds the phrases AutocompletePhrase generates; values below 2
+// are treated as 2.
+func (t *TrieA1) IndexPhrases(corpus []string, maxLen int) {
+	maxLen = max(maxLen, 2)
+	t.phraseLength = maxLen
+	if t.phrases == nil {
+		t.phrases = NewTriesA2()
+	}
+
+	var run []string
+	for _, word := range corpus {
+		word, ok := normalizeWord(word, t.strict)
+		if !ok {
+			run = run[:0]
+			continue
+		}
+		run = append(run, word)
+		if len(run) > maxLen {
+			run = run[1:]
+		}
+		// Count each phrase once, at the position of its last word.
+		for length := 2; length <= len(run); length++ {
+			t.phrases.Insert(strings.Join(run[len(run)-length:], " "))
+		}
+	}
+	t.cache.clear()
+}
+
+// splitPhraseInput splits typed text into the words already completed and
+// the word still being typed, which is empty when the text ends in a space.
+func splitPhraseInput(input string) (words []string, partial string) {
+	words = strings.Fields(input)
+	if len(words) == 0 || strings.LastIndexFunc(input, unicode.IsSpace) == len(input)-1 {
+		return words, ""
+	}
+	return words[:len(words)-1], words[len(words)-1]
+}
+
+// AutocompletePhrase completes input, which may span several words, with up
+// to k whole phrases: "new y" suggests "new york" and "new york city".
+// Phrases recorded by IndexPhrases come first, scored by their share of the
+// matching phrases' frequency. Remaining slots are filled by generating
+// phrases from the context model: the word being typed is completed in the
+// context of the words before it, then extended one most likely next word at
+// a time up to the phrase length. Generated phrases score the product of
+// their words' contextual probabilities.
+func (t *TrieA1) AutocompletePhrase(input string, k int) []Suggestion {
+	words, partial := splitPhraseInput(input)
+	typed := strings.Join(append(append([]string(nil), words...), partial), " ")
+	if partial == "" && len(words) > 0 {
+		typed += " "
+	}
+
+	var results []Suggestion
+	if t.phrases != nil {
+		results = t.phrases.AutocompleteProb(typed, k)
+	}
+	seen := make(map[string]bool)
+	for _, s := range results {
+		seen[s.Word] = true
+	}
+
+	for _, s := range t.generatePhrases(words, partial, k) {
+		if len(results) >= k {
+			break
+		}
+		if !seen[s.Word] {
+			results = append(results, s)
+			seen[s.Word] = true
+		}
+	}
+	return results
+}
+
+// generatePhrases completes partial after words and extends each completion
+// greedily with the n-gram model.
+func (t *TrieA1) generatePhrases(words []string, partial string, k int) []Suggestion {
+	var starts []Suggestion
+	if partial != "" {
+		starts = t.AutocompleteContext(partial, words, k)
+	} else if len(words) > 0 {
+		starts = t.topFollowers(words, k)
+	}
+
+	length := t.phraseLength
+	if length == 0 {
+		length = defaultPhraseLength
+	}
+
+	phrases := make([]Suggestion, 0, len(starts))
+	for _, start := range starts {
+		phrase := append(append([]string(nil), words...), start.Word)
+		probability := start.Score
+		for len(phrase) < length {
+			next := t.topFollowers(phrase, 1)
+			if len(next) == 0 {
+				break
+			}
+			phrase = append(phrase, next[0].Word)
+			probability *= next[0].Score
+		}
+		if len(phrase) < 2 {
+			continue
+		}
+		phrases = append(phrases, Suggestion{Word: strings.Join(phrase, " "), Score: probability})
+	}
+	sort.SliceStable(phrases, func(i, j int) bool { return rankBefore(phrases[i], phrases[j]) })
+	return phrases
+}
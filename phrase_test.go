@@ -0,0 +1,90 @@
+package autocomplete
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSplitPhraseInput(t *testing.T) {
+	tests := []struct {
+		input   string
+		words   []string
+		partial string
+	}{
+		{"new y", []string{"new"}, "y"},
+		{"new york ", []string{"new", "york"}, ""},
+		{"  new", []string{}, "new"},
+		{"", nil, ""},
+	}
+	for _, test := range tests {
+		words, partial := splitPhraseInput(test.input)
+		if len(words) != len(test.words) || (len(words) > 0 && !reflect.DeepEqual(words, test.words)) || partial != test.partial {
+			t.Errorf("For %q: expected %v %q, got %v %q", test.input, test.words, test.partial, words, partial)
+		}
+	}
+}
+
+func TestAutocompletePhrase(t *testing.T) {
+	corpus := []string{
+		"new", "york", "city", "is", "big",
+		"new", "york", "city", "never", "sleeps",
+		"new", "york", "state",
+		"new", "yorker",
+	}
+	trie := NewTrieA1()
+	trie.BuildFromCorpus(corpus)
+	trie.BuildNgramTable(corpus, 3)
+	trie.IndexPhrases(corpus, 3)
+
+	got := Words(trie.AutocompletePhrase("new y", 4))
+	if want := []string{"new york", "new york city", "new york state", "new yorker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	got = Words(trie.AutocompletePhrase("york c", 1))
+	if want := []string{"york city"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestAutocompletePhraseGenerates(t *testing.T) {
+	corpus := []string{"the", "quick", "brown", "fox", "the", "quick", "brown", "fox", "the", "lazy", "dog"}
+	trie := NewTrieA1()
+	for _, w := range corpus {
+		trie.Insert(w)
+	}
+	trie.BuildNgramTable(corpus, 3)
+
+	// Nothing is indexed, so phrases are generated from the n-gram model
+	// up to the default length of three words: P(quick | the) = 2/3 and
+	// P(brown | the quick) = 1.
+	got := trie.AutocompletePhrase("the q", 2)
+	if len(got) != 1 || got[0].Word != "the quick brown" || got[0].Score != 2.0/3 {
+		t.Errorf("Expected [the quick brown (0.6667)], got %v", got)
+	}
+
+	got = trie.AutocompletePhrase("the ", 2)
+	if want := []string{"the quick brown", "the lazy dog"}; !reflect.DeepEqual(Words(got), want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestPhraseIndexSurvivesSnapshot(t *testing.T) {
+	corpus := []string{"new", "york", "city", "new", "york"}
+	trie := buildAlg1Trie(corpus)
+	trie.IndexPhrases(corpus, 3)
+
+	var buf bytes.Buffer
+	if err := trie.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := NewTrieA1()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := trie.AutocompletePhrase("new y", 2)
+	if got := loaded.AutocompletePhrase("new y", 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,41 @@
+package autocomplete
+
+// -----------------------------------------
+// Prefix Counts
+// -----------------------------------------
+
+// Every node counts the words ending at or below it, kept up to date by
+// Insert, Delete and the loaders, so the number of words sharing a prefix is
+// read off the prefix's node without visiting its subtree.
+
+// CountPrefix returns how many distinct words start with prefix, in time
+// proportional to the length of prefix. The empty prefix counts every word.
+// Filters, the blocklist and result options are not applied.
+func (t *TrieA1) CountPrefix(prefix string) int {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return 0
+	}
+	return node.words
+}
+
+// CountPrefix returns how many distinct words start with prefix, in time
+// proportional to the length of prefix.
+func (t *TriesA2) CountPrefix(prefix string) int {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return 0
+	}
+	return node.words
+}
+
+// countNewWord adds one to the word count of every node on the path of
+// word, which must already be in the trie.
+func (t *TriesA2) countNewWord(word string) {
+	node := t.root
+	node.words++
+	for _, char := range word {
+		node = node.children[char]
+		node.words++
+	}
+}
@@ -0,0 +1,99 @@
+package autocomplete
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var prefixCountCorpus = []string{"hello", "hello", "hell", "help", "helicopter", "hero", "world"}
+
+// checkPrefixCounts compares CountPrefix against the number of completions
+// a full query finds.
+func checkPrefixCounts(t *testing.T, name string, count func(string) int, complete func(string) []Suggestion) {
+	t.Helper()
+	for _, prefix := range []string{"", "h", "he", "hel", "hell", "hello", "helloo", "w", "x"} {
+		if got, want := count(prefix), len(complete(prefix)); got != want {
+			t.Errorf("%s: expected CountPrefix(%q) to be %d, got %d", name, prefix, want, got)
+		}
+	}
+}
+
+func TestCountPrefix(t *testing.T) {
+	a1, a2 := buildAlg1Trie(prefixCountCorpus), buildAlg2Trie(prefixCountCorpus)
+
+	if got := a1.CountPrefix("hel"); got != 4 {
+		t.Errorf("Expected 4 words under 'hel', got %d", got)
+	}
+	if got := a2.CountPrefix(""); got != a2.Len() {
+		t.Errorf("Expected the empty prefix to count all %d words, got %d", a2.Len(), got)
+	}
+	all := func(a Autocompleter) func(string) []Suggestion {
+		return func(prefix string) []Suggestion { return a.Autocomplete(prefix, 100) }
+	}
+	checkPrefixCounts(t, "A1", a1.CountPrefix, all(a1))
+	checkPrefixCounts(t, "A2", a2.CountPrefix, all(a2))
+
+	for _, word := range []string{"hello", "hell", "help", "missing"} {
+		a1.Delete(word)
+		a2.Delete(word)
+	}
+	if got := a1.CountPrefix("hel"); got != 2 {
+		t.Errorf("Expected 2 words under 'hel' after the deletes, got %d", got)
+	}
+	checkPrefixCounts(t, "A1 after deletes", a1.CountPrefix, all(a1))
+	checkPrefixCounts(t, "A2 after deletes", a2.CountPrefix, all(a2))
+	if err := a2.Validate(); err != nil {
+		t.Errorf("Expected valid word counts after the deletes, got %v", err)
+	}
+}
+
+func TestCountPrefixSurvivesRebuilds(t *testing.T) {
+	parallel := NewTrieA1()
+	parallel.BuildFromCorpusParallel(prefixCountCorpus, 3)
+	compact := buildAlg1Trie(prefixCountCorpus).WithCompactNodes(true)
+
+	var snapshot bytes.Buffer
+	if err := buildAlg1Trie(prefixCountCorpus).Save(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewTrieA1()
+	if err := loaded.Load(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(buildAlg1Trie(prefixCountCorpus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshalled := NewTrieA1()
+	if err := json.Unmarshal(data, unmarshalled); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, trie := range map[string]*TrieA1{
+		"parallel":     parallel,
+		"compact":      compact,
+		"loaded":       loaded,
+		"unmarshalled": unmarshalled,
+		"clone":        compact.Clone(),
+	} {
+		if got := trie.CountPrefix("he"); got != 5 {
+			t.Errorf("%s: expected 5 words under 'he', got %d", name, got)
+		}
+	}
+
+	snapshot.Reset()
+	if err := buildAlg2Trie(prefixCountCorpus).Save(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	a2 := NewTriesA2()
+	if err := a2.Load(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if got := a2.CountPrefix("he"); got != 5 {
+		t.Errorf("Expected 5 words under 'he' in a loaded A2 trie, got %d", got)
+	}
+	if err := a2.Validate(); err != nil {
+		t.Errorf("Expected a loaded A2 trie to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package autocomplete
+
+// -----------------------------------------
+// Normalized Probabilities for Algorithm_2
+// -----------------------------------------
+
+// AutocompleteProb returns up to k completions of prefix like
+// AutocompleteTopK, but as Suggestions whose probability is the word's share
+// of the total frequency of all completions of prefix. This gives
+// Algorithm_2 the same output shape as Algorithm_1.
+//
+// With a recency window enabled, windowed counts replace frequencies.
+// Otherwise, with decay enabled, probabilities are shares of the decayed
+// weights while Frequency stays the all-time count.
+func (t *TriesA2) AutocompleteProb(prefix string, k int) []Suggestion {
+	return t.autocompleteProb(prefix, k, query{})
+}
+
+func (t *TriesA2) autocompleteProb(prefix string, k int, q query) []Suggestion {
+	if t.maxCorrections > 0 && t.searchPrefix(prefix) == nil {
+		return t.correct(prefix, q, k)
+	}
+	scored := t.scoredCompletions(prefix, q)
+	if scored == nil {
+		return nil
+	}
+	return topK(scored, t.options.limit(k))
+}
+
+// scoredCompletions returns every completion of prefix allowed by the result
+// options and the query with its probability, unordered, or nil if the
+// query was cancelled.
+func (t *TriesA2) scoredCompletions(prefix string, q query) []Suggestion {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return nil
+	}
+
+	var entries []Suggestion
+	if !collectEntriesA2Cancel(node, prefix, &entries, t.options.depth(), q.cancel) {
+		return nil
+	}
+	if t.graphemes {
+		entries = keepWholeGraphemes(prefix, entries)
+	}
+	return t.scoreEntries(prefix, q, entries)
+}
+
+// scoreEntries keeps the collected entries allowed by the result options and
+// the query and gives each its probability, unordered.
+func (t *TriesA2) scoreEntries(prefix string, q query, entries []Suggestion) []Suggestion {
+	if t.window != nil {
+		for i := range entries {
+			entries[i].Frequency = t.WindowedFrequency(entries[i].Word)
+		}
+	}
+	entries = q.restrict(t.filter.apply(t.options.keepFrequent(entries)))
+
+	weights := make([]float64, len(entries))
+	for i := range entries {
+		switch {
+		case t.window != nil:
+			weights[i] = float64(entries[i].Frequency)
+		case t.halfLife > 0:
+			weights[i] = t.DecayedFrequency(entries[i].Word)
+		default:
+			weights[i] = float64(entries[i].Frequency)
+		}
+	}
+	total := exactSum(weights)
+
+	suggestions := make([]Suggestion, len(entries))
+	for i, e := range entries {
+		probability := 0.0
+		if total > 0 {
+			probability = weights[i] / total
+		}
+		suggestions[i] = Suggestion{Word: e.Word, Score: probability, Frequency: e.Frequency}
+	}
+	t.clicks.blend(prefix, suggestions)
+	return t.options.keepScoring(rescore(t.scorer, prefix, nil, suggestions))
+}
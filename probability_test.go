@@ -0,0 +1,38 @@
+package autocomplete
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAutocompleteProb(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hell", "hell", "helicopter", "hero", "world"}
+	trie := buildAlg2Trie(corpus)
+
+	suggestions := trie.AutocompleteProb("he", 10)
+	if len(suggestions) != 4 {
+		t.Fatalf("Expected 4 suggestions, got %v", suggestions)
+	}
+
+	sum := 0.0
+	for _, s := range suggestions {
+		sum += s.Score
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected probabilities to sum to 1, got %f", sum)
+	}
+
+	frequencyOrder := trie.AutocompleteTopK("he", 2)
+	for i, word := range frequencyOrder {
+		if suggestions[i].Word != word {
+			t.Errorf("Position %d: expected '%s' as in frequency order, got '%s'", i, word, suggestions[i].Word)
+		}
+	}
+	if suggestions[0].Score != 3.0/7.0 {
+		t.Errorf("Expected P(hello)=3/7, got %f", suggestions[0].Score)
+	}
+
+	if got := trie.AutocompleteProb("xyz", 3); len(got) != 0 {
+		t.Errorf("Expected no suggestions for unknown prefix, got %v", got)
+	}
+}
@@ -0,0 +1,215 @@
+package autocomplete
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------
+// Query Log Learning
+// -----------------------------------------
+
+// QueryEvent is one completion request and what the user did with it.
+type QueryEvent struct {
+	Time    time.Time `json:"time"`
+	Context []string  `json:"context,omitempty"`
+	Prefix  string    `json:"prefix"`
+	// Selected is the suggestion the user accepted, or "" if they picked
+	// none.
+	Selected string `json:"selected,omitempty"`
+}
+
+// QueryLog records query events, keeps the ones within a sliding time
+// window for inspection and folds accepted selections back into a trie:
+// each selection adds one to the selected word's frequency, is recorded
+// for click-through re-ranking if the trie has it enabled and, after a
+// context, counts the word as following the last context word. Events can
+// also be appended to a sink as JSON lines and replayed from it later, so
+// a restarted server can learn again from what it saw. A QueryLog is safe
+// for concurrent use.
+type QueryLog struct {
+	target *Concurrent
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	sink    *json.Encoder
+	recent  []QueryEvent
+	pending []QueryEvent
+	folded  int
+}
+
+// NewQueryLog returns a log that folds selections into target, which must
+// wrap a *TrieA1 or *TriesA2, and keeps the events of the last window for
+// Recent and SelectionRate. A non-positive window keeps none.
+func NewQueryLog(target *Concurrent, window time.Duration) *QueryLog {
+	return &QueryLog{target: target, window: window, now: time.Now}
+}
+
+// WithSink makes Record append every event to w as a JSON line, in the
+// format Replay reads. A nil w stops writing.
+func (l *QueryLog) WithSink(w io.Writer) *QueryLog {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sink = nil
+	if w != nil {
+		l.sink = json.NewEncoder(w)
+	}
+	return l
+}
+
+// Record logs event, stamping it with the current time if it has none. The
+// selection, if any, is folded into the trie by the next Fold. The error is
+// the sink's; the event is recorded either way.
+func (l *QueryLog) Record(event QueryEvent) error {
+	if event.Time.IsZero() {
+		event.Time = l.now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.add(event)
+	if l.sink != nil {
+		return l.sink.Encode(event)
+	}
+	return nil
+}
+
+// add keeps event in the window and queues its selection. l.mu is held.
+func (l *QueryLog) add(event QueryEvent) {
+	if event.Selected != "" {
+		l.pending = append(l.pending, event)
+	}
+	if l.window <= 0 {
+		return
+	}
+	l.recent = append(l.recent, event)
+	cutoff := l.now().Add(-l.window)
+	drop := 0
+	for drop < len(l.recent) && l.recent[drop].Time.Before(cutoff) {
+		drop++
+	}
+	l.recent = append(l.recent[:0], l.recent[drop:]...)
+}
+
+// Replay records the JSON-line events read from r, as written by a sink,
+// without writing them to the sink again, and returns how many it read.
+// Their selections are folded by the next Fold, and those still within
+// the window show up in Recent.
+func (l *QueryLog) Replay(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxSnapshotString)
+	n := 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event QueryEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return n, fmt.Errorf("autocomplete: query log line %d: %w", line, err)
+		}
+		l.mu.Lock()
+		l.add(event)
+		l.mu.Unlock()
+		n++
+	}
+	return n, scanner.Err()
+}
+
+// Fold applies the selections recorded since the last Fold to the trie
+// under its write lock and returns how many it applied. Both algorithms
+// promote the selected word only if they already hold it, so a stray
+// selection cannot add a word.
+func (l *QueryLog) Fold() int {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.folded += len(pending)
+	l.mu.Unlock()
+	if len(pending) == 0 {
+		return 0
+	}
+
+	l.target.Update(func(a Autocompleter) {
+		for _, event := range pending {
+			switch t := a.(type) {
+			case *TrieA1:
+				t.Promote(event.Selected)
+				t.RecordSelection(event.Prefix, event.Selected)
+				if len(event.Context) > 0 {
+					t.RecordBigram(event.Context[len(event.Context)-1], event.Selected)
+				}
+			case *TriesA2:
+				t.Promote(event.Selected)
+				t.RecordSelection(event.Prefix, event.Selected)
+			default:
+				a.Insert(event.Selected)
+			}
+		}
+	})
+	return len(pending)
+}
+
+// FoldEvery calls Fold every interval until the returned stop function is
+// called.
+func (l *QueryLog) FoldEvery(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				l.Fold()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+// Recent returns the events recorded within the window, oldest first.
+func (l *QueryLog) Recent() []QueryEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cutoff := l.now().Add(-l.window)
+	var events []QueryEvent
+	for _, event := range l.recent {
+		if !event.Time.Before(cutoff) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// SelectionRate returns the share of the events within the window in which
+// the user accepted a suggestion, or 0 if there are none.
+func (l *QueryLog) SelectionRate() float64 {
+	events := l.Recent()
+	if len(events) == 0 {
+		return 0
+	}
+	selected := 0
+	for _, event := range events {
+		if event.Selected != "" {
+			selected++
+		}
+	}
+	return float64(selected) / float64(len(events))
+}
+
+// Folded returns how many selections Fold has applied so far.
+func (l *QueryLog) Folded() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.folded
+}
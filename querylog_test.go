@@ -0,0 +1,115 @@
+package autocomplete
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryLogFold(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "help", "say"})
+	log := NewQueryLog(NewConcurrent(trie), time.Hour)
+
+	for i := 0; i < 2; i++ {
+		log.Record(QueryEvent{Context: []string{"say"}, Prefix: "he", Selected: "help"})
+	}
+	log.Record(QueryEvent{Prefix: "he"})
+
+	// Nothing is learned until the log is folded.
+	if got := Words(trie.Autocomplete("he", 1)); !reflect.DeepEqual(got, []string{"hello"}) {
+		t.Errorf("Expected [hello] before folding, got %v", got)
+	}
+	if n := log.Fold(); n != 2 {
+		t.Errorf("Expected 2 selections folded, got %d", n)
+	}
+	if got := Words(trie.Autocomplete("he", 2)); !reflect.DeepEqual(got, []string{"help", "hello"}) {
+		t.Errorf("Expected [help hello] after folding, got %v", got)
+	}
+	if got := trie.bigramTable["say"]["help"]; got != 2 {
+		t.Errorf("Expected say -> help counted twice, got %d", got)
+	}
+	if n := log.Fold(); n != 0 || log.Folded() != 2 {
+		t.Errorf("Expected a second fold to apply nothing, got %d of %d", n, log.Folded())
+	}
+}
+
+func TestQueryLogWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	log := NewQueryLog(NewConcurrent(NewTrieA1()), time.Minute)
+	log.now = func() time.Time { return now }
+
+	log.Record(QueryEvent{Prefix: "a", Selected: "apple"})
+	now = now.Add(45 * time.Second)
+	log.Record(QueryEvent{Prefix: "b"})
+	if rate := log.SelectionRate(); rate != 0.5 {
+		t.Errorf("Expected a selection rate of 0.5, got %v", rate)
+	}
+
+	now = now.Add(30 * time.Second)
+	if got := log.Recent(); len(got) != 1 || got[0].Prefix != "b" {
+		t.Errorf("Expected only the event within the last minute, got %+v", got)
+	}
+	if rate := log.SelectionRate(); rate != 0 {
+		t.Errorf("Expected a selection rate of 0, got %v", rate)
+	}
+}
+
+func TestQueryLogReplay(t *testing.T) {
+	var sink bytes.Buffer
+	log := NewQueryLog(NewConcurrent(NewTrieA1()), time.Hour).WithSink(&sink)
+	log.Record(QueryEvent{Context: []string{"good"}, Prefix: "mo", Selected: "morning"})
+	log.Record(QueryEvent{Prefix: "ni"})
+
+	trie := buildAlg1Trie([]string{"morning", "night"})
+	replayed := NewQueryLog(NewConcurrent(trie), time.Hour)
+	n, err := replayed.Replay(strings.NewReader(sink.String()))
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 events replayed, got %d (%v)", n, err)
+	}
+	if replayed.Fold() != 1 || trie.searchPrefix("morning").frequency != 2 || trie.bigramTable["good"]["morning"] != 1 {
+		t.Errorf("Expected the replayed selection to be learned, got frequency %d", trie.searchPrefix("morning").frequency)
+	}
+
+	if _, err := replayed.Replay(strings.NewReader("{\"prefix\": \"a\"}\nnot json\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error naming line 2, got %v", err)
+	}
+}
+
+func TestQueryLogFoldsIntoA2(t *testing.T) {
+	trie := buildAlg2Trie([]string{"cat", "cat", "car"})
+	log := NewQueryLog(NewConcurrent(trie), 0)
+	log.Record(QueryEvent{Prefix: "ca", Selected: "car"})
+	log.Record(QueryEvent{Prefix: "ca", Selected: "car"})
+	log.Fold()
+	if got := Words(trie.Autocomplete("ca", 1)); !reflect.DeepEqual(got, []string{"car"}) {
+		t.Errorf("Expected [car] after folding, got %v", got)
+	}
+	if len(log.Recent()) != 0 {
+		t.Errorf("Expected no window to keep no events")
+	}
+
+	// Like Algorithm_1, Algorithm_2 only promotes words it already has.
+	log.Record(QueryEvent{Prefix: "ca", Selected: "cab"})
+	log.Fold()
+	if trie.Contains("cab") || trie.Len() != 2 {
+		t.Errorf("Expected an unknown selection not to be added, got %d words", trie.Len())
+	}
+}
+
+func TestQueryLogFoldEvery(t *testing.T) {
+	log := NewQueryLog(NewConcurrent(buildAlg1Trie([]string{"go"})), time.Hour)
+	stop := log.FoldEvery(time.Millisecond)
+	defer stop()
+
+	log.Record(QueryEvent{Prefix: "g", Selected: "go"})
+	deadline := time.Now().Add(time.Second)
+	for log.Folded() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if log.Folded() != 1 {
+		t.Errorf("Expected the selection to be folded in the background")
+	}
+	stop()
+}
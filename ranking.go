@@ -0,0 +1,222 @@
+package autocomplete
+
+import (
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Ranking Adjustments
+// -----------------------------------------
+
+// SetMatchRatioWeight sets how much the prefix match ratio contributes to the
+// ranking score. The final score is (1-w)*probability + w*matchRatio, so a
+// weight of 0 ranks by probability alone and 1 by match ratio alone. Values
+// outside [0, 1] are clamped.
+func (t *TrieA1) SetMatchRatioWeight(w float64) {
+	if w < 0 {
+		w = 0
+	}
+	if w > 1 {
+		w = 1
+	}
+	t.matchRatioWeight = w
+	t.cache.clear()
+}
+
+// WithContextWeight blends contextual probability with plain frequency when
+// a context is known: the score becomes alpha*P(word | context) +
+// (1-alpha)*P(word), where P(word) is the word's share of the completions'
+// frequency. The default of 1 ranks by context alone, so a word seen once
+// after the context beats a hugely common one that never was; lowering alpha
+// lets frequency push back. Values outside [0, 1] are clamped. It returns t
+// so it can be chained after NewTrieA1.
+func (t *TrieA1) WithContextWeight(alpha float64) *TrieA1 {
+	if alpha < 0 {
+		alpha = 0
+	}
+	if alpha > 1 {
+		alpha = 1
+	}
+	t.frequencyWeight = 1 - alpha
+	t.cache.clear()
+	return t
+}
+
+// blendContext mixes contextual and unigram probabilities by the context
+// weight.
+func (t *TrieA1) blendContext(contextual, unigram []float64) []float64 {
+	if t.frequencyWeight == 0 {
+		return contextual
+	}
+	w := t.frequencyWeight
+	for i := range contextual {
+		contextual[i] = (1-w)*contextual[i] + w*unigram[i]
+	}
+	return contextual
+}
+
+// matchRatio returns the fraction of word covered by prefix, measured in runes.
+// "he" covers 2/3 of "hey" but only 2/10 of "helicopter".
+func matchRatio(prefix, word string) float64 {
+	wordLen := utf8.RuneCountInString(word)
+	if wordLen == 0 {
+		return 0
+	}
+	return float64(utf8.RuneCountInString(prefix)) / float64(wordLen)
+}
+
+func (t *TrieA1) blendMatchRatio(prefix, word string, probability float64) float64 {
+	if t.matchRatioWeight == 0 {
+		return probability
+	}
+	w := t.matchRatioWeight
+	return (1-w)*probability + w*matchRatio(prefix, word)
+}
+
+// clampK bounds a requested result count to [0, n]. Negative values yield no
+// results and values larger than the candidate set return every candidate.
+func clampK(k, n int) int {
+	if k < 0 {
+		return 0
+	}
+	if k > n {
+		return n
+	}
+	return k
+}
+
+// FrequencyTransform maps a raw word count to the weight used for ranking.
+type FrequencyTransform func(frequency int) float64
+
+// RawFrequency ranks by the unmodified count.
+func RawFrequency(frequency int) float64 {
+	return float64(frequency)
+}
+
+// LogFrequency dampens very frequent words with log(1 + count).
+func LogFrequency(frequency int) float64 {
+	return math.Log1p(float64(frequency))
+}
+
+// SqrtFrequency dampens very frequent words less aggressively than LogFrequency.
+func SqrtFrequency(frequency int) float64 {
+	return math.Sqrt(float64(frequency))
+}
+
+// CappedFrequency returns a transform that counts at most limit occurrences
+// of any word.
+func CappedFrequency(limit int) FrequencyTransform {
+	return func(frequency int) float64 {
+		if frequency > limit {
+			frequency = limit
+		}
+		return float64(frequency)
+	}
+}
+
+// SetFrequencyTransform selects how word counts are weighted when ranking
+// without context. Monotonic transforms keep the frequency order on their
+// own; they matter once frequency is blended with other terms such as the
+// match ratio, where they stop a single hot word from swamping the
+// probability mass. Passing nil restores RawFrequency.
+func (t *TrieA1) SetFrequencyTransform(transform FrequencyTransform) {
+	t.frequencyTransform = transform
+	t.cache.clear()
+}
+
+// rankBefore orders suggestions by descending score, breaking ties by
+// descending frequency and then alphabetically so equal scores always come
+// back in the same order. Unscored suggestions are thus ranked by frequency.
+func rankBefore(a, b Suggestion) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Frequency != b.Frequency {
+		return a.Frequency > b.Frequency
+	}
+	return a.Word < b.Word
+}
+
+// exactSum returns the sum of values correctly rounded, as if they were
+// added with infinite precision. Unlike a plain loop its result does not
+// depend on the order of values, so totals over candidates collected in map
+// order, and the scores divided by them, are identical on every run. It is
+// Shewchuk's algorithm as used by Python's math.fsum.
+func exactSum(values []float64) float64 {
+	// partials are non-overlapping and increasing in magnitude; their exact
+	// sum is the exact sum of the values seen so far.
+	var partials []float64
+	for _, x := range values {
+		i := 0
+		for _, y := range partials {
+			if math.Abs(x) < math.Abs(y) {
+				x, y = y, x
+			}
+			hi := x + y
+			lo := y - (hi - x)
+			if lo != 0 {
+				partials[i] = lo
+				i++
+			}
+			x = hi
+		}
+		partials = append(partials[:i], x)
+	}
+
+	n := len(partials)
+	if n == 0 {
+		return 0
+	}
+	n--
+	hi, lo := partials[n], 0.0
+	for n > 0 {
+		x := hi
+		n--
+		y := partials[n]
+		hi = x + y
+		lo = y - (hi - x)
+		if lo != 0 {
+			break
+		}
+	}
+	// Round half to even when the rest lies exactly halfway.
+	if n > 0 && (lo < 0 && partials[n-1] < 0 || lo > 0 && partials[n-1] > 0) {
+		y := lo * 2
+		x := hi + y
+		if x-hi == y {
+			hi = x
+		}
+	}
+	return hi
+}
+
+// SetPrecision rounds the probabilities returned by Autocomplete to the given
+// number of decimal places, which keeps serialized output free of long
+// floating point tails. Results are ranked on the exact scores before
+// rounding, so rounding never changes their order. Zero or a negative value
+// turns rounding off.
+func (t *TrieA1) SetPrecision(digits int) {
+	if digits < 0 {
+		digits = 0
+	}
+	t.precision = digits
+	t.cache.clear()
+}
+
+func (t *TrieA1) roundProbabilities(suggestions []Suggestion) {
+	if t.precision == 0 {
+		return
+	}
+	scale := math.Pow(10, float64(t.precision))
+	for i := range suggestions {
+		suggestions[i].Score = math.Round(suggestions[i].Score*scale) / scale
+	}
+}
+
+// String formats a suggestion as its word followed by its probability to
+// four significant digits, e.g. "hello (0.3333)".
+func (s Suggestion) String() string {
+	return s.Word + " (" + strconv.FormatFloat(s.Score, 'g', 4, 64) + ")"
+}
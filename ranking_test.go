@@ -0,0 +1,213 @@
+package autocomplete
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Words that the prefix covers more of should outrank longer words of equal frequency
+func TestMatchRatioBoostsShortWords(t *testing.T) {
+	corpus := []string{"helicopter", "hey", "helium"}
+	prefix := "he"
+
+	trie := buildAlg1Trie(corpus)
+
+	// Without the match ratio all three words tie on frequency.
+	trie.SetMatchRatioWeight(0.5)
+	suggestions := trie.Autocomplete(prefix, 3)
+
+	if len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %d", len(suggestions))
+	}
+	want := []string{"hey", "helium", "helicopter"}
+	for i, w := range want {
+		if suggestions[i].Word != w {
+			t.Errorf("Position %d: expected '%s', got '%s'", i, w, suggestions[i].Word)
+		}
+	}
+}
+
+// A zero weight must leave frequency ranking untouched
+func TestMatchRatioZeroWeight(t *testing.T) {
+	corpus := []string{"helicopter", "helicopter", "hey"}
+	trie := buildAlg1Trie(corpus)
+
+	suggestions := trie.Autocomplete("he", 2)
+	if len(suggestions) == 0 || suggestions[0].Word != "helicopter" {
+		t.Errorf("Expected 'helicopter' first with zero match ratio weight, got %v", suggestions)
+	}
+}
+
+// Out-of-range k values must never panic and must truncate consistently
+func TestKBounds(t *testing.T) {
+	corpus := []string{"hello", "hell", "helicopter", "hero", "world"}
+	prefix := "he"
+
+	trieA1 := buildAlg1Trie(corpus)
+	trieA2 := buildAlg2Trie(corpus)
+
+	cases := []struct {
+		k    int
+		want int
+	}{
+		{-5, 0},
+		{0, 0},
+		{2, 2},
+		{math.MaxInt, 4},
+	}
+	for _, c := range cases {
+		if got := len(trieA1.Autocomplete(prefix, c.k)); got != c.want {
+			t.Errorf("Algorithm_1 with k=%d: expected %d suggestions, got %d", c.k, c.want, got)
+		}
+		if got := len(trieA2.AutocompleteTopK(prefix, c.k)); got != c.want {
+			t.Errorf("Algorithm_2 with k=%d: expected %d suggestions, got %d", c.k, c.want, got)
+		}
+	}
+}
+
+// Dampening a dominant word's frequency lets moderately frequent words through
+func TestFrequencyTransformDampensDominantWord(t *testing.T) {
+	var corpus []string
+	for i := 0; i < 1000; i++ {
+		corpus = append(corpus, "helicopter")
+	}
+	for i := 0; i < 5; i++ {
+		corpus = append(corpus, "hey", "hex", "hem")
+	}
+	prefix := "he"
+
+	trie := buildAlg1Trie(corpus)
+	trie.SetMatchRatioWeight(0.5)
+
+	suggestions := trie.Autocomplete(prefix, 1)
+	if len(suggestions) != 1 || suggestions[0].Word != "helicopter" {
+		t.Fatalf("Expected 'helicopter' to dominate with raw frequency, got %v", suggestions)
+	}
+
+	transforms := map[string]FrequencyTransform{
+		"log":    LogFrequency,
+		"capped": CappedFrequency(10),
+	}
+	for name, transform := range transforms {
+		trie.SetFrequencyTransform(transform)
+		for _, s := range trie.Autocomplete(prefix, 3) {
+			if s.Word == "helicopter" {
+				t.Errorf("%s transform: expected 'helicopter' to drop out of the top 3", name)
+			}
+		}
+	}
+}
+
+// Rounded probabilities keep the order of the exact scores
+func TestPrecisionRounding(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "help", "help", "hero", "hero"}
+	trie := buildAlg1Trie(corpus)
+	exact := trie.Autocomplete("he", 3)
+
+	trie.SetPrecision(2)
+	rounded := trie.Autocomplete("he", 3)
+
+	want := []float64{0.43, 0.29, 0.29}
+	for i, s := range rounded {
+		if s.Score != want[i] {
+			t.Errorf("Position %d: expected probability %v, got %v", i, want[i], s.Score)
+		}
+		if s.Word != exact[i].Word {
+			t.Errorf("Position %d: rounding reordered results, got '%s' instead of '%s'", i, s.Word, exact[i].Word)
+		}
+	}
+
+	if got := rounded[0].String(); got != "hello (0.43)" {
+		t.Errorf("Unexpected String() output: %s", got)
+	}
+	if got := exact[0].String(); got != "hello (0.4286)" {
+		t.Errorf("Unexpected String() output: %s", got)
+	}
+}
+
+func TestContextWeight(t *testing.T) {
+	corpus := []string{"the", "cat"}
+	for i := 0; i < 20; i++ {
+		corpus = append(corpus, "car")
+	}
+	trie := buildAlg1Trie(corpus)
+
+	tests := []struct {
+		alpha float64
+		want  string
+	}{
+		{1, "cat"},   // context alone: cat followed "the", car never did
+		{0.3, "car"}, // 0.3*1 + 0.7/21 for cat against 0.7*20/21 for car
+		{0, "car"},
+		{-2, "car"}, // clamped to 0
+	}
+	for _, test := range tests {
+		trie.WithContextWeight(test.alpha)
+		got := trie.AutocompleteWithContext("the", "ca", 2)
+		if got[0].Word != test.want {
+			t.Errorf("For alpha %v: expected %q first, got %v", test.alpha, test.want, got)
+		}
+	}
+}
+
+func TestExactSumIgnoresOrder(t *testing.T) {
+	values := []float64{1e16, 1, -1e16, 0.1, 0.2, 0.3, 1e-17, math.Log(3), math.Sqrt(2)}
+	want := exactSum(values)
+	for i := 0; i < len(values); i++ {
+		rotated := append(append([]float64(nil), values[i:]...), values[:i]...)
+		for l, r := 0, len(rotated)-1; l < r && i%2 == 1; l, r = l+1, r-1 {
+			rotated[l], rotated[r] = rotated[r], rotated[l]
+		}
+		if got := exactSum(rotated); got != want {
+			t.Errorf("Expected %v for every order, got %v", want, got)
+		}
+	}
+	if got := exactSum([]float64{0.1, 0.2, 0.3}); got != 0.6 {
+		t.Errorf("Expected the correctly rounded 0.6, got %v", got)
+	}
+	if exactSum(nil) != 0 {
+		t.Errorf("Expected 0 for no values")
+	}
+}
+
+func TestResultsAreIdenticalAcrossBuilds(t *testing.T) {
+	corpus := syntheticCorpus(3000)
+	build := func() []Suggestion {
+		trie := NewTrieA1()
+		trie.BuildFromCorpus(corpus)
+		trie.SetFrequencyTransform(LogFrequency)
+		return trie.Autocomplete("", 50)
+	}
+	want := build()
+	// The documented order: score, then frequency, then word.
+	for j := 1; j < len(want); j++ {
+		a, b := want[j-1], want[j]
+		if a.Score < b.Score || a.Score == b.Score && (a.Frequency < b.Frequency || a.Frequency == b.Frequency && a.Word > b.Word) {
+			t.Fatalf("Suggestion %d (%v, frequency %d) is out of order after %v, frequency %d", j, b, b.Frequency, a, a.Frequency)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		got := build()
+		for j := range want {
+			if got[j] != want[j] {
+				t.Fatalf("Build %d: suggestion %d is %v, expected %v", i, j, got[j], want[j])
+			}
+		}
+	}
+}
+
+func TestRankBeforeBreaksTiesByFrequency(t *testing.T) {
+	ranked := []Suggestion{
+		{Word: "apple", Frequency: 1},
+		{Word: "banana", Frequency: 3},
+		{Word: "cherry", Frequency: 3},
+		{Word: "date", Score: 0.5},
+	}
+	want := []string{"date", "banana", "cherry", "apple"}
+	sort.Slice(ranked, func(i, j int) bool { return rankBefore(ranked[i], ranked[j]) })
+	if got := Words(ranked); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,40 @@
+package autocomplete
+
+import (
+	"regexp"
+	"sort"
+)
+
+// -----------------------------------------
+// Regular Expression Filtering
+// -----------------------------------------
+
+// AutocompleteRegex returns up to k completions of prefix that match pattern,
+// ranked by frequency and then alphabetically. The pattern is matched against
+// the whole word, so anchor it ("^he.o$") when a full match is wanted.
+// Compiling the pattern is left to the caller so it can be reused across
+// queries.
+func (t *TriesA2) AutocompleteRegex(prefix string, pattern *regexp.Regexp, k int) []string {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return []string{}
+	}
+
+	var entries []Suggestion
+	collectEntriesA2(node, prefix, &entries)
+	entries = t.filter.apply(entries)
+
+	var matched []Suggestion
+	for _, e := range entries {
+		if pattern.MatchString(e.Word) {
+			matched = append(matched, e)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return rankBefore(matched[i], matched[j]) })
+
+	results := make([]string, clampK(k, len(matched)))
+	for i := range results {
+		results[i] = matched[i].Word
+	}
+	return results
+}
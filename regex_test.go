@@ -0,0 +1,31 @@
+package autocomplete
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestAutocompleteRegex(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "hello", "helicopter", "hero", "halo", "world"})
+
+	pattern := regexp.MustCompile(`^he.*o$`)
+	got := trie.AutocompleteRegex("he", pattern, 5)
+	if want := []string{"hello", "hero"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Words outside the prefix are never considered, even if they match.
+	for _, w := range trie.AutocompleteRegex("he", regexp.MustCompile(`o$`), 5) {
+		if w == "halo" {
+			t.Errorf("'halo' does not share the prefix and must not be returned")
+		}
+	}
+
+	if got := trie.AutocompleteRegex("he", regexp.MustCompile(`^zzz`), 5); len(got) != 0 {
+		t.Errorf("Expected no results for a pattern that matches nothing, got %v", got)
+	}
+	if got := trie.AutocompleteRegex("he", pattern, 1); !reflect.DeepEqual(got, []string{"hello"}) {
+		t.Errorf("Expected only the most frequent match with k=1, got %v", got)
+	}
+}
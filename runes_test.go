@@ -0,0 +1,45 @@
+package autocomplete
+
+import "testing"
+
+func TestAutocompleteRunesCJK(t *testing.T) {
+	corpus := []string{"東京", "東京都", "東京都", "東京タワー", "東北", "大阪"}
+	trie := buildAlg1Trie(corpus)
+
+	prefix := []rune("東京")
+	suggestions := trie.AutocompleteRunes(prefix, 2)
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected k=2 suggestions, got %v", suggestions)
+	}
+	if suggestions[0].Word != "東京都" {
+		t.Errorf("Expected '東京都' first, got '%s'", suggestions[0].Word)
+	}
+
+	// The rune and string APIs must agree.
+	fromString := trie.Autocomplete("東京", 3)
+	fromRunes := trie.AutocompleteRunes(prefix, 3)
+	if len(fromString) != 3 || len(fromRunes) != 3 || fromString[0] != fromRunes[0] {
+		t.Errorf("String and rune APIs disagree: %v vs %v", fromString, fromRunes)
+	}
+	if string(prefix) != "東京" {
+		t.Errorf("AutocompleteRunes modified the caller's prefix")
+	}
+}
+
+func TestRuneLengthSemanticsCJK(t *testing.T) {
+	trie := buildAlg1Trie([]string{"東京", "東京タワー"})
+
+	// Dropped characters are counted in runes, not bytes.
+	suggestions, dropped := trie.AutocompleteBackoff("東京駅前", 5)
+	if dropped != 2 {
+		t.Errorf("Expected 2 dropped runes, got %d", dropped)
+	}
+	if len(suggestions) != 2 {
+		t.Errorf("Expected completions of '東京', got %v", suggestions)
+	}
+
+	// Match ratio uses rune length: "東京" covers all of "東京" and 2/5 of "東京タワー".
+	if got := matchRatio("東京", "東京タワー"); got != 0.4 {
+		t.Errorf("Expected match ratio 0.4, got %f", got)
+	}
+}
@@ -0,0 +1,54 @@
+package autocomplete
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// -----------------------------------------
+// Randomized Sampling
+// -----------------------------------------
+
+// SampleAutocomplete draws k completions of prefix without replacement, with
+// each completion chosen proportionally to its ranking score. The same seed
+// always yields the same result, which makes A/B and exploration experiments
+// reproducible. The candidates and their scores are those Autocomplete
+// ranks, so the result options, scorer and filters apply; completions
+// without a positive score are never sampled.
+func (t *TrieA1) SampleAutocomplete(prefix string, k int, seed int64) []Suggestion {
+	ranked := t.candidates(prefix)
+
+	// Map-backed nodes give their children in no fixed order; sort the
+	// candidates so a seeded source draws the same sample on every run.
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].Word < ranked[j].Word
+	})
+
+	// Weighted reservoir sampling (Efraimidis-Spirakis): each candidate gets
+	// the key u^(1/w) and the k largest keys win.
+	rng := rand.New(rand.NewSource(seed))
+	type keyed struct {
+		suggestion Suggestion
+		key        float64
+	}
+	var candidates []keyed
+	for _, s := range ranked {
+		if s.Score <= 0 {
+			continue
+		}
+		key := math.Pow(rng.Float64(), 1/s.Score)
+		candidates = append(candidates, keyed{s, key})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+
+	k = clampK(t.options.limit(k), len(candidates))
+	sampled := make([]Suggestion, k)
+	for i := range sampled {
+		sampled[i] = candidates[i].suggestion
+	}
+	t.roundProbabilities(sampled)
+	return sampled
+}
@@ -0,0 +1,74 @@
+package autocomplete
+
+import "testing"
+
+func TestSampleAutocompleteReproducible(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hell", "helicopter", "hero", "help"}
+	trie := buildAlg1Trie(corpus)
+
+	first := trie.SampleAutocomplete("he", 3, 7)
+	if len(first) != 3 {
+		t.Fatalf("Expected 3 samples, got %d", len(first))
+	}
+	for run := 0; run < 10; run++ {
+		again := trie.SampleAutocomplete("he", 3, 7)
+		for i := range first {
+			if again[i].Word != first[i].Word {
+				t.Fatalf("Run %d differs at %d: '%s' vs '%s'", run, i, again[i].Word, first[i].Word)
+			}
+		}
+	}
+}
+
+func TestSampleAutocompleteFavorsFrequentWords(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hello", "hello", "hello", "hero"}
+	trie := buildAlg1Trie(corpus)
+
+	counts := map[string]int{}
+	for seed := int64(0); seed < 1000; seed++ {
+		sampled := trie.SampleAutocomplete("he", 1, seed)
+		if len(sampled) != 1 {
+			t.Fatalf("Expected one sample, got %d", len(sampled))
+		}
+		counts[sampled[0].Word]++
+	}
+
+	if counts["hello"] <= counts["hero"] {
+		t.Errorf("Expected 'hello' to be sampled more often, got %v", counts)
+	}
+	if counts["hero"] == 0 {
+		t.Errorf("Expected 'hero' to be sampled at least once, got %v", counts)
+	}
+}
+
+func TestSampleAutocompleteMissingPrefix(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello"})
+	if got := trie.SampleAutocomplete("xyz", 3, 1); len(got) != 0 {
+		t.Errorf("Expected no samples for unknown prefix, got %v", got)
+	}
+}
+
+func TestSampleAutocompleteFollowsScorerAndOptions(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "help", "help", "helium"}
+
+	trie := buildAlg1Trie(corpus).WithOptions(Options{MinFrequency: 2})
+	for seed := int64(0); seed < 50; seed++ {
+		for _, s := range trie.SampleAutocomplete("he", 3, seed) {
+			if s.Word == "helium" {
+				t.Fatalf("Seed %d: expected MinFrequency to exclude 'helium'", seed)
+			}
+		}
+	}
+
+	// A scorer giving only "helium" a positive score makes it the only sample.
+	onlyHelium := ScorerFunc(func(candidate string, _ Context) float64 {
+		if candidate == "helium" {
+			return 1
+		}
+		return 0
+	})
+	trie = buildAlg1Trie(corpus).WithScorer(onlyHelium)
+	if got := trie.SampleAutocomplete("he", 3, 1); len(got) != 1 || got[0].Word != "helium" {
+		t.Errorf("Expected only 'helium' to be sampled, got %v", got)
+	}
+}
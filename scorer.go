@@ -0,0 +1,66 @@
+package autocomplete
+
+// -----------------------------------------
+// Pluggable Scoring
+// -----------------------------------------
+
+// Context is what a Scorer knows about the query a candidate answers.
+type Context struct {
+	// Prefix is the typed prefix.
+	Prefix string
+	// Previous are the words typed before it, most recent last; nil for
+	// queries without context.
+	Previous []string
+	// Score is the algorithm's own score for the candidate and Frequency
+	// its count, for scorers that adjust rather than replace the ranking.
+	Score     float64
+	Frequency int
+}
+
+// Scorer assigns candidates their final score, layering rules such as
+// boosting sponsored terms or demoting unwanted ones over the built-in
+// ranking without forking it. Results are ordered by the returned score,
+// highest first, and the score is what Suggestion.Score reports. A Scorer
+// must be deterministic, since results may be cached.
+type Scorer interface {
+	Score(candidate string, ctx Context) float64
+}
+
+// ScorerFunc adapts a function to the Scorer interface.
+type ScorerFunc func(candidate string, ctx Context) float64
+
+// Score calls f.
+func (f ScorerFunc) Score(candidate string, ctx Context) float64 {
+	return f(candidate, ctx)
+}
+
+// WithScorer makes s give every candidate its final score; nil restores the
+// built-in ranking. It returns t so it can be chained after NewTrieA1.
+func (t *TrieA1) WithScorer(s Scorer) *TrieA1 {
+	t.scorer = s
+	t.cache.clear()
+	return t
+}
+
+// WithScorer makes s give every candidate its final score; nil restores the
+// built-in ranking. It returns t so it can be chained after NewTriesA2.
+func (t *TriesA2) WithScorer(s Scorer) *TriesA2 {
+	t.scorer = s
+	return t
+}
+
+// rescore replaces each candidate's score with the scorer's, in place.
+func rescore(s Scorer, prefix string, previous []string, candidates []Suggestion) []Suggestion {
+	if s == nil {
+		return candidates
+	}
+	for i, c := range candidates {
+		candidates[i].Score = s.Score(c.Word, Context{
+			Prefix:    prefix,
+			Previous:  previous,
+			Score:     c.Score,
+			Frequency: c.Frequency,
+		})
+	}
+	return candidates
+}
@@ -0,0 +1,55 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScorer(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hell", "hell", "helium", "help"}
+	sponsored := ScorerFunc(func(candidate string, ctx Context) float64 {
+		switch candidate {
+		case "helium":
+			return ctx.Score + 1
+		case "hell":
+			return -1
+		}
+		return ctx.Score
+	})
+
+	tries := map[string]interface {
+		Autocomplete(string, int) []Suggestion
+	}{
+		"Algorithm_1": buildAlg1Trie(corpus).WithScorer(sponsored),
+		"Algorithm_2": buildAlg2Trie(corpus).WithScorer(sponsored),
+	}
+	for name, trie := range tries {
+		got := trie.Autocomplete("he", 4)
+		if words := Words(got); !reflect.DeepEqual(words, []string{"helium", "hello", "help", "hell"}) {
+			t.Errorf("%s: expected the boosted and demoted order, got %v", name, words)
+		}
+		if got[0].Score != 1+1.0/7 {
+			t.Errorf("%s: expected helium to report its boosted score, got %v", name, got[0].Score)
+		}
+	}
+}
+
+func TestScorerSeesContext(t *testing.T) {
+	trie := NewTrieA1()
+	trie.BuildFromCorpus([]string{"new", "york", "old", "yodel", "yodel"})
+
+	var seen Context
+	trie.WithScorer(ScorerFunc(func(candidate string, ctx Context) float64 {
+		seen = ctx
+		return ctx.Score
+	}))
+	trie.AutocompleteContext("yo", []string{"new"}, 1)
+	if seen.Prefix != "yo" || !reflect.DeepEqual(seen.Previous, []string{"new"}) {
+		t.Errorf("Expected the scorer to see prefix yo after [new], got %+v", seen)
+	}
+
+	trie.WithScorer(nil)
+	if got := trie.AutocompleteContext("yo", []string{"new"}, 1); got[0].Word != "york" {
+		t.Errorf("Expected the built-in ranking after removing the scorer, got %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package autocomplete
+
+import "sort"
+
+// -----------------------------------------
+// Hierarchical Key Completion
+// -----------------------------------------
+
+// SegmentCompletion is one level of a hierarchical key such as
+// "user.profile". HasChildren reports whether longer keys continue past the
+// next separator, i.e. whether the caller can drill further down.
+type SegmentCompletion struct {
+	Key         string
+	HasChildren bool
+}
+
+// AutocompleteSegment completes dotted or slashed keys one level at a time:
+// each completion of prefix stops at the next sep, so "user." yields
+// "user.profile" and "user.id" rather than every full key below them. A
+// stored key that ends before the next separator is returned as well.
+// Results are ranked by the total frequency of the keys beneath them, then
+// alphabetically, and truncated to k.
+func (t *TriesA2) AutocompleteSegment(prefix string, sep rune, k int) []SegmentCompletion {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return nil
+	}
+
+	type weighted struct {
+		SegmentCompletion
+		weight int
+	}
+	var results []weighted
+
+	var walk func(node *NodeA2, path string)
+	walk = func(node *NodeA2, path string) {
+		isWord := node.isEndOfWord && t.filter.allows(path)
+		weight, hasChildren := 0, false
+		if sepChild, ok := node.children[sep]; ok {
+			weight, hasChildren = t.shownFrequency(sepChild, path+string(sep))
+		}
+		if path != "" && (isWord || hasChildren) {
+			if isWord {
+				weight += node.frequency
+			}
+			results = append(results, weighted{SegmentCompletion{path, hasChildren}, weight})
+		}
+		for char, child := range node.children {
+			if char != sep {
+				walk(child, path+string(char))
+			}
+		}
+	}
+	walk(node, prefix)
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].weight != results[j].weight {
+			return results[i].weight > results[j].weight
+		}
+		return results[i].Key < results[j].Key
+	})
+
+	completions := make([]SegmentCompletion, clampK(k, len(results)))
+	for i := range completions {
+		completions[i] = results[i].SegmentCompletion
+	}
+	return completions
+}
+
+// shownFrequency sums the frequencies of the words at or below node, whose
+// path is path, that the filter lets through, and reports whether there are
+// any.
+func (t *TriesA2) shownFrequency(node *NodeA2, path string) (int, bool) {
+	total, shown := 0, false
+	if node.isEndOfWord && t.filter.allows(path) {
+		total, shown = node.frequency, true
+	}
+	for char, child := range node.children {
+		frequency, below := t.shownFrequency(child, path+string(char))
+		total += frequency
+		shown = shown || below
+	}
+	return total, shown
+}
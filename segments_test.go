@@ -0,0 +1,47 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAutocompleteSegmentDrillDown(t *testing.T) {
+	keys := []string{
+		"user.profile.name", "user.profile.name", "user.profile.age",
+		"user.settings.theme", "user.id", "users", "order.id",
+	}
+	trie := buildAlg2Trie(keys)
+
+	got := trie.AutocompleteSegment("user.", '.', 10)
+	want := []SegmentCompletion{
+		{Key: "user.profile", HasChildren: true},
+		{Key: "user.id", HasChildren: false},
+		{Key: "user.settings", HasChildren: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Level 2: expected %v, got %v", want, got)
+	}
+
+	got = trie.AutocompleteSegment("user.profile.", '.', 10)
+	want = []SegmentCompletion{
+		{Key: "user.profile.name", HasChildren: false},
+		{Key: "user.profile.age", HasChildren: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Level 3: expected %v, got %v", want, got)
+	}
+
+	got = trie.AutocompleteSegment("", '.', 10)
+	want = []SegmentCompletion{
+		{Key: "user", HasChildren: true},
+		{Key: "order", HasChildren: true},
+		{Key: "users", HasChildren: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Top level: expected %v, got %v", want, got)
+	}
+
+	if got := trie.AutocompleteSegment("user.", '.', 1); len(got) != 1 || got[0].Key != "user.profile" {
+		t.Errorf("Expected only the heaviest segment with k=1, got %v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package autocomplete
+
+// -----------------------------------------
+// Sentence Boundaries
+// -----------------------------------------
+
+// BuildBigramTableFromSentences counts the bigrams of every sentence on its
+// own, so no context spans the end of one sentence and the start of the
+// next, as it would if the sentences were joined into one corpus for
+// BuildBigramTable. Package corpus can split text into sentences.
+func (t *TrieA1) BuildBigramTableFromSentences(sentences [][]string) {
+	t.countBigrams(t.joinSentences(sentences))
+}
+
+// BuildNgramTableFromSentences is BuildNgramTable with no context spanning
+// two sentences.
+func (t *TrieA1) BuildNgramTableFromSentences(sentences [][]string, n int) {
+	t.countNgrams(t.joinSentences(sentences), n)
+}
+
+// BuildFromSentences is BuildFromCorpus with no context spanning two
+// sentences.
+func (t *TrieA1) BuildFromSentences(sentences [][]string) {
+	words := t.joinSentences(sentences)
+	for _, word := range words {
+		if word != "" {
+			t.Insert(word)
+		}
+	}
+	t.countBigrams(words)
+}
+
+// joinSentences tokenizes every sentence and joins them with an empty word
+// in between, which no context spans since it is never a valid word.
+func (t *TrieA1) joinSentences(sentences [][]string) []string {
+	var words []string
+	for i, sentence := range sentences {
+		if i > 0 {
+			words = append(words, "")
+		}
+		words = append(words, t.tokenize(sentence)...)
+	}
+	return words
+}
@@ -0,0 +1,37 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildBigramTableFromSentences(t *testing.T) {
+	sentences := [][]string{{"we", "won"}, {"then", "we", "left"}}
+
+	joined := NewTrieA1()
+	joined.BuildFromCorpus([]string{"we", "won", "then", "we", "left"})
+	if joined.bigramTable["won"]["then"] != 1 {
+		t.Fatalf("Expected the joined corpus to count won -> then")
+	}
+
+	trie := NewTrieA1()
+	trie.BuildFromSentences(sentences)
+	if _, ok := trie.bigramTable["won"]; ok {
+		t.Errorf("Expected no context across the sentence boundary, got %v", trie.bigramTable["won"])
+	}
+	if trie.bigramTable["we"]["left"] != 1 || trie.Len() != 4 || trie.Rejected() != 0 {
+		t.Errorf("Expected the words and in-sentence bigrams, got %v with %d words", trie.bigramTable, trie.Len())
+	}
+
+	bigrams := NewTrieA1()
+	bigrams.BuildBigramTableFromSentences(sentences)
+	if !reflect.DeepEqual(bigrams.bigramTable, trie.bigramTable) {
+		t.Errorf("Expected %v, got %v", trie.bigramTable, bigrams.bigramTable)
+	}
+
+	ngrams := NewTrieA1()
+	ngrams.BuildNgramTableFromSentences([][]string{{"a", "b"}, {"c", "d"}}, 3)
+	if _, ok := ngrams.ngramTable["a"+ngramSeparator+"b"]; ok {
+		t.Errorf("Expected no trigram context across the boundary, got %v", ngrams.ngramTable)
+	}
+}
@@ -0,0 +1,331 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+// -----------------------------------------
+// A/B Comparison
+// -----------------------------------------
+
+// Experiment splits the queries of GET /experiment/suggest between two
+// variants, each the name of a1, a2 or an Autocompleter added with
+// Register.
+type Experiment struct {
+	A, B string
+	// PercentB is the share of queries, from 0 to 100, answered by B.
+	PercentB int
+	// Sink, if not nil, receives every query and selection of the
+	// experiment as a JSON line, for offline analysis.
+	Sink io.Writer
+}
+
+// experiment is a running Experiment and what it has measured so far.
+type experiment struct {
+	Experiment
+	tries map[string]*autocomplete.Concurrent
+
+	mu    sync.Mutex
+	sink  *json.Encoder
+	stats map[string]*variantStats
+}
+
+// variantStats are the measurements kept per variant.
+type variantStats struct {
+	latency     *histogram
+	queries     int
+	empty       int
+	feedback    int
+	selections  int
+	reciprocals float64
+}
+
+// ExperimentEvent is one line written to an experiment's sink: a query,
+// with its latency and suggestions, or the user's selection after one.
+type ExperimentEvent struct {
+	Time        time.Time `json:"time"`
+	Variant     string    `json:"variant"`
+	User        string    `json:"user,omitempty"`
+	Prefix      string    `json:"prefix"`
+	Context     string    `json:"context,omitempty"`
+	Suggestions []string  `json:"suggestions,omitempty"`
+	Latency     float64   `json:"latencySeconds,omitempty"`
+	Selected    string    `json:"selected,omitempty"`
+	// Position is the index of Selected among the suggestions shown, or -1
+	// if the user picked none of them. It is only set on selections.
+	Position *int `json:"position,omitempty"`
+}
+
+// ExperimentResponse is the body returned by GET /experiment/suggest.
+type ExperimentResponse struct {
+	Variant     string                               `json:"variant"`
+	Prefix      string                               `json:"prefix"`
+	Context     string                               `json:"context,omitempty"`
+	Suggestions []autocomplete.HighlightedSuggestion `json:"suggestions"`
+}
+
+// ExperimentSelectRequest is the body accepted by POST /experiment/select:
+// the variant that answered, what the user had typed and the suggestion
+// they accepted. Position is its index among the suggestions shown; leave
+// Selected empty, or Position negative, when the user picked none.
+type ExperimentSelectRequest struct {
+	Variant  string `json:"variant"`
+	User     string `json:"user,omitempty"`
+	Prefix   string `json:"prefix"`
+	Context  string `json:"context,omitempty"`
+	Selected string `json:"selected,omitempty"`
+	Position int    `json:"position"`
+}
+
+// VariantStats are the measurements GET /experiment reports for a variant.
+// SelectionRate is the share of the feedback in which the user accepted a
+// suggestion and MeanReciprocalRank averages 1/(Position+1) over the same
+// feedback, counting no selection as 0.
+type VariantStats struct {
+	Name               string  `json:"name"`
+	Queries            int     `json:"queries"`
+	EmptyResults       int     `json:"emptyResults"`
+	MeanLatency        float64 `json:"meanLatencySeconds"`
+	P50Latency         float64 `json:"p50LatencySeconds"`
+	P95Latency         float64 `json:"p95LatencySeconds"`
+	Feedback           int     `json:"feedback"`
+	Selections         int     `json:"selections"`
+	SelectionRate      float64 `json:"selectionRate"`
+	MeanReciprocalRank float64 `json:"meanReciprocalRank"`
+}
+
+// ExperimentStats is the body returned by GET /experiment.
+type ExperimentStats struct {
+	PercentB int            `json:"percentB"`
+	Variants []VariantStats `json:"variants"`
+}
+
+// Register adds a under name, so an Experiment can name it as a variant.
+// The server takes ownership of a. The names a1 and a2 are taken by the
+// server's own tries.
+func (s *Server) Register(name string, a autocomplete.Autocompleter) *Server {
+	if name == AlgorithmContextual || name == AlgorithmFrequency {
+		panic(fmt.Sprintf("server: variant name %q is reserved", name))
+	}
+	if s.variants == nil {
+		s.variants = make(map[string]*autocomplete.Concurrent)
+	}
+	s.variants[name] = autocomplete.NewConcurrent(a)
+	return s
+}
+
+// variant returns the trie registered under name, or nil.
+func (s *Server) variant(name string) *autocomplete.Concurrent {
+	switch name {
+	case AlgorithmContextual:
+		return s.a1
+	case AlgorithmFrequency:
+		return s.a2
+	}
+	return s.variants[name]
+}
+
+// EnableExperiment starts exp and serves it:
+//
+//	GET  /experiment/suggest?prefix=he&k=5&context=hello&user=42
+//	POST /experiment/select  {"variant": "a2", "prefix": "he", "selected": "hello", "position": 0}
+//	GET  /experiment         per-variant statistics
+//
+// Queries with a user are assigned a variant by a hash of it, so a user
+// keeps seeing the same one; queries without are assigned at random.
+// Enabling another experiment replaces this one and its statistics.
+func (s *Server) EnableExperiment(exp Experiment) error {
+	if exp.A == exp.B {
+		return fmt.Errorf("server: experiment variants must differ, got %q twice", exp.A)
+	}
+	if exp.PercentB < 0 || exp.PercentB > 100 {
+		return fmt.Errorf("server: experiment percentage must be between 0 and 100, got %d", exp.PercentB)
+	}
+	e := &experiment{
+		Experiment: exp,
+		tries:      make(map[string]*autocomplete.Concurrent),
+		stats:      make(map[string]*variantStats),
+	}
+	for _, name := range []string{exp.A, exp.B} {
+		trie := s.variant(name)
+		if trie == nil {
+			return fmt.Errorf("server: unknown experiment variant %q", name)
+		}
+		e.tries[name] = trie
+		e.stats[name] = &variantStats{latency: newHistogram(latencyBuckets)}
+	}
+	if exp.Sink != nil {
+		e.sink = json.NewEncoder(exp.Sink)
+	}
+
+	s.experimentMu.Lock()
+	started := s.experiment != nil
+	s.experiment = e
+	s.experimentMu.Unlock()
+	if !started {
+		s.mux.HandleFunc("GET /experiment/suggest", s.handleExperimentSuggest)
+		s.mux.HandleFunc("POST /experiment/select", s.handleExperimentSelect)
+		s.mux.HandleFunc("GET /experiment", s.handleExperimentStats)
+	}
+	return nil
+}
+
+func (s *Server) currentExperiment() *experiment {
+	s.experimentMu.Lock()
+	defer s.experimentMu.Unlock()
+	return s.experiment
+}
+
+// assign picks the variant that answers a query from user.
+func (e *experiment) assign(user string) string {
+	var bucket int
+	if user != "" {
+		h := fnv.New32a()
+		io.WriteString(h, user)
+		bucket = int(h.Sum32() % 100)
+	} else {
+		bucket = rand.Intn(100)
+	}
+	if bucket < e.PercentB {
+		return e.B
+	}
+	return e.A
+}
+
+// log writes event to the sink, if any. e.mu is held.
+func (e *experiment) log(event ExperimentEvent) {
+	if e.sink != nil {
+		e.sink.Encode(event)
+	}
+}
+
+func (s *Server) handleExperimentSuggest(w http.ResponseWriter, r *http.Request) {
+	e := s.currentExperiment()
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+
+	k := s.k
+	if raw := query.Get("k"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"k must be a non-negative integer"})
+			return
+		}
+		k = parsed
+	}
+
+	user := query.Get("user")
+	variant := e.assign(user)
+	context := query.Get("context")
+	start := time.Now()
+	suggestions, err := complete(r.Context(), e.tries[variant], prefix, context, k)
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{"query abandoned: " + err.Error()})
+		return
+	}
+	elapsed := time.Since(start)
+
+	e.mu.Lock()
+	stats := e.stats[variant]
+	stats.queries++
+	stats.latency.observe(elapsed.Seconds())
+	if len(suggestions) == 0 {
+		stats.empty++
+	}
+	e.log(ExperimentEvent{
+		Time:        start,
+		Variant:     variant,
+		User:        user,
+		Prefix:      prefix,
+		Context:     context,
+		Suggestions: autocomplete.Words(suggestions),
+		Latency:     elapsed.Seconds(),
+	})
+	e.mu.Unlock()
+
+	w.Header().Set("X-Experiment-Variant", variant)
+	writeJSON(w, http.StatusOK, ExperimentResponse{
+		Variant:     variant,
+		Prefix:      prefix,
+		Context:     context,
+		Suggestions: autocomplete.HighlightMatches(prefix, suggestions),
+	})
+}
+
+func (s *Server) handleExperimentSelect(w http.ResponseWriter, r *http.Request) {
+	e := s.currentExperiment()
+	var req ExperimentSelectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"invalid JSON body: " + err.Error()})
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats := e.stats[req.Variant]
+	if stats == nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("variant must be %s or %s", e.A, e.B)})
+		return
+	}
+	if req.Selected == "" || req.Position < 0 {
+		req.Selected, req.Position = "", -1
+	}
+	stats.feedback++
+	if req.Selected != "" {
+		stats.selections++
+		stats.reciprocals += 1 / float64(req.Position+1)
+	}
+	e.log(ExperimentEvent{
+		Time:     time.Now(),
+		Variant:  req.Variant,
+		User:     req.User,
+		Prefix:   req.Prefix,
+		Context:  req.Context,
+		Selected: req.Selected,
+		Position: &req.Position,
+	})
+	writeJSON(w, http.StatusOK, e.variantStats(req.Variant))
+}
+
+func (s *Server) handleExperimentStats(w http.ResponseWriter, r *http.Request) {
+	e := s.currentExperiment()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	writeJSON(w, http.StatusOK, ExperimentStats{
+		PercentB: e.PercentB,
+		Variants: []VariantStats{e.variantStats(e.A), e.variantStats(e.B)},
+	})
+}
+
+// variantStats summarises the measurements of the named variant. e.mu is
+// held.
+func (e *experiment) variantStats(name string) VariantStats {
+	stats := e.stats[name]
+	v := VariantStats{
+		Name:         name,
+		Queries:      stats.queries,
+		EmptyResults: stats.empty,
+		P50Latency:   stats.latency.quantile(0.5),
+		P95Latency:   stats.latency.quantile(0.95),
+		Feedback:     stats.feedback,
+		Selections:   stats.selections,
+	}
+	if stats.queries > 0 {
+		v.MeanLatency = stats.latency.sum / float64(stats.queries)
+	}
+	if stats.feedback > 0 {
+		v.SelectionRate = float64(stats.selections) / float64(stats.feedback)
+		v.MeanReciprocalRank = stats.reciprocals / float64(stats.feedback)
+	}
+	return v
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	autocomplete "auto-complete"
+)
+
+func experimentSuggest(t *testing.T, s *Server, query string) ExperimentResponse {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/experiment/suggest?"+query, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp ExperimentResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if got := rec.Header().Get("X-Experiment-Variant"); got != resp.Variant {
+		t.Errorf("Expected the variant header to be %q, got %q", resp.Variant, got)
+	}
+	return resp
+}
+
+func experimentStats(t *testing.T, s *Server) ExperimentStats {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/experiment", nil))
+	var stats ExperimentStats
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	return stats
+}
+
+func TestExperimentRouting(t *testing.T) {
+	tests := []struct {
+		percentB int
+		want     string
+	}{
+		{0, AlgorithmContextual},
+		{100, AlgorithmFrequency},
+	}
+	for _, test := range tests {
+		s := newTestServer()
+		if err := s.EnableExperiment(Experiment{A: AlgorithmContextual, B: AlgorithmFrequency, PercentB: test.percentB}); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 10; i++ {
+			if resp := experimentSuggest(t, s, "prefix=hel"); resp.Variant != test.want {
+				t.Errorf("Expected every query to go to %s at %d%%, got %s", test.want, test.percentB, resp.Variant)
+			}
+		}
+	}
+}
+
+func TestExperimentStickyUsers(t *testing.T) {
+	s := newTestServer()
+	if err := s.EnableExperiment(Experiment{A: AlgorithmContextual, B: AlgorithmFrequency, PercentB: 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{}
+	for user := 0; user < 200; user++ {
+		first := experimentSuggest(t, s, fmt.Sprintf("prefix=hel&user=%d", user)).Variant
+		if again := experimentSuggest(t, s, fmt.Sprintf("prefix=he&user=%d", user)).Variant; again != first {
+			t.Errorf("Expected user %d to stay on %s, got %s", user, first, again)
+		}
+		counts[first]++
+	}
+	if b := counts[AlgorithmFrequency]; b < 30 || b > 90 {
+		t.Errorf("Expected about 30%% of 200 users on B, got %d", b)
+	}
+}
+
+func TestExperimentRegisteredVariant(t *testing.T) {
+	s := newTestServer()
+	other := autocomplete.NewTriesA2()
+	other.Insert("helium")
+	s.Register("elements", other)
+	if err := s.EnableExperiment(Experiment{A: AlgorithmContextual, B: "elements", PercentB: 100}); err != nil {
+		t.Fatal(err)
+	}
+	resp := experimentSuggest(t, s, "prefix=hel")
+	if resp.Variant != "elements" || len(resp.Suggestions) != 1 || resp.Suggestions[0].Word != "helium" {
+		t.Errorf("Expected helium from the registered variant, got %+v", resp)
+	}
+
+	if err := s.EnableExperiment(Experiment{A: "a1", B: "missing"}); err == nil {
+		t.Errorf("Expected an error for an unknown variant")
+	}
+	if err := s.EnableExperiment(Experiment{A: "a1", B: "a2", PercentB: 101}); err == nil {
+		t.Errorf("Expected an error for a percentage over 100")
+	}
+}
+
+func TestExperimentStats(t *testing.T) {
+	s := newTestServer()
+	var sink bytes.Buffer
+	err := s.EnableExperiment(Experiment{A: AlgorithmContextual, B: AlgorithmFrequency, PercentB: 100, Sink: &sink})
+	if err != nil {
+		t.Fatal(err)
+	}
+	experimentSuggest(t, s, "prefix=hel")
+	experimentSuggest(t, s, "prefix=hel")
+	experimentSuggest(t, s, "prefix=zzz")
+
+	for _, body := range []string{
+		`{"variant": "a2", "prefix": "hel", "selected": "hell", "position": 1}`,
+		`{"variant": "a2", "prefix": "hel", "position": -1}`,
+	} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/experiment/select", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/experiment/select", strings.NewReader(`{"variant": "a3"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a variant outside the experiment, got %d", rec.Code)
+	}
+
+	stats := experimentStats(t, s)
+	if stats.PercentB != 100 || len(stats.Variants) != 2 {
+		t.Fatalf("Expected both variants at 100%%, got %+v", stats)
+	}
+	a, b := stats.Variants[0], stats.Variants[1]
+	if a.Queries != 0 || a.Feedback != 0 {
+		t.Errorf("Expected no traffic on A, got %+v", a)
+	}
+	if b.Queries != 3 || b.EmptyResults != 1 || b.Feedback != 2 || b.Selections != 1 {
+		t.Errorf("Expected 3 queries, 1 empty and 1 of 2 selections on B, got %+v", b)
+	}
+	if b.SelectionRate != 0.5 || b.MeanReciprocalRank != 0.25 {
+		t.Errorf("Expected a selection rate of 0.5 and an MRR of 0.25, got %v and %v", b.SelectionRate, b.MeanReciprocalRank)
+	}
+	if b.P95Latency <= 0 || b.P50Latency > b.P95Latency {
+		t.Errorf("Expected ordered positive latency quantiles, got %v and %v", b.P50Latency, b.P95Latency)
+	}
+	if got := strings.Count(sink.String(), "\n"); got != 5 {
+		t.Errorf("Expected 5 logged events, got %d", got)
+	}
+}
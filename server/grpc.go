@@ -0,0 +1,304 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+// -----------------------------------------
+// gRPC Service
+// -----------------------------------------
+
+// The Autocomplete service of proto/autocomplete.proto, served on the same
+// mux as the HTTP API. gRPC runs over HTTP/2, which net/http only speaks
+// over TLS, so clients reach it on a server started with ListenAndServeTLS
+// and must dial with TLS credentials: plaintext (h2c) connections are not
+// supported, and HTTP/1 requests are refused with 415. The codec in
+// protobuf.go is checked against the reference protobuf runtime's
+// encodings in testdata/protobuf.golden.
+
+// grpcService is the service's full name, the first path element of its
+// methods.
+const grpcService = "autocomplete.v1.Autocomplete"
+
+// maxGRPCMessage bounds the size of a request message.
+const maxGRPCMessage = 4 << 20
+
+// gRPC status codes answered by the service.
+const (
+	grpcOK              = 0
+	grpcInvalidArgument = 3
+	grpcUnimplemented   = 12
+	grpcInternal        = 13
+	grpcUnavailable     = 14
+)
+
+// grpcError is an RPC failure with its gRPC status code.
+type grpcError struct {
+	code    int
+	message string
+}
+
+func (e *grpcError) Error() string {
+	return e.message
+}
+
+func grpcErrorf(code int, format string, args ...any) error {
+	return &grpcError{code, fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) handleGRPC(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || !isGRPCContentType(r.Header.Get("Content-Type")) {
+		http.Error(w, "gRPC requests need HTTP/2 and an application/grpc content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+	// Streaming clients wait for the headers before sending.
+	http.NewResponseController(w).Flush()
+
+	var err error
+	switch method := r.PathValue("method"); method {
+	case "Suggest":
+		err = unary(w, r, s.grpcSuggest)
+	case "SuggestStream":
+		err = s.grpcSuggestStream(w, r)
+	case "Insert":
+		err = unary(w, r, s.grpcInsert)
+	case "BatchInsert":
+		err = unary(w, r, s.grpcBatchInsert)
+	case "Stats":
+		err = unary(w, r, s.grpcStats)
+	default:
+		err = grpcErrorf(grpcUnimplemented, "unknown method %s", method)
+	}
+	writeGRPCStatus(w, err)
+}
+
+func isGRPCContentType(contentType string) bool {
+	return contentType == "application/grpc" || strings.HasPrefix(contentType, "application/grpc+proto") ||
+		strings.HasPrefix(contentType, "application/grpc;")
+}
+
+// writeGRPCStatus ends the call with the status of err in the trailers.
+func writeGRPCStatus(w http.ResponseWriter, err error) {
+	code, message := grpcOK, ""
+	if err != nil {
+		var rpcErr *grpcError
+		if errors.As(err, &rpcErr) {
+			code, message = rpcErr.code, rpcErr.message
+		} else {
+			code, message = grpcInternal, err.Error()
+		}
+	}
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	if message != "" {
+		w.Header().Set("Grpc-Message", percentEncode(message))
+	}
+}
+
+// percentEncode escapes a status message as gRPC does: every byte but
+// printable ASCII other than % becomes %XX.
+func percentEncode(message string) string {
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		if c := message[i]; c >= ' ' && c <= '~' && c != '%' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// unary answers a call of one request message with one response message.
+func unary[Req any, PReq interface {
+	*Req
+	unmarshal([]byte) error
+}](w http.ResponseWriter, r *http.Request, call func(*http.Request, *Req) ([]byte, error)) error {
+	data, err := readGRPCMessage(r.Body)
+	if err == io.EOF {
+		return grpcErrorf(grpcInvalidArgument, "missing request message")
+	}
+	if err != nil {
+		return err
+	}
+	var req Req
+	if err := PReq(&req).unmarshal(data); err != nil {
+		return grpcErrorf(grpcInvalidArgument, "invalid request: %v", err)
+	}
+	resp, err := call(r, &req)
+	if err != nil {
+		return err
+	}
+	return writeGRPCMessage(w, resp)
+}
+
+// readGRPCMessage reads one length-prefixed message, or returns io.EOF once
+// the client has sent all of them.
+func readGRPCMessage(body io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, grpcErrorf(grpcInvalidArgument, "reading message: %v", err)
+	}
+	if header[0] != 0 {
+		return nil, grpcErrorf(grpcUnimplemented, "compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxGRPCMessage {
+		return nil, grpcErrorf(grpcInvalidArgument, "message of %d bytes exceeds the limit of %d", size, maxGRPCMessage)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(body, data); err != nil {
+		return nil, grpcErrorf(grpcInvalidArgument, "reading message: %v", err)
+	}
+	return data, nil
+}
+
+// writeGRPCMessage sends one length-prefixed message and flushes it, so a
+// streaming client sees it before the call ends.
+func writeGRPCMessage(w http.ResponseWriter, data []byte) error {
+	frame := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	if _, err := w.Write(append(frame, data...)); err != nil {
+		return err
+	}
+	http.NewResponseController(w).Flush()
+	return nil
+}
+
+func (s *Server) grpcSuggest(r *http.Request, req *protoSuggestRequest) ([]byte, error) {
+	k := s.k
+	if req.K < 0 {
+		return nil, grpcErrorf(grpcInvalidArgument, "k must be non-negative")
+	}
+	if req.K > 0 {
+		k = int(req.K)
+	}
+	algorithm := req.Algorithm
+	if algorithm == "" {
+		algorithm = s.algorithm
+	}
+	var trie *autocomplete.Concurrent
+	switch algorithm {
+	case AlgorithmContextual:
+		trie = s.a1
+	case AlgorithmFrequency:
+		trie = s.a2
+	default:
+		return nil, grpcErrorf(grpcInvalidArgument, "algorithm must be a1 or a2")
+	}
+
+	start := time.Now()
+	var suggestions []autocomplete.Suggestion
+	var err error
+	if len(req.Context) > 0 && algorithm == AlgorithmContextual {
+		trie.View(func(a autocomplete.Autocompleter) {
+			suggestions = a.(*autocomplete.TrieA1).AutocompleteContext(req.Prefix, req.Context, k)
+		})
+	} else {
+		suggestions, err = complete(r.Context(), trie, req.Prefix, "", k)
+	}
+	if err != nil {
+		return nil, grpcErrorf(grpcUnavailable, "query abandoned: %v", err)
+	}
+	s.metrics.observeQuery(algorithm, time.Since(start), len(suggestions))
+
+	resp := protoSuggestResponse{Prefix: req.Prefix, Algorithm: algorithm}
+	for _, h := range autocomplete.HighlightMatches(req.Prefix, suggestions) {
+		resp.Suggestions = append(resp.Suggestions, protoSuggestion{
+			Word:       h.Word,
+			Score:      h.Score,
+			Frequency:  int64(h.Frequency),
+			MatchStart: int32(h.Highlight.Start),
+			MatchEnd:   int32(h.Highlight.End),
+		})
+	}
+	return resp.marshal(), nil
+}
+
+// grpcSuggestStream answers each request on the stream as it arrives.
+func (s *Server) grpcSuggestStream(w http.ResponseWriter, r *http.Request) error {
+	// Responses go out while requests still come in.
+	if err := http.NewResponseController(w).EnableFullDuplex(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	for {
+		data, err := readGRPCMessage(r.Body)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var req protoSuggestRequest
+		if err := req.unmarshal(data); err != nil {
+			return grpcErrorf(grpcInvalidArgument, "invalid request: %v", err)
+		}
+		resp, err := s.grpcSuggest(r, &req)
+		if err != nil {
+			return err
+		}
+		if err := writeGRPCMessage(w, resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) grpcInsert(_ *http.Request, req *protoInsertRequest) ([]byte, error) {
+	if req.Word == "" {
+		return nil, grpcErrorf(grpcInvalidArgument, "word must not be empty")
+	}
+	weight := req.Weight
+	if weight < 0 {
+		return nil, grpcErrorf(grpcInvalidArgument, "weight must be non-negative")
+	}
+	if weight == 0 {
+		weight = 1
+	}
+	s.a1.InsertWithWeight(req.Word, int(weight))
+	s.a2.InsertWithWeight(req.Word, int(weight))
+	s.metrics.observeInsert(AlgorithmContextual, 1)
+	s.metrics.observeInsert(AlgorithmFrequency, 1)
+	return protoInsertResponse{Inserted: weight}.marshal(), nil
+}
+
+func (s *Server) grpcBatchInsert(_ *http.Request, req *protoBatchInsertRequest) ([]byte, error) {
+	s.insertWords(req.Words)
+	return protoInsertResponse{Inserted: int64(len(req.Words))}.marshal(), nil
+}
+
+func (s *Server) grpcStats(_ *http.Request, _ *protoStatsRequest) ([]byte, error) {
+	resp := protoStatsResponse{Words: map[string]int64{
+		AlgorithmContextual: int64(s.a1.Len()),
+		AlgorithmFrequency:  int64(s.a2.Len()),
+	}}
+	s.a1.View(func(a autocomplete.Autocompleter) {
+		hits, misses := a.(*autocomplete.TrieA1).CacheStats()
+		resp.CacheHits, resp.CacheMisses = int64(hits), int64(misses)
+	})
+	return resp.marshal(), nil
+}
+
+// sortedNames returns the keys of m in order, so encodings are stable.
+func sortedNames(m map[string]int64) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
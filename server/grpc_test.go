@@ -0,0 +1,256 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newGRPCTestServer serves newTestServer over TLS with HTTP/2, as gRPC
+// clients need.
+func newGRPCTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	s := newTestServer()
+	ts := httptest.NewUnstartedServer(s)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func grpcFrame(message []byte) []byte {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+func grpcPost(t *testing.T, ts *httptest.Server, method string, body io.Reader) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/"+grpcService+"/"+method, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("Expected an HTTP/2 response, got %s", resp.Proto)
+	}
+	return resp
+}
+
+// grpcCall makes a unary call and returns its response message and status.
+func grpcCall(t *testing.T, ts *httptest.Server, method string, request []byte) ([]byte, string) {
+	t.Helper()
+	resp := grpcPost(t, ts, method, bytes.NewReader(grpcFrame(request)))
+	defer resp.Body.Close()
+	message, err := readGRPCMessage(resp.Body)
+	if err == io.EOF {
+		message = nil
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return message, resp.Trailer.Get("Grpc-Status")
+}
+
+func TestGRPCSuggest(t *testing.T) {
+	_, ts := newGRPCTestServer(t)
+
+	data, status := grpcCall(t, ts, "Suggest", protoSuggestRequest{Prefix: "hel", K: 2}.marshal())
+	if status != "0" {
+		t.Fatalf("Expected status 0, got %q", status)
+	}
+	var resp protoSuggestResponse
+	if err := resp.unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Algorithm != AlgorithmContextual || len(resp.Suggestions) != 2 {
+		t.Fatalf("Expected 2 a1 suggestions, got %+v", resp)
+	}
+	if got := resp.Suggestions[0]; got.Word != "hello" || got.Frequency != 2 || got.MatchEnd != 3 {
+		t.Errorf("Expected 'hello' with frequency 2 matching 3 runes, got %+v", got)
+	}
+
+	_, status = grpcCall(t, ts, "Suggest", protoSuggestRequest{Prefix: "he", Algorithm: "a3"}.marshal())
+	if status != "3" {
+		t.Errorf("Expected INVALID_ARGUMENT for an unknown algorithm, got %q", status)
+	}
+	_, status = grpcCall(t, ts, "Shout", nil)
+	if status != "12" {
+		t.Errorf("Expected UNIMPLEMENTED for an unknown method, got %q", status)
+	}
+}
+
+func TestGRPCInsertAndStats(t *testing.T) {
+	s, ts := newGRPCTestServer(t)
+
+	data, status := grpcCall(t, ts, "Insert", protoInsertRequest{Word: "helm", Weight: 5}.marshal())
+	var inserted protoInsertResponse
+	if err := inserted.unmarshal(data); err != nil || status != "0" || inserted.Inserted != 5 {
+		t.Fatalf("Expected 5 inserted, got %+v (status %q, err %v)", inserted, status, err)
+	}
+	if _, resp := suggest(t, s, "prefix=hel&k=1&algorithm=a2"); resp.Suggestions[0].Word != "helm" {
+		t.Errorf("Expected the weighted 'helm' first, got %v", resp.Suggestions)
+	}
+
+	data, status = grpcCall(t, ts, "BatchInsert", protoBatchInsertRequest{Words: []string{"brave", "new", "world"}}.marshal())
+	inserted = protoInsertResponse{}
+	if err := inserted.unmarshal(data); err != nil || status != "0" || inserted.Inserted != 3 {
+		t.Fatalf("Expected 3 inserted, got %+v (status %q, err %v)", inserted, status, err)
+	}
+
+	data, status = grpcCall(t, ts, "Stats", nil)
+	var stats protoStatsResponse
+	if err := stats.unmarshal(data); err != nil || status != "0" {
+		t.Fatalf("Expected stats, got status %q, err %v", status, err)
+	}
+	// hello, hell, helicopter, world, helm, brave and new.
+	want := map[string]int64{AlgorithmContextual: 7, AlgorithmFrequency: 7}
+	if !reflect.DeepEqual(stats.Words, want) {
+		t.Errorf("Expected %v, got %v", want, stats.Words)
+	}
+}
+
+func TestGRPCSuggestStream(t *testing.T) {
+	_, ts := newGRPCTestServer(t)
+
+	requests, send := io.Pipe()
+	resp := grpcPost(t, ts, "SuggestStream", requests)
+	defer resp.Body.Close()
+
+	// Each response arrives before the next request is sent.
+	for _, prefix := range []string{"h", "he", "wor"} {
+		if _, err := send.Write(grpcFrame(protoSuggestRequest{Prefix: prefix, K: 1}.marshal())); err != nil {
+			t.Fatal(err)
+		}
+		data, err := readGRPCMessage(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got protoSuggestResponse
+		if err := got.unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+		if got.Prefix != prefix || len(got.Suggestions) != 1 {
+			t.Errorf("For prefix %q: expected one suggestion, got %+v", prefix, got)
+		}
+	}
+	send.Close()
+	if _, err := readGRPCMessage(resp.Body); err != io.EOF {
+		t.Errorf("Expected the stream to end, got %v", err)
+	}
+	if status := resp.Trailer.Get("Grpc-Status"); status != "0" {
+		t.Errorf("Expected status 0, got %q", status)
+	}
+}
+
+func TestGRPCNeedsHTTP2(t *testing.T) {
+	s := newTestServer()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/"+grpcService+"/Suggest", bytes.NewReader(grpcFrame(nil)))
+	req.Header.Set("Content-Type", "application/grpc")
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected status 415 over HTTP/1.1, got %d", rec.Code)
+	}
+}
+
+func TestProtoRoundTrip(t *testing.T) {
+	want := protoSuggestResponse{Prefix: "hé", Algorithm: "a1", Suggestions: []protoSuggestion{
+		{Word: "héllo", Score: 0.5, Frequency: 3, MatchEnd: 2},
+		{},
+	}}
+	var got protoSuggestResponse
+	if err := got.unmarshal(want.marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	var req protoSuggestRequest
+	if err := req.unmarshal([]byte{0x0a, 0x05, 'h'}); err == nil {
+		t.Errorf("Expected an error for a truncated field")
+	}
+}
+
+// TestProtoGolden checks the codec against encodings made by the reference
+// protobuf runtime, so clients generated from proto/autocomplete.proto
+// read what the server writes and the other way round.
+func TestProtoGolden(t *testing.T) {
+	type message interface {
+		marshal() []byte
+		unmarshal([]byte) error
+	}
+	tests := map[string]struct{ want, got message }{
+		"SuggestRequest": {
+			&protoSuggestRequest{Prefix: "hél", K: 2, Algorithm: "a1", Context: []string{"say", "the"}},
+			&protoSuggestRequest{},
+		},
+		"SuggestRequestNegative": {&protoSuggestRequest{Prefix: "x", K: -1}, &protoSuggestRequest{}},
+		"SuggestResponse": {
+			&protoSuggestResponse{Prefix: "hél", Algorithm: "a1", Suggestions: []protoSuggestion{
+				{Word: "héllo", Score: 0.5, Frequency: 3, MatchEnd: 3},
+				{Word: "help", Score: 0.25, Frequency: 1, MatchStart: 1, MatchEnd: 3},
+			}},
+			&protoSuggestResponse{},
+		},
+		"InsertRequest":      {&protoInsertRequest{Word: "helm", Weight: 5}, &protoInsertRequest{}},
+		"BatchInsertRequest": {&protoBatchInsertRequest{Words: []string{"brave", "new", "world"}}, &protoBatchInsertRequest{}},
+		"InsertResponse":     {&protoInsertResponse{Inserted: 300}, &protoInsertResponse{}},
+		"StatsResponse": {
+			&protoStatsResponse{Words: map[string]int64{"a1": 7, "a2": 1000}, CacheHits: 4, CacheMisses: 2},
+			&protoStatsResponse{},
+		},
+	}
+
+	f, err := os.Open("testdata/protobuf.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	seen := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, encoded, _ := strings.Cut(line, " ")
+		test, ok := tests[name]
+		if !ok {
+			t.Errorf("Unexpected golden message %s", name)
+			continue
+		}
+		seen++
+		golden, err := hex.DecodeString(encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := test.want.marshal(); !bytes.Equal(got, golden) {
+			t.Errorf("%s: expected encoding %x, got %x", name, golden, got)
+		}
+		if err := test.got.unmarshal(golden); err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("%s: expected %+v, got %+v", name, test.want, test.got)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if seen != len(tests) {
+		t.Errorf("Expected %d golden messages, got %d", len(tests), seen)
+	}
+}
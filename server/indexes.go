@@ -0,0 +1,74 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	autocomplete "auto-complete"
+)
+
+// -----------------------------------------
+// Named Indexes
+// -----------------------------------------
+
+// WithIndexes serves the indexes of set next to the server's own tries, so
+// one process can serve many datasets:
+//
+//	GET /indexes                                 stats of every index
+//	GET /indexes/{name}/suggest?prefix=he&k=5&context=say+hello
+//
+// context holds the previous words separated by spaces. Indexes registered
+// from disk are loaded by the first query that needs them.
+func (s *Server) WithIndexes(set *autocomplete.IndexSet) *Server {
+	s.indexes = set
+	s.mux.HandleFunc("GET /indexes", s.handleIndexes)
+	s.mux.HandleFunc("GET /indexes/{name}/suggest", s.handleIndexSuggest)
+	return s
+}
+
+// IndexSuggestResponse is the body returned by GET /indexes/{name}/suggest.
+type IndexSuggestResponse struct {
+	Index       string                               `json:"index"`
+	Prefix      string                               `json:"prefix"`
+	Context     string                               `json:"context,omitempty"`
+	Suggestions []autocomplete.HighlightedSuggestion `json:"suggestions"`
+}
+
+func (s *Server) handleIndexes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.indexes.Stats())
+}
+
+func (s *Server) handleIndexSuggest(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+
+	k := s.k
+	if raw := query.Get("k"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"k must be a non-negative integer"})
+			return
+		}
+		k = parsed
+	}
+
+	context := query.Get("context")
+	suggestions, err := s.indexes.Autocomplete(name, prefix, strings.Fields(context), k)
+	if errors.Is(err, autocomplete.ErrUnknownIndex) {
+		writeJSON(w, http.StatusNotFound, errorResponse{err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, IndexSuggestResponse{
+		Index:       name,
+		Prefix:      prefix,
+		Context:     context,
+		Suggestions: autocomplete.HighlightMatches(prefix, suggestions),
+	})
+}
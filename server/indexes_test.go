@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	autocomplete "auto-complete"
+)
+
+func newIndexTestServer() *Server {
+	en := autocomplete.NewTrieA1()
+	en.BuildFromCorpus([]string{"say", "hello", "say", "hello", "help"})
+	de := autocomplete.NewTrieA1()
+	de.BuildFromCorpus([]string{"hallo", "haus", "haus"})
+
+	set := autocomplete.NewIndexSet(nil)
+	set.Add("en", en)
+	set.Add("de", de)
+	return newTestServer().WithIndexes(set)
+}
+
+func TestIndexSuggest(t *testing.T) {
+	s := newIndexTestServer()
+
+	for name, want := range map[string]string{"en": "hello", "de": "haus"} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indexes/"+name+"/suggest?prefix=h&k=1&context=say", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", name, rec.Code)
+		}
+		var resp IndexSuggestResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Invalid JSON response: %v", err)
+		}
+		if resp.Index != name || len(resp.Suggestions) != 1 || resp.Suggestions[0].Word != want {
+			t.Errorf("%s: expected '%s', got %+v", name, want, resp)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indexes/fr/suggest?prefix=h", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown index, got %d", rec.Code)
+	}
+}
+
+func TestIndexStats(t *testing.T) {
+	s := newIndexTestServer()
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/indexes/en/suggest?prefix=he", nil))
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/indexes", nil))
+	var stats map[string]autocomplete.IndexStats
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if stats["en"].Queries != 1 || stats["en"].Words != 3 || stats["de"].Queries != 0 {
+		t.Errorf("Unexpected index stats %+v", stats)
+	}
+}
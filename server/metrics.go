@@ -0,0 +1,157 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+// -----------------------------------------
+// Prometheus Metrics
+// -----------------------------------------
+
+// latencyBuckets are the upper bounds, in seconds, of the query latency
+// histogram: 50µs to 1s.
+var latencyBuckets = []float64{0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// sizeBuckets are the upper bounds of the suggestions-per-query histogram.
+var sizeBuckets = []float64{0, 1, 2, 3, 5, 10, 20, 50}
+
+// histogram is a cumulative Prometheus histogram.
+type histogram struct {
+	bounds []float64
+	counts []uint64 // counts[i] observations <= bounds[i]; the last is +Inf
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.counts[len(h.bounds)]++
+	h.sum += v
+}
+
+// quantile estimates the q-th quantile as the upper bound of the bucket it
+// falls in. Observations beyond the last bound count as the last bound, and
+// an empty histogram gives 0.
+func (h *histogram) quantile(q float64) float64 {
+	total := h.counts[len(h.bounds)]
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(total)))
+	for i, bound := range h.bounds {
+		if h.counts[i] >= rank {
+			return bound
+		}
+	}
+	return h.bounds[len(h.bounds)-1]
+}
+
+func (h *histogram) write(w io.Writer, name, algorithm string) {
+	for i, bound := range h.bounds {
+		fmt.Fprintf(w, "%s_bucket{algorithm=%q,le=%q} %d\n", name, algorithm, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{algorithm=%q,le=\"+Inf\"} %d\n", name, algorithm, h.counts[len(h.bounds)])
+	fmt.Fprintf(w, "%s_sum{algorithm=%q} %g\n", name, algorithm, h.sum)
+	fmt.Fprintf(w, "%s_count{algorithm=%q} %d\n", name, algorithm, h.counts[len(h.bounds)])
+}
+
+// algorithmMetrics are the metrics kept per algorithm.
+type algorithmMetrics struct {
+	latency     *histogram
+	suggestions *histogram
+	inserted    uint64
+}
+
+// metrics collects what the server exposes on GET /metrics.
+type metrics struct {
+	mu         sync.Mutex
+	algorithms map[string]*algorithmMetrics
+}
+
+func newMetrics() *metrics {
+	m := &metrics{algorithms: make(map[string]*algorithmMetrics)}
+	for _, algorithm := range []string{AlgorithmContextual, AlgorithmFrequency} {
+		m.algorithms[algorithm] = &algorithmMetrics{
+			latency:     newHistogram(latencyBuckets),
+			suggestions: newHistogram(sizeBuckets),
+		}
+	}
+	return m
+}
+
+func (m *metrics) observeQuery(algorithm string, elapsed time.Duration, suggestions int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a := m.algorithms[algorithm]
+	a.latency.observe(elapsed.Seconds())
+	a.suggestions.observe(float64(suggestions))
+}
+
+func (m *metrics) observeInsert(algorithm string, words int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.algorithms[algorithm].inserted += uint64(words)
+}
+
+// handleMetrics writes the metrics in the Prometheus text exposition format.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	sizes := map[string]int{
+		AlgorithmContextual: s.a1.Len(),
+		AlgorithmFrequency:  s.a2.Len(),
+	}
+	var hits, misses int
+	var memory autocomplete.NodeMemory
+	s.a1.View(func(a autocomplete.Autocompleter) {
+		hits, misses = a.(*autocomplete.TrieA1).CacheStats()
+		memory = a.(*autocomplete.TrieA1).NodeMemory()
+	})
+	algorithms := []string{AlgorithmContextual, AlgorithmFrequency}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.mu.Lock()
+	defer s.metrics.mu.Unlock()
+
+	header(w, "autocomplete_query_duration_seconds", "histogram", "Time taken to answer /suggest queries.")
+	for _, algorithm := range algorithms {
+		s.metrics.algorithms[algorithm].latency.write(w, "autocomplete_query_duration_seconds", algorithm)
+	}
+	header(w, "autocomplete_suggestions_returned", "histogram", "Number of suggestions returned per query.")
+	for _, algorithm := range algorithms {
+		s.metrics.algorithms[algorithm].suggestions.write(w, "autocomplete_suggestions_returned", algorithm)
+	}
+	header(w, "autocomplete_inserted_words_total", "counter", "Words inserted through POST /words.")
+	for _, algorithm := range algorithms {
+		fmt.Fprintf(w, "autocomplete_inserted_words_total{algorithm=%q} %d\n", algorithm, s.metrics.algorithms[algorithm].inserted)
+	}
+	header(w, "autocomplete_words", "gauge", "Distinct words stored.")
+	for _, algorithm := range algorithms {
+		fmt.Fprintf(w, "autocomplete_words{algorithm=%q} %d\n", algorithm, sizes[algorithm])
+	}
+	header(w, "autocomplete_cache_hits_total", "counter", "Algorithm_1 queries served from its result cache.")
+	fmt.Fprintf(w, "autocomplete_cache_hits_total %d\n", hits)
+	header(w, "autocomplete_cache_misses_total", "counter", "Algorithm_1 queries that missed its result cache.")
+	fmt.Fprintf(w, "autocomplete_cache_misses_total %d\n", misses)
+	header(w, "autocomplete_node_bytes", "gauge", "Estimated memory held by Algorithm_1's trie nodes.")
+	fmt.Fprintf(w, "autocomplete_node_bytes %d\n", memory.Bytes)
+	header(w, "autocomplete_compact_node_savings_bytes", "gauge", "Estimated memory Algorithm_1 saves with compact nodes over map nodes.")
+	fmt.Fprintf(w, "autocomplete_compact_node_savings_bytes %d\n", memory.MapBytes-memory.CompactBytes)
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	autocomplete "auto-complete"
+)
+
+func TestMetrics(t *testing.T) {
+	s := newTestServer()
+	suggest(t, s, "prefix=he&k=2&algorithm=a1")
+	suggest(t, s, "prefix=he&k=3&algorithm=a1")
+	suggest(t, s, "prefix=xyz&algorithm=a2")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/words", strings.NewReader(`{"words": ["zebra", "zeal"]}`)))
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected the Prometheus text content type, got %q", ct)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE autocomplete_query_duration_seconds histogram\n",
+		`autocomplete_query_duration_seconds_count{algorithm="a1"} 2` + "\n",
+		`autocomplete_query_duration_seconds_bucket{algorithm="a2",le="+Inf"} 1` + "\n",
+		`autocomplete_suggestions_returned_bucket{algorithm="a1",le="2"} 1` + "\n",
+		`autocomplete_suggestions_returned_bucket{algorithm="a1",le="3"} 2` + "\n",
+		`autocomplete_suggestions_returned_sum{algorithm="a1"} 5` + "\n",
+		`autocomplete_suggestions_returned_bucket{algorithm="a2",le="0"} 1` + "\n",
+		`autocomplete_inserted_words_total{algorithm="a2"} 2` + "\n",
+		`autocomplete_words{algorithm="a1"} 6` + "\n",
+		"autocomplete_cache_hits_total 0\n",
+		"# TYPE autocomplete_compact_node_savings_bytes gauge\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsNodeMemory(t *testing.T) {
+	corpus := []string{"hello", "hello", "hell", "helicopter", "world"}
+	a1 := autocomplete.NewTrieA1()
+	a1.BuildFromCorpus(corpus)
+	memory := a1.NodeMemory()
+	s := New(a1, autocomplete.NewTriesA2())
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		fmt.Sprintf("autocomplete_node_bytes %d\n", memory.MapBytes),
+		fmt.Sprintf("autocomplete_compact_node_savings_bytes %d\n", memory.MapBytes-memory.CompactBytes),
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// -----------------------------------------
+// Protocol Buffers Encoding
+// -----------------------------------------
+
+// The messages of proto/autocomplete.proto, encoded and decoded by hand so
+// the module keeps to the standard library. Writers leave out fields at
+// their zero value, as proto3 does, and readers skip unknown fields.
+
+// Wire types of the protobuf encoding.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errMalformed = errors.New("malformed protobuf message")
+
+// protoWriter appends fields to an encoded message.
+type protoWriter []byte
+
+func (w *protoWriter) key(field, wireType int) {
+	*w = binary.AppendUvarint(*w, uint64(field<<3|wireType))
+}
+
+func (w *protoWriter) uvarint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.key(field, wireVarint)
+	*w = binary.AppendUvarint(*w, v)
+}
+
+// int writes an int32 or int64 field; negative values take ten bytes.
+func (w *protoWriter) int(field int, v int64) {
+	w.uvarint(field, uint64(v))
+}
+
+func (w *protoWriter) double(field int, v float64) {
+	if v == 0 {
+		return
+	}
+	w.key(field, wireFixed64)
+	*w = binary.LittleEndian.AppendUint64(*w, math.Float64bits(v))
+}
+
+// bytes writes a length-delimited field, even an empty one, as repeated
+// fields and embedded messages need.
+func (w *protoWriter) bytes(field int, b []byte) {
+	w.key(field, wireBytes)
+	*w = binary.AppendUvarint(*w, uint64(len(b)))
+	*w = append(*w, b...)
+}
+
+func (w *protoWriter) string(field int, s string) {
+	if s != "" {
+		w.bytes(field, []byte(s))
+	}
+}
+
+// protoReader walks the fields of an encoded message: next moves to a
+// field, whose value one of the typed methods, or skip, must then consume.
+// The first error stops the walk and stays in err.
+type protoReader struct {
+	data     []byte
+	field    int
+	wireType int
+	err      error
+}
+
+func (r *protoReader) next() bool {
+	if r.err != nil || len(r.data) == 0 {
+		return false
+	}
+	key := r.uvarint()
+	r.field, r.wireType = int(key>>3), int(key&7)
+	if r.err == nil && r.field == 0 {
+		r.err = errMalformed
+	}
+	return r.err == nil
+}
+
+func (r *protoReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errMalformed
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *protoReader) expect(wireType int) bool {
+	if r.err == nil && r.wireType != wireType {
+		r.err = fmt.Errorf("field %d has wire type %d, expected %d", r.field, r.wireType, wireType)
+	}
+	return r.err == nil
+}
+
+func (r *protoReader) varint() uint64 {
+	if !r.expect(wireVarint) {
+		return 0
+	}
+	return r.uvarint()
+}
+
+func (r *protoReader) fixed(size int) []byte {
+	if len(r.data) < size {
+		r.err = errMalformed
+		return nil
+	}
+	b := r.data[:size]
+	r.data = r.data[size:]
+	return b
+}
+
+func (r *protoReader) double() float64 {
+	if !r.expect(wireFixed64) {
+		return 0
+	}
+	b := r.fixed(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+func (r *protoReader) bytes() []byte {
+	if !r.expect(wireBytes) {
+		return nil
+	}
+	n := r.uvarint()
+	if r.err == nil && n > uint64(len(r.data)) {
+		r.err = errMalformed
+	}
+	if r.err != nil {
+		return nil
+	}
+	return r.fixed(int(n))
+}
+
+func (r *protoReader) string() string {
+	return string(r.bytes())
+}
+
+// skip consumes the value of a field the message does not know.
+func (r *protoReader) skip() {
+	switch r.wireType {
+	case wireVarint:
+		r.uvarint()
+	case wireFixed64:
+		r.fixed(8)
+	case wireBytes:
+		r.bytes()
+	case wireFixed32:
+		r.fixed(4)
+	default:
+		r.err = fmt.Errorf("field %d has unsupported wire type %d", r.field, r.wireType)
+	}
+}
+
+// protoSuggestRequest is the SuggestRequest message.
+type protoSuggestRequest struct {
+	Prefix    string
+	K         int32
+	Algorithm string
+	Context   []string
+}
+
+func (m protoSuggestRequest) marshal() []byte {
+	var w protoWriter
+	w.string(1, m.Prefix)
+	w.int(2, int64(m.K))
+	w.string(3, m.Algorithm)
+	for _, word := range m.Context {
+		w.bytes(4, []byte(word))
+	}
+	return w
+}
+
+func (m *protoSuggestRequest) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		switch r.field {
+		case 1:
+			m.Prefix = r.string()
+		case 2:
+			m.K = int32(r.varint())
+		case 3:
+			m.Algorithm = r.string()
+		case 4:
+			m.Context = append(m.Context, r.string())
+		default:
+			r.skip()
+		}
+	}
+	return r.err
+}
+
+// protoSuggestion is the Suggestion message.
+type protoSuggestion struct {
+	Word       string
+	Score      float64
+	Frequency  int64
+	MatchStart int32
+	MatchEnd   int32
+}
+
+func (m protoSuggestion) marshal() []byte {
+	var w protoWriter
+	w.string(1, m.Word)
+	w.double(2, m.Score)
+	w.int(3, m.Frequency)
+	w.int(4, int64(m.MatchStart))
+	w.int(5, int64(m.MatchEnd))
+	return w
+}
+
+func (m *protoSuggestion) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		switch r.field {
+		case 1:
+			m.Word = r.string()
+		case 2:
+			m.Score = r.double()
+		case 3:
+			m.Frequency = int64(r.varint())
+		case 4:
+			m.MatchStart = int32(r.varint())
+		case 5:
+			m.MatchEnd = int32(r.varint())
+		default:
+			r.skip()
+		}
+	}
+	return r.err
+}
+
+// protoSuggestResponse is the SuggestResponse message.
+type protoSuggestResponse struct {
+	Prefix      string
+	Algorithm   string
+	Suggestions []protoSuggestion
+}
+
+func (m protoSuggestResponse) marshal() []byte {
+	var w protoWriter
+	w.string(1, m.Prefix)
+	w.string(2, m.Algorithm)
+	for _, s := range m.Suggestions {
+		w.bytes(3, s.marshal())
+	}
+	return w
+}
+
+func (m *protoSuggestResponse) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		switch r.field {
+		case 1:
+			m.Prefix = r.string()
+		case 2:
+			m.Algorithm = r.string()
+		case 3:
+			var s protoSuggestion
+			if b := r.bytes(); r.err == nil {
+				r.err = s.unmarshal(b)
+			}
+			m.Suggestions = append(m.Suggestions, s)
+		default:
+			r.skip()
+		}
+	}
+	return r.err
+}
+
+// protoInsertRequest is the InsertRequest message.
+type protoInsertRequest struct {
+	Word   string
+	Weight int64
+}
+
+func (m protoInsertRequest) marshal() []byte {
+	var w protoWriter
+	w.string(1, m.Word)
+	w.int(2, m.Weight)
+	return w
+}
+
+func (m *protoInsertRequest) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		switch r.field {
+		case 1:
+			m.Word = r.string()
+		case 2:
+			m.Weight = int64(r.varint())
+		default:
+			r.skip()
+		}
+	}
+	return r.err
+}
+
+// protoBatchInsertRequest is the BatchInsertRequest message.
+type protoBatchInsertRequest struct {
+	Words []string
+}
+
+func (m protoBatchInsertRequest) marshal() []byte {
+	var w protoWriter
+	for _, word := range m.Words {
+		w.bytes(1, []byte(word))
+	}
+	return w
+}
+
+func (m *protoBatchInsertRequest) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		if r.field == 1 {
+			m.Words = append(m.Words, r.string())
+		} else {
+			r.skip()
+		}
+	}
+	return r.err
+}
+
+// protoInsertResponse is the InsertResponse message.
+type protoInsertResponse struct {
+	Inserted int64
+}
+
+func (m protoInsertResponse) marshal() []byte {
+	var w protoWriter
+	w.int(1, m.Inserted)
+	return w
+}
+
+func (m *protoInsertResponse) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		if r.field == 1 {
+			m.Inserted = int64(r.varint())
+		} else {
+			r.skip()
+		}
+	}
+	return r.err
+}
+
+// protoStatsRequest is the StatsRequest message, which has no fields.
+type protoStatsRequest struct{}
+
+func (m *protoStatsRequest) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		r.skip()
+	}
+	return r.err
+}
+
+// protoStatsResponse is the StatsResponse message. Its map field travels as
+// repeated entries of key 1 and value 2.
+type protoStatsResponse struct {
+	Words       map[string]int64
+	CacheHits   int64
+	CacheMisses int64
+}
+
+func (m protoStatsResponse) marshal() []byte {
+	var w protoWriter
+	for _, name := range sortedNames(m.Words) {
+		var entry protoWriter
+		entry.string(1, name)
+		entry.int(2, m.Words[name])
+		w.bytes(1, entry)
+	}
+	w.int(2, m.CacheHits)
+	w.int(3, m.CacheMisses)
+	return w
+}
+
+func (m *protoStatsResponse) unmarshal(data []byte) error {
+	r := protoReader{data: data}
+	for r.next() {
+		switch r.field {
+		case 1:
+			entry := protoReader{data: r.bytes()}
+			var name string
+			var words int64
+			for r.err == nil && entry.next() {
+				switch entry.field {
+				case 1:
+					name = entry.string()
+				case 2:
+					words = int64(entry.varint())
+				default:
+					entry.skip()
+				}
+			}
+			if r.err == nil {
+				r.err = entry.err
+			}
+			if m.Words == nil {
+				m.Words = make(map[string]int64)
+			}
+			m.Words[name] = words
+		case 2:
+			m.CacheHits = int64(r.varint())
+		case 3:
+			m.CacheMisses = int64(r.varint())
+		default:
+			r.skip()
+		}
+	}
+	return r.err
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+// -----------------------------------------
+// Query Log
+// -----------------------------------------
+
+// SelectRequest is the body accepted by POST /select: what the user had
+// typed, the previous words separated by spaces, and the suggestion they
+// accepted, or "" if they accepted none.
+type SelectRequest struct {
+	Prefix   string `json:"prefix"`
+	Context  string `json:"context,omitempty"`
+	Selected string `json:"selected,omitempty"`
+}
+
+// EnableQueryLog adds POST /select, which records each request in a query
+// log over the Algorithm_1 trie, keeping the events of the last window and
+// appending them to sink if it is not nil. It returns the log, whose
+// selections only reach the trie when it is folded, for example with
+// FoldEvery.
+func (s *Server) EnableQueryLog(window time.Duration, sink io.Writer) *autocomplete.QueryLog {
+	s.queryLog = autocomplete.NewQueryLog(s.a1, window).WithSink(sink)
+	s.mux.HandleFunc("POST /select", s.handleSelect)
+	return s.queryLog
+}
+
+func (s *Server) handleSelect(w http.ResponseWriter, r *http.Request) {
+	var req SelectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"invalid JSON body: " + err.Error()})
+		return
+	}
+	err := s.queryLog.Record(autocomplete.QueryEvent{
+		Context:  strings.Fields(req.Context),
+		Prefix:   req.Prefix,
+		Selected: req.Selected,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{"recording the selection: " + err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]float64{"selectionRate": s.queryLog.SelectionRate()})
+}
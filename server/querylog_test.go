@@ -0,0 +1,39 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelect(t *testing.T) {
+	s := newTestServer()
+	var sink bytes.Buffer
+	log := s.EnableQueryLog(time.Hour, &sink)
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/select", strings.NewReader(`{"prefix": "hel", "selected": "helicopter"}`)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+	}
+	if n := log.Fold(); n != 3 {
+		t.Errorf("Expected 3 selections folded, got %d", n)
+	}
+	if code, resp := suggest(t, s, "/suggest?prefix=hel&k=1"); code != http.StatusOK || resp.Suggestions[0].Word != "helicopter" {
+		t.Errorf("Expected helicopter to rank first after the selections, got %+v", resp)
+	}
+	if got := strings.Count(sink.String(), "\n"); got != 3 {
+		t.Errorf("Expected 3 logged events, got %d", got)
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/select", strings.NewReader("{")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad body, got %d", rec.Code)
+	}
+}
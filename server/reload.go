@@ -0,0 +1,141 @@
+package server
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+// -----------------------------------------
+// Hot Reload
+// -----------------------------------------
+
+// Reload swaps in freshly built tries. Queries already running finish on
+// the old ones; none is dropped. The server takes ownership of a1 and a2,
+// and whatever the old tries learned since they were built, from POST
+// /words or selections, is gone.
+func (s *Server) Reload(a1 *autocomplete.TrieA1, a2 *autocomplete.TriesA2) {
+	s.a1.Swap(a1)
+	s.a2.Swap(a2)
+}
+
+// Reloader describes how WatchReload rebuilds the server's tries.
+type Reloader struct {
+	// Build returns the new tries, or nil ones to keep the old tries while
+	// still reloading the indexes of WithIndexes.
+	Build func() (*autocomplete.TrieA1, *autocomplete.TriesA2, error)
+	// Paths are polled every Interval; a change of size or modification
+	// time triggers a reload. A non-positive Interval only reloads on
+	// SIGHUP.
+	Paths    []string
+	Interval time.Duration
+	// Logf reports each reload and failure. Nil uses log.Printf.
+	Logf func(format string, args ...any)
+}
+
+// WatchReload reloads on SIGHUP and, if r has an interval, whenever one of
+// r.Paths changes, until the returned stop function is called. A reload
+// rebuilds the tries with r.Build and swaps them in with Reload, then
+// re-reads every index of WithIndexes that is in use. A failed build keeps
+// the old tries serving.
+func (s *Server) WatchReload(r Reloader) (stop func()) {
+	logf := r.Logf
+	if logf == nil {
+		logf = log.Printf
+	}
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	var tick <-chan time.Time
+	var ticker *time.Ticker
+	if r.Interval > 0 {
+		ticker = time.NewTicker(r.Interval)
+		tick = ticker.C
+	}
+	seen := statFiles(r.Paths)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-hangup:
+				logf("Reloading on SIGHUP")
+			case <-tick:
+				current := statFiles(r.Paths)
+				if sameFiles(seen, current) {
+					continue
+				}
+				seen = current
+				logf("Reloading after a file changed")
+			case <-done:
+				return
+			}
+			s.reload(r, logf)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(hangup)
+			if ticker != nil {
+				ticker.Stop()
+			}
+			close(done)
+		})
+	}
+}
+
+func (s *Server) reload(r Reloader, logf func(format string, args ...any)) {
+	if r.Build != nil {
+		a1, a2, err := r.Build()
+		switch {
+		case err != nil:
+			logf("Reload failed, still serving the old tries: %v", err)
+		case a1 != nil && a2 != nil:
+			s.Reload(a1, a2)
+			logf("Reloaded %d words", a1.Len())
+		}
+	}
+	if s.indexes == nil {
+		return
+	}
+	for _, name := range s.indexes.Names() {
+		if err := s.indexes.Reload(name); err != nil {
+			logf("Reload of index %s failed, still serving the old one: %v", name, err)
+		}
+	}
+}
+
+// fileState is what statFiles notices changing about a file.
+type fileState struct {
+	size    int64
+	modTime time.Time
+	missing bool
+}
+
+func statFiles(paths []string) []fileState {
+	states := make([]fileState, len(paths))
+	for i, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			states[i].missing = true
+			continue
+		}
+		states[i] = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+	return states
+}
+
+func sameFiles(a, b []fileState) bool {
+	for i := range a {
+		if a[i].size != b[i].size || !a[i].modTime.Equal(b[i].modTime) || a[i].missing != b[i].missing {
+			return false
+		}
+	}
+	return true
+}
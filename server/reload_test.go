@@ -0,0 +1,119 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+func buildTries(words []string) (*autocomplete.TrieA1, *autocomplete.TriesA2) {
+	a1 := autocomplete.NewTrieA1()
+	a1.BuildFromCorpus(words)
+	a2 := autocomplete.NewTriesA2()
+	for _, w := range words {
+		a2.Insert(w)
+	}
+	return a1, a2
+}
+
+func TestReloadKeepsServing(t *testing.T) {
+	s := newTestServer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if code, resp := suggest(t, s, "prefix=h"); code != http.StatusOK || len(resp.Suggestions) == 0 {
+					t.Errorf("Expected suggestions throughout the reload, got %d %+v", code, resp)
+					return
+				}
+			}
+		}()
+	}
+	s.Reload(buildTries([]string{"harbour", "harbour", "hat"}))
+	wg.Wait()
+
+	for _, algorithm := range []string{AlgorithmContextual, AlgorithmFrequency} {
+		_, resp := suggest(t, s, "prefix=h&algorithm="+algorithm)
+		if len(resp.Suggestions) != 2 || resp.Suggestions[0].Word != "harbour" {
+			t.Errorf("%s: expected the reloaded words, got %+v", algorithm, resp.Suggestions)
+		}
+	}
+}
+
+func TestWatchReloadOnFileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer()
+	var mu sync.Mutex
+	var logged []string
+	stop := s.WatchReload(Reloader{
+		Build: func() (*autocomplete.TrieA1, *autocomplete.TriesA2, error) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(data) == 0 {
+				return nil, nil, errors.New("empty corpus")
+			}
+			a1, a2 := buildTries(strings.Fields(string(data)))
+			return a1, a2, nil
+		},
+		Paths:    []string{path},
+		Interval: 5 * time.Millisecond,
+		Logf: func(format string, args ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			logged = append(logged, fmt.Sprintf(format, args...))
+		},
+	})
+	defer stop()
+
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if _, resp := suggest(t, s, "prefix=ha"); len(resp.Suggestions) > 0 && resp.Suggestions[0].Word == want {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("Expected %s after the reload", want)
+	}
+
+	if err := os.WriteFile(path, []byte("harbour hat hat"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("hat")
+
+	// A failed build keeps the reloaded tries.
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		failed := strings.Contains(strings.Join(logged, "\n"), "Reload failed")
+		mu.Unlock()
+		if failed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the failed reload to be logged, got %q", logged)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	waitFor("hat")
+}
@@ -0,0 +1,238 @@
+// Package server exposes the autocomplete tries over HTTP.
+//
+//	GET  /suggest?prefix=he&k=5&algorithm=a1&context=hello
+//	POST /words   {"words": ["hello", "world"]}
+//	GET  /metrics (Prometheus text format)
+//	GET  /indexes and /indexes/{name}/suggest, with WithIndexes
+//	POST /select  {"prefix": "he", "selected": "hello"}, with EnableQueryLog
+//	GET  /experiment/suggest?prefix=he&user=42, with EnableExperiment
+//
+// The same mux serves the gRPC service of proto/autocomplete.proto under
+// /autocomplete.v1.Autocomplete/, to HTTP/2 clients over TLS.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	autocomplete "auto-complete"
+)
+
+// defaultK is the number of suggestions returned when the request has no k,
+// unless WithDefaults changes it.
+const defaultK = 5
+
+// Algorithm names accepted by the algorithm query parameter.
+const (
+	AlgorithmContextual = "a1"
+	AlgorithmFrequency  = "a2"
+)
+
+// Server serves suggestions from both algorithms. It is safe for concurrent
+// use: queries share a read lock and insertions take the write lock.
+type Server struct {
+	a1      *autocomplete.Concurrent
+	a2      *autocomplete.Concurrent
+	mux     *http.ServeMux
+	metrics *metrics
+
+	// k and algorithm answer requests that leave them out.
+	k         int
+	algorithm string
+
+	// indexes are the named datasets served under /indexes, if any.
+	indexes *autocomplete.IndexSet
+
+	// queryLog records the selections posted to /select, if enabled.
+	queryLog *autocomplete.QueryLog
+
+	// variants are the Autocompleters added with Register, and experiment
+	// the A/B comparison between two of them, if one was enabled.
+	variants     map[string]*autocomplete.Concurrent
+	experimentMu sync.Mutex
+	experiment   *experiment
+}
+
+// New returns a Server backed by the given tries. The server takes ownership
+// of them; they must not be used directly afterwards.
+func New(a1 *autocomplete.TrieA1, a2 *autocomplete.TriesA2) *Server {
+	s := &Server{
+		a1:      autocomplete.NewConcurrent(a1),
+		a2:      autocomplete.NewConcurrent(a2),
+		mux:     http.NewServeMux(),
+		metrics: newMetrics(),
+
+		k:         defaultK,
+		algorithm: AlgorithmContextual,
+	}
+	s.mux.HandleFunc("GET /suggest", s.handleSuggest)
+	s.mux.HandleFunc("POST /words", s.handleWords)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	s.mux.HandleFunc("POST /"+grpcService+"/{method}", s.handleGRPC)
+	return s
+}
+
+// WithDefaults sets the number of suggestions returned to requests without
+// k and the algorithm, a1 or a2, that answers /suggest requests without one.
+func (s *Server) WithDefaults(algorithm string, k int) *Server {
+	s.algorithm, s.k = algorithm, k
+	return s
+}
+
+// DecayEvery ages Algorithm_2's decayed weights and both tries' recorded
+// selections every interval until the returned stop function is called. It
+// only matters for tries built with WithDecay or WithClickThrough.
+func (s *Server) DecayEvery(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.a1.Update(func(a autocomplete.Autocompleter) {
+					a.(*autocomplete.TrieA1).DecaySelections()
+				})
+				s.a2.Update(func(a autocomplete.Autocompleter) {
+					a.(*autocomplete.TriesA2).Decay()
+					a.(*autocomplete.TriesA2).DecaySelections()
+				})
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// SuggestResponse is the body returned by GET /suggest. Each suggestion
+// carries the span of it that matched the prefix.
+type SuggestResponse struct {
+	Prefix      string                               `json:"prefix"`
+	Context     string                               `json:"context,omitempty"`
+	Algorithm   string                               `json:"algorithm"`
+	Suggestions []autocomplete.HighlightedSuggestion `json:"suggestions"`
+}
+
+// WordsRequest is the body accepted by POST /words. The words are inserted
+// into both tries, and their order feeds Algorithm_1's bigram table.
+type WordsRequest struct {
+	Words []string `json:"words"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleSuggest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prefix := query.Get("prefix")
+
+	k := s.k
+	if raw := query.Get("k"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"k must be a non-negative integer"})
+			return
+		}
+		k = parsed
+	}
+
+	algorithm := query.Get("algorithm")
+	if algorithm == "" {
+		algorithm = s.algorithm
+	}
+	var trie *autocomplete.Concurrent
+	switch algorithm {
+	case AlgorithmContextual:
+		trie = s.a1
+	case AlgorithmFrequency:
+		trie = s.a2
+	default:
+		writeJSON(w, http.StatusBadRequest, errorResponse{"algorithm must be a1 or a2"})
+		return
+	}
+
+	context := query.Get("context")
+	start := time.Now()
+	suggestions, err := complete(r.Context(), trie, prefix, context, k)
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{"query abandoned: " + err.Error()})
+		return
+	}
+	s.metrics.observeQuery(algorithm, time.Since(start), len(suggestions))
+	writeJSON(w, http.StatusOK, SuggestResponse{
+		Prefix:      prefix,
+		Context:     context,
+		Algorithm:   algorithm,
+		Suggestions: autocomplete.HighlightMatches(prefix, suggestions),
+	})
+}
+
+func (s *Server) handleWords(w http.ResponseWriter, r *http.Request) {
+	var req WordsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"invalid JSON body: " + err.Error()})
+		return
+	}
+
+	s.insertWords(req.Words)
+	writeJSON(w, http.StatusOK, map[string]int{"inserted": len(req.Words)})
+}
+
+// insertWords adds words to both tries, in order so that Algorithm_1 learns
+// their bigrams.
+func (s *Server) insertWords(words []string) {
+	s.a1.Update(func(a autocomplete.Autocompleter) {
+		a.(*autocomplete.TrieA1).BuildFromCorpus(words)
+	})
+	s.a2.Update(func(a autocomplete.Autocompleter) {
+		for _, word := range words {
+			a.Insert(word)
+		}
+	})
+	s.metrics.observeInsert(AlgorithmContextual, len(words))
+	s.metrics.observeInsert(AlgorithmFrequency, len(words))
+}
+
+// complete queries trie for up to k completions of prefix. Only Algorithm_1
+// ranks by the previous word, context; other tries ignore it. Queries
+// without one stop early once ctx is done.
+func complete(ctx context.Context, trie *autocomplete.Concurrent, prefix, context string, k int) ([]autocomplete.Suggestion, error) {
+	var suggestions []autocomplete.Suggestion
+	var err error
+	trie.View(func(a autocomplete.Autocompleter) {
+		switch a := a.(type) {
+		case *autocomplete.TrieA1:
+			if context != "" {
+				suggestions = a.AutocompleteWithContext(context, prefix, k)
+			} else {
+				suggestions, err = a.AutocompleteCtx(ctx, prefix, k)
+			}
+		case *autocomplete.TriesA2:
+			suggestions, err = a.AutocompleteCtx(ctx, prefix, k)
+		default:
+			suggestions = a.Autocomplete(prefix, k)
+		}
+	})
+	return suggestions, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	autocomplete "auto-complete"
+)
+
+func newTestServer() *Server {
+	corpus := []string{"hello", "hello", "hell", "helicopter", "world"}
+	a1 := autocomplete.NewTrieA1()
+	a1.BuildFromCorpus(corpus)
+	a2 := autocomplete.NewTriesA2()
+	for _, w := range corpus {
+		a2.Insert(w)
+	}
+	return New(a1, a2)
+}
+
+func suggest(t *testing.T, s *Server, query string) (int, SuggestResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/suggest?"+query, nil))
+	var resp SuggestResponse
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("Invalid JSON response: %v", err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func TestSuggest(t *testing.T) {
+	s := newTestServer()
+
+	for _, algorithm := range []string{AlgorithmContextual, AlgorithmFrequency} {
+		code, resp := suggest(t, s, "prefix=he&k=2&algorithm="+algorithm)
+		if code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", algorithm, code)
+		}
+		if len(resp.Suggestions) != 2 || resp.Suggestions[0].Word != "hello" {
+			t.Errorf("%s: unexpected suggestions %v", algorithm, resp.Suggestions)
+		}
+		if resp.Algorithm != algorithm || resp.Prefix != "he" {
+			t.Errorf("%s: unexpected response metadata %+v", algorithm, resp)
+		}
+		if h := resp.Suggestions[0].Highlight; h.Start != 0 || h.End != 2 {
+			t.Errorf("%s: expected the first 2 characters highlighted, got %+v", algorithm, h)
+		}
+	}
+
+	code, resp := suggest(t, s, "prefix=xyz")
+	if code != http.StatusOK || resp.Suggestions == nil || len(resp.Suggestions) != 0 {
+		t.Errorf("Expected an empty suggestion list for an unknown prefix, got %d %v", code, resp.Suggestions)
+	}
+}
+
+func TestSuggestWithContext(t *testing.T) {
+	s := newTestServer()
+
+	// "helicopter" only ever followed "hell", so it outranks the more
+	// frequent "hello" in that context.
+	code, resp := suggest(t, s, "prefix=he&k=1&context=hell")
+	if code != http.StatusOK || len(resp.Suggestions) != 1 || resp.Suggestions[0].Word != "helicopter" {
+		t.Errorf("Expected helicopter after hell, got %d %v", code, resp.Suggestions)
+	}
+	if resp.Context != "hell" {
+		t.Errorf("Expected the context to be echoed, got %q", resp.Context)
+	}
+
+	_, resp = suggest(t, s, "prefix=he&k=1&context=hell&algorithm="+AlgorithmFrequency)
+	if len(resp.Suggestions) != 1 || resp.Suggestions[0].Word != "hello" {
+		t.Errorf("Expected Algorithm_2 to ignore the context, got %v", resp.Suggestions)
+	}
+}
+
+func TestSuggestBadRequest(t *testing.T) {
+	s := newTestServer()
+	for _, query := range []string{"prefix=he&k=-1", "prefix=he&k=abc", "prefix=he&algorithm=a9"} {
+		if code, _ := suggest(t, s, query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, code)
+		}
+	}
+}
+
+func TestPostWords(t *testing.T) {
+	s := newTestServer()
+
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"words": ["zebra", "zeal", "zebra"]}`)
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/words", body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	for _, algorithm := range []string{AlgorithmContextual, AlgorithmFrequency} {
+		_, resp := suggest(t, s, "prefix=ze&algorithm="+algorithm)
+		if len(resp.Suggestions) != 2 || resp.Suggestions[0].Word != "zebra" {
+			t.Errorf("%s: expected the inserted words, got %v", algorithm, resp.Suggestions)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/words", strings.NewReader("not json")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid body, got %d", rec.Code)
+	}
+}
+
+func TestSuggestCancelled(t *testing.T) {
+	s := newTestServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/suggest?prefix=he", nil).WithContext(ctx))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 for a cancelled request, got %d", rec.Code)
+	}
+}
+
+func TestSuggestDefaults(t *testing.T) {
+	s := newTestServer().WithDefaults(AlgorithmFrequency, 1)
+
+	code, resp := suggest(t, s, "prefix=hel")
+	if code != http.StatusOK || resp.Algorithm != AlgorithmFrequency || len(resp.Suggestions) != 1 {
+		t.Errorf("Expected one a2 suggestion by default, got %d %+v", code, resp)
+	}
+	if _, resp := suggest(t, s, "prefix=hel&k=3&algorithm=a1"); resp.Algorithm != AlgorithmContextual || len(resp.Suggestions) != 3 {
+		t.Errorf("Expected the request to override the defaults, got %+v", resp)
+	}
+}
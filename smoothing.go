@@ -0,0 +1,134 @@
+package autocomplete
+
+// -----------------------------------------
+// Context Smoothing
+// -----------------------------------------
+
+// Smoothing controls how contextual probabilities are estimated for
+// completions that rarely or never followed the context. The zero value uses
+// raw relative counts, so a completion never seen after the context scores 0.
+//
+// Both smoothing methods back off to unigram frequency: probability mass
+// taken from the seen followers is handed to every completion in proportion
+// to its frequency, so unseen completions still rank sensibly among
+// themselves.
+type Smoothing struct {
+	method smoothingMethod
+	param  float64
+}
+
+type smoothingMethod int
+
+const (
+	noSmoothing smoothingMethod = iota
+	addKSmoothing
+	kneserNeySmoothing
+)
+
+// defaultDiscount is the Kneser-Ney discount used when none in (0, 1] is given.
+const defaultDiscount = 0.75
+
+// AddK adds k pseudo-counts per completion to every context:
+//
+//	P(w | ctx) = (c(ctx, w) + k*V*P(w)) / (c(ctx) + k*V)
+//
+// where V is the number of completions and P(w) the word's unigram
+// probability among them. With equal frequencies this is classic add-k
+// smoothing. A non-positive k disables smoothing.
+func AddK(k float64) Smoothing {
+	if k <= 0 {
+		return Smoothing{}
+	}
+	return Smoothing{method: addKSmoothing, param: k}
+}
+
+// KneserNey uses interpolated Kneser-Ney smoothing: discount is subtracted
+// from every seen count and the freed mass is spread by continuation
+// probability, which favours words that follow many different contexts over
+// words that are merely frequent after one. The continuation distribution is
+// itself discounted and interpolated with unigram frequency. A discount
+// outside (0, 1] uses 0.75.
+//
+// Continuation counts are gathered from the whole bigram table on each
+// query, so this costs one pass over the table per contextual lookup.
+func KneserNey(discount float64) Smoothing {
+	if discount <= 0 || discount > 1 {
+		discount = defaultDiscount
+	}
+	return Smoothing{method: kneserNeySmoothing, param: discount}
+}
+
+// SetSmoothing selects how contextual probabilities are smoothed. It affects
+// AutocompleteWithContext and AutocompleteContext; context-free ranking is
+// unchanged.
+func (t *TrieA1) SetSmoothing(s Smoothing) {
+	t.smoothing = s
+	t.cache.clear()
+}
+
+// apply returns the contextual probability of each completion given the
+// follower counts of its context and the completions' unigram probabilities.
+func (s Smoothing) apply(bigramTable map[string]map[string]int, contextData map[string]int, completions []Suggestion, unigram []float64) []float64 {
+	total := float64(contextData["_total"])
+	probabilities := make([]float64, len(completions))
+
+	switch s.method {
+	case addKSmoothing:
+		pseudo := s.param * float64(len(completions))
+		for i, completion := range completions {
+			probabilities[i] = (float64(contextData[completion.Word]) + pseudo*unigram[i]) / (total + pseudo)
+		}
+
+	case kneserNeySmoothing:
+		d := s.param
+		continuation := continuationProbabilities(bigramTable, completions, unigram, d)
+		followers := float64(len(contextData) - 1) // exclude "_total"
+		lambda := d * followers / total
+		for i, completion := range completions {
+			probabilities[i] = max(float64(contextData[completion.Word])-d, 0)/total + lambda*continuation[i]
+		}
+
+	default:
+		for i, completion := range completions {
+			probabilities[i] = float64(contextData[completion.Word]) / total
+		}
+	}
+	return probabilities
+}
+
+// continuationProbabilities estimates, for each completion, how likely it is
+// to appear as a novel continuation: the number of distinct contexts it
+// followed, discounted by d and interpolated with unigram. Completions that
+// never followed anything fall back to unigram frequency alone.
+func continuationProbabilities(bigramTable map[string]map[string]int, completions []Suggestion, unigram []float64, d float64) []float64 {
+	index := make(map[string]int, len(completions))
+	for i, completion := range completions {
+		index[completion.Word] = i
+	}
+
+	contexts := make([]float64, len(completions))
+	for _, followers := range bigramTable {
+		for word, count := range followers {
+			if i, ok := index[word]; ok && word != "_total" && count > 0 {
+				contexts[i]++
+			}
+		}
+	}
+
+	total, seen := 0.0, 0.0
+	for _, n := range contexts {
+		total += n
+		if n > 0 {
+			seen++
+		}
+	}
+	if total == 0 {
+		return unigram
+	}
+
+	probabilities := make([]float64, len(completions))
+	for i, n := range contexts {
+		probabilities[i] = (max(n-d, 0) + d*seen*unigram[i]) / total
+	}
+	return probabilities
+}
@@ -0,0 +1,51 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSmoothing(t *testing.T) {
+	// After "the" only "cat" was seen. "cow" is the more frequent of the
+	// unseen completions, but "car" follows more distinct words.
+	trie := buildAlg1Trie([]string{
+		"the", "cat", "the", "cat",
+		"a", "cow", "a", "cow", "a", "cow",
+		"my", "car", "his", "car",
+	})
+
+	got := trie.AutocompleteWithContext("the", "c", 3)
+	if got[1].Score != 0 || got[2].Score != 0 {
+		t.Errorf("Expected unseen completions to score 0 without smoothing, got %v", got)
+	}
+
+	trie.SetSmoothing(AddK(0.5))
+	got = trie.AutocompleteWithContext("the", "c", 3)
+	if want := []string{"cat", "cow", "car"}; !reflect.DeepEqual(Words(got), want) {
+		t.Errorf("AddK: expected %v, got %v", want, got)
+	}
+
+	trie.SetSmoothing(KneserNey(0.75))
+	got = trie.AutocompleteWithContext("the", "c", 3)
+	if want := []string{"cat", "car", "cow"}; !reflect.DeepEqual(Words(got), want) {
+		t.Errorf("KneserNey: expected %v, got %v", want, got)
+	}
+	if got[2].Score <= 0 || got[1].Score <= got[2].Score {
+		t.Errorf("KneserNey: expected car to outscore cow and both to be positive, got %v", got)
+	}
+
+	trie.SetSmoothing(Smoothing{})
+	got = trie.AutocompleteWithContext("the", "c", 3)
+	if got[1].Score != 0 {
+		t.Errorf("Expected the zero Smoothing to disable smoothing, got %v", got)
+	}
+}
+
+func TestSmoothingKeepsContextFreeRanking(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "help", "hello", "help", "hero"})
+	want := trie.Autocomplete("he", 3)
+	trie.SetSmoothing(KneserNey(0.5))
+	if got := trie.Autocomplete("he", 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected smoothing not to affect Autocomplete, got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,319 @@
+package autocomplete
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// -----------------------------------------
+// Binary Snapshots
+// -----------------------------------------
+
+// Snapshot layout, all integers as varints:
+//
+//	magic   "ACA1" or "ACA2"
+//	node    uvarint(frequency<<1 | isEnd), uvarint(#children),
+//	        then per child in rune order: varint(rune), node
+//	counts  uvarint(#contexts), then per context: string(context),
+//	        uvarint(#entries), per entry: string(word), uvarint(count)
+//	string  uvarint(len), bytes
+//
+// An Algorithm_1 snapshot is the magic, the root node, the bigram counts,
+// uvarint(n-gram order), the n-gram counts, uvarint(phrase length) and, if
+// that is not zero, the root node of the phrase index. Algorithm_2 has only
+// the magic and the root node.
+const (
+	magicA1 = "ACA1"
+	magicA2 = "ACA2"
+)
+
+// maxSnapshotString bounds string lengths read from a snapshot so a corrupt
+// length cannot trigger a huge allocation.
+const maxSnapshotString = 1 << 20
+
+var errBadSnapshot = errors.New("autocomplete: invalid snapshot")
+
+type snapshotWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func (s *snapshotWriter) uvarint(v uint64) {
+	if s.err == nil {
+		_, s.err = s.w.Write(s.buf[:binary.PutUvarint(s.buf[:], v)])
+	}
+}
+
+func (s *snapshotWriter) varint(v int64) {
+	if s.err == nil {
+		_, s.err = s.w.Write(s.buf[:binary.PutVarint(s.buf[:], v)])
+	}
+}
+
+func (s *snapshotWriter) string(v string) {
+	s.uvarint(uint64(len(v)))
+	if s.err == nil {
+		_, s.err = s.w.WriteString(v)
+	}
+}
+
+func (s *snapshotWriter) node(frequency int, isEnd bool, children int) {
+	flags := uint64(frequency) << 1
+	if isEnd {
+		flags |= 1
+	}
+	s.uvarint(flags)
+	s.uvarint(uint64(children))
+}
+
+func (s *snapshotWriter) counts(table map[string]map[string]int) {
+	contexts := sortedKeys(table)
+	s.uvarint(uint64(len(contexts)))
+	for _, context := range contexts {
+		followers := table[context]
+		s.string(context)
+		s.uvarint(uint64(len(followers)))
+		for _, word := range sortedKeys(followers) {
+			s.string(word)
+			s.uvarint(uint64(followers[word]))
+		}
+	}
+}
+
+type snapshotReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (s *snapshotReader) uvarint() uint64 {
+	if s.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(s.r)
+	s.setErr(err)
+	return v
+}
+
+func (s *snapshotReader) varint() int64 {
+	if s.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(s.r)
+	s.setErr(err)
+	return v
+}
+
+func (s *snapshotReader) string() string {
+	n := s.uvarint()
+	if s.err != nil {
+		return ""
+	}
+	if n > maxSnapshotString {
+		s.err = errBadSnapshot
+		return ""
+	}
+	buf := make([]byte, n)
+	_, err := io.ReadFull(s.r, buf)
+	s.setErr(err)
+	return string(buf)
+}
+
+func (s *snapshotReader) node() (frequency int, isEnd bool, children int) {
+	flags := s.uvarint()
+	children = int(s.uvarint())
+	return int(flags >> 1), flags&1 == 1, children
+}
+
+func (s *snapshotReader) counts() map[string]map[string]int {
+	table := make(map[string]map[string]int)
+	contexts := int(s.uvarint())
+	for i := 0; i < contexts && s.err == nil; i++ {
+		context := s.string()
+		entries := int(s.uvarint())
+		followers := make(map[string]int)
+		for j := 0; j < entries && s.err == nil; j++ {
+			word := s.string()
+			followers[word] = int(s.uvarint())
+		}
+		table[context] = followers
+	}
+	return table
+}
+
+func (s *snapshotReader) magic(want string) {
+	buf := make([]byte, len(want))
+	if _, err := io.ReadFull(s.r, buf); err != nil {
+		s.setErr(err)
+		return
+	}
+	if string(buf) != want {
+		s.err = fmt.Errorf("%w: expected magic %q, got %q", errBadSnapshot, want, buf)
+	}
+}
+
+func (s *snapshotReader) setErr(err error) {
+	if err == nil || s.err != nil {
+		return
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	s.err = fmt.Errorf("%w: %v", errBadSnapshot, err)
+}
+
+// Save writes the trie, its bigram and n-gram tables and its phrase index
+// to w. Ranking settings such as stopwords or the cache are not part of the
+// snapshot.
+func (t *TrieA1) Save(w io.Writer) error {
+	s := &snapshotWriter{w: bufio.NewWriter(w)}
+	s.w.WriteString(magicA1)
+
+	var writeNode func(*TrieNodeA1)
+	writeNode = func(node *TrieNodeA1) {
+		s.node(node.frequency, node.isEnd, node.childCount())
+		for _, char := range node.childRunes() {
+			s.varint(int64(char))
+			writeNode(node.child(char))
+		}
+	}
+	writeNode(t.root)
+
+	s.counts(t.bigramTable)
+	s.uvarint(uint64(t.ngramOrder))
+	s.counts(t.ngramTable)
+	s.uvarint(uint64(t.phraseLength))
+	if t.phraseLength > 0 {
+		s.nodeA2(t.phrases.root)
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+	return s.w.Flush()
+}
+
+// Load replaces the trie and its context tables with a snapshot written by
+// Save. On error the trie is left unchanged. Payloads are not part of
+// snapshots, so those attached before are dropped.
+func (t *TrieA1) Load(r io.Reader) error {
+	s := &snapshotReader{r: bufio.NewReader(r)}
+	s.magic(magicA1)
+
+	size := 0
+	var readNode func() *TrieNodeA1
+	readNode = func() *TrieNodeA1 {
+		node := t.newNode()
+		frequency, isEnd, children := s.node()
+		node.frequency, node.isEnd = frequency, isEnd
+		if isEnd {
+			size++
+		}
+		for i := 0; i < children && s.err == nil; i++ {
+			char := rune(s.varint())
+			node.setChild(char, readNode())
+		}
+		node.recount()
+		return node
+	}
+	root := readNode()
+
+	bigramTable := s.counts()
+	ngramOrder := int(s.uvarint())
+	ngramTable := s.counts()
+	phraseLength := int(s.uvarint())
+	var phrases *TriesA2
+	if phraseLength > 0 {
+		phrases = NewTriesA2()
+		phrases.root = s.nodeA2(&phrases.size)
+	}
+
+	if s.err != nil {
+		return s.err
+	}
+	t.root, t.bigramTable, t.size = root, bigramTable, size
+	t.ngramTable, t.ngramOrder = ngramTable, ngramOrder
+	t.phrases, t.phraseLength = phrases, phraseLength
+	t.history = nil
+	t.payloads = nil
+	t.resetKeyIndexes()
+	t.unfinalize()
+	t.cache.clear()
+	return nil
+}
+
+// Save writes the trie to w.
+func (t *TriesA2) Save(w io.Writer) error {
+	s := &snapshotWriter{w: bufio.NewWriter(w)}
+	s.w.WriteString(magicA2)
+	s.nodeA2(t.root)
+
+	if s.err != nil {
+		return s.err
+	}
+	return s.w.Flush()
+}
+
+// Load replaces the trie with a snapshot written by Save. On error the trie
+// is left unchanged. Any recency window is reset, decayed weights restart
+// from the loaded frequencies and payloads attached before are dropped.
+func (t *TriesA2) Load(r io.Reader) error {
+	s := &snapshotReader{r: bufio.NewReader(r)}
+	s.magic(magicA2)
+	size := 0
+	root := s.nodeA2(&size)
+
+	if s.err != nil {
+		return s.err
+	}
+	t.root, t.size = root, size
+	t.payloads = nil
+	t.resetInfix()
+	if t.window != nil {
+		t.EnableWindow(len(t.window))
+	}
+	if t.halfLife > 0 {
+		t.WithDecay(t.halfLife)
+	}
+	return nil
+}
+
+func (s *snapshotWriter) nodeA2(node *NodeA2) {
+	s.node(node.frequency, node.isEndOfWord, len(node.children))
+	for _, char := range sortedKeys(node.children) {
+		s.varint(int64(char))
+		s.nodeA2(node.children[char])
+	}
+}
+
+// nodeA2 reads an Algorithm_2 subtree, adding the words in it to size.
+func (s *snapshotReader) nodeA2(size *int) *NodeA2 {
+	node := &NodeA2{children: make(map[rune]*NodeA2)}
+	frequency, isEnd, children := s.node()
+	node.frequency, node.isEndOfWord = frequency, isEnd
+	if isEnd {
+		*size++
+		node.words++
+	}
+	for i := 0; i < children && s.err == nil; i++ {
+		char := rune(s.varint())
+		child := s.nodeA2(size)
+		node.children[char] = child
+		node.words += child.words
+	}
+	return node
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[K rune | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,118 @@
+package autocomplete
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotRoundTripA1(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "héllo", "hell", "hell", "help", "hello", "world"}
+	original := NewTrieA1()
+	original.BuildFromCorpus(corpus)
+
+	var buf bytes.Buffer
+	if err := original.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := NewTrieA1()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.Len() != original.Len() {
+		t.Errorf("Expected %d words, got %d", original.Len(), loaded.Len())
+	}
+	if !reflect.DeepEqual(loaded.bigramTable, original.bigramTable) {
+		t.Errorf("Expected bigram table %v, got %v", original.bigramTable, loaded.bigramTable)
+	}
+	for _, prefix := range []string{"h", "he", "hé", "w", "x"} {
+		want, got := original.Autocomplete(prefix, 5), loaded.Autocomplete(prefix, 5)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("For prefix %q: expected %v, got %v", prefix, want, got)
+		}
+	}
+}
+
+func TestSnapshotRoundTripA2(t *testing.T) {
+	original := buildAlg2Trie([]string{"hello", "hello", "hello", "hell", "hell", "helicopter", "日本", "日本語"})
+
+	var first bytes.Buffer
+	if err := original.Save(&first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := NewTriesA2()
+	if err := loaded.Load(bytes.NewReader(first.Bytes())); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if err := loaded.Validate(); err != nil {
+		t.Errorf("Expected a valid trie after Load, got %v", err)
+	}
+	added, removed, changed := loaded.Diff(original)
+	if len(added)+len(removed)+len(changed) != 0 {
+		t.Errorf("Expected no differences, got added %v removed %v changed %v", added, removed, changed)
+	}
+
+	// Children are written in rune order, so snapshots are reproducible.
+	var second bytes.Buffer
+	if err := loaded.Save(&second); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("Expected identical snapshots of identical tries")
+	}
+}
+
+func TestLoadDropsPayloads(t *testing.T) {
+	var a1Snapshot, a2Snapshot bytes.Buffer
+	if err := buildAlg1Trie([]string{"go", "rust"}).Save(&a1Snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if err := buildAlg2Trie([]string{"go", "rust"}).Save(&a2Snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	a1 := NewTrieA1()
+	a1.InsertWithPayload("go", Payload{Category: "language"})
+	if err := a1.Load(&a1Snapshot); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := a1.Payload("go"); ok {
+		t.Errorf("Algorithm_1: expected Load to drop the payload of 'go'")
+	}
+
+	a2 := NewTriesA2()
+	a2.InsertWithPayload("go", Payload{Category: "language"})
+	if err := a2.Load(&a2Snapshot); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := a2.AutocompleteTagged("", 5, "language"); len(got) != 0 {
+		t.Errorf("Algorithm_2: expected no tagged words after Load, got %v", got)
+	}
+}
+
+func TestSnapshotRejectsBadInput(t *testing.T) {
+	var buf bytes.Buffer
+	if err := buildAlg2Trie([]string{"hello", "world"}).Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data := buf.Bytes()
+
+	trie := buildAlg1Trie([]string{"keep"})
+	if err := trie.Load(bytes.NewReader(data)); !errors.Is(err, errBadSnapshot) {
+		t.Errorf("Expected a magic mismatch error loading an Algorithm_2 snapshot, got %v", err)
+	}
+	if len(Words(trie.Autocomplete("k", 5))) != 1 {
+		t.Errorf("Expected the trie to be unchanged after a failed Load")
+	}
+
+	a2 := NewTriesA2()
+	if err := a2.Load(bytes.NewReader(data[:len(data)-1])); !errors.Is(err, errBadSnapshot) {
+		t.Errorf("Expected an error for a truncated snapshot, got %v", err)
+	}
+	if a2.Len() != 0 {
+		t.Errorf("Expected the trie to be unchanged after a failed Load")
+	}
+}
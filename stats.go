@@ -0,0 +1,133 @@
+package autocomplete
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// -----------------------------------------
+// Corpus Statistics
+// -----------------------------------------
+
+// statsTopWords is how many of the most frequent words Stats reports.
+const statsTopWords = 10
+
+// TrieStats summarizes what a trie holds, for checking that it was built
+// from the intended data.
+type TrieStats struct {
+	TotalWords  int // occurrences of all words: the sum of their frequencies
+	UniqueWords int
+	Nodes       int // including the root
+	MaxDepth    int // runes in the longest word
+	// TopWords are the most frequent words, most frequent first, with
+	// Frequency set and Score zero.
+	TopWords []Suggestion
+	// BigramContexts is the number of words with at least one follower and
+	// Bigrams the number of distinct word pairs. Both are zero for
+	// Algorithm_2.
+	BigramContexts int
+	Bigrams        int
+}
+
+// Stats walks the trie and summarizes it.
+func (t *TrieA1) Stats() TrieStats {
+	var stats TrieStats
+	var words []Suggestion
+	var walk func(*TrieNodeA1, []rune)
+	walk = func(node *TrieNodeA1, path []rune) {
+		stats.Nodes++
+		if node.isEnd {
+			words = append(words, Suggestion{Word: string(path), Frequency: node.frequency})
+			stats.MaxDepth = max(stats.MaxDepth, len(path))
+		}
+		node.eachChild(func(char rune, child *TrieNodeA1) {
+			walk(child, append(path, char))
+		})
+	}
+	walk(t.root, nil)
+	stats.summarize(words)
+
+	stats.BigramContexts = len(t.bigramTable)
+	for _, followers := range t.bigramTable {
+		stats.Bigrams += len(followers) - 1 // exclude "_total"
+	}
+	return stats
+}
+
+// Stats walks the trie and summarizes it.
+func (t *TriesA2) Stats() TrieStats {
+	var stats TrieStats
+	var words []Suggestion
+	var walk func(*NodeA2, []rune)
+	walk = func(node *NodeA2, path []rune) {
+		stats.Nodes++
+		if node.isEndOfWord {
+			words = append(words, Suggestion{Word: string(path), Frequency: node.frequency})
+			stats.MaxDepth = max(stats.MaxDepth, len(path))
+		}
+		for char, child := range node.children {
+			walk(child, append(path, char))
+		}
+	}
+	walk(t.root, nil)
+	stats.summarize(words)
+	return stats
+}
+
+// summarize fills in the word counts and top words from every word.
+func (s *TrieStats) summarize(words []Suggestion) {
+	s.UniqueWords = len(words)
+	for i, w := range words {
+		s.TotalWords += w.Frequency
+		words[i].Score = float64(w.Frequency)
+	}
+	s.TopWords = topK(words, statsTopWords)
+	for i := range s.TopWords {
+		s.TopWords[i].Score = 0
+	}
+}
+
+// ExportFrequencies writes every word and its frequency as word<TAB>count
+// lines, most frequent first and ties in lexicographic order. The output can
+// be read back with LoadFrequencyTSV.
+func (t *TrieA1) ExportFrequencies(w io.Writer) error {
+	var entries []Suggestion
+	var walk func(*TrieNodeA1, []rune)
+	walk = func(node *TrieNodeA1, path []rune) {
+		if node.isEnd {
+			entries = append(entries, Suggestion{Word: string(path), Frequency: node.frequency})
+		}
+		node.eachChild(func(char rune, child *TrieNodeA1) {
+			walk(child, append(path, char))
+		})
+	}
+	walk(t.root, nil)
+	return writeFrequencies(w, entries)
+}
+
+// ExportFrequencies writes every word and its all-time frequency as
+// word<TAB>count lines, most frequent first and ties in lexicographic
+// order. The output can be read back with LoadFrequencyTSV.
+func (t *TriesA2) ExportFrequencies(w io.Writer) error {
+	var entries []Suggestion
+	collectEntriesA2(t.root, "", &entries)
+	return writeFrequencies(w, entries)
+}
+
+func writeFrequencies(w io.Writer, entries []Suggestion) error {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Frequency != entries[j].Frequency {
+			return entries[i].Frequency > entries[j].Frequency
+		}
+		return entries[i].Word < entries[j].Word
+	})
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(bw, "%s\t%d\n", e.Word, e.Frequency); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,66 @@
+package autocomplete
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	corpus := []string{"hello", "hello", "hello", "hell", "hell", "hero", "world"}
+	a1 := buildAlg1Trie(corpus)
+	a2 := buildAlg2Trie(corpus)
+
+	for name, stats := range map[string]TrieStats{"a1": a1.Stats(), "a2": a2.Stats()} {
+		// Nodes: root, h, e, l, l, o, r, o, w, o, r, l, d.
+		if stats.TotalWords != 7 || stats.UniqueWords != 4 || stats.Nodes != 13 || stats.MaxDepth != 5 {
+			t.Errorf("%s: unexpected counts %+v", name, stats)
+		}
+		want := []Suggestion{{Word: "hello", Frequency: 3}, {Word: "hell", Frequency: 2}, {Word: "hero", Frequency: 1}, {Word: "world", Frequency: 1}}
+		if len(stats.TopWords) != len(want) {
+			t.Fatalf("%s: expected top words %v, got %v", name, want, stats.TopWords)
+		}
+		for i := range want {
+			if stats.TopWords[i] != want[i] {
+				t.Errorf("%s: top word %d is %v, expected %v", name, i, stats.TopWords[i], want[i])
+			}
+		}
+	}
+
+	// Pairs: hello>hello, hello>hell, hell>hell, hell>hero, hero>world.
+	if stats := a1.Stats(); stats.BigramContexts != 3 || stats.Bigrams != 5 {
+		t.Errorf("Expected 3 bigram contexts and 5 bigrams, got %d and %d", stats.BigramContexts, stats.Bigrams)
+	}
+	if stats := a2.Stats(); stats.BigramContexts != 0 || stats.Bigrams != 0 {
+		t.Errorf("Expected no bigrams for Algorithm_2, got %+v", stats)
+	}
+
+	empty := NewTrieA1().Stats()
+	if empty.Nodes != 1 || empty.UniqueWords != 0 || len(empty.TopWords) != 0 {
+		t.Errorf("Expected only the root for an empty trie, got %+v", empty)
+	}
+}
+
+func TestExportFrequencies(t *testing.T) {
+	corpus := []string{"hero", "hello", "hell", "hello", "hell", "hello"}
+	want := "hello\t3\nhell\t2\nhero\t1\n"
+
+	var a1, a2 bytes.Buffer
+	if err := buildAlg1Trie(corpus).ExportFrequencies(&a1); err != nil {
+		t.Fatal(err)
+	}
+	if err := buildAlg2Trie(corpus).ExportFrequencies(&a2); err != nil {
+		t.Fatal(err)
+	}
+	if a1.String() != want || a2.String() != want {
+		t.Errorf("Expected %q from both, got %q and %q", want, a1.String(), a2.String())
+	}
+
+	reloaded := NewTriesA2()
+	if err := (FrequencyLoader{Comma: '\t'}).LoadReader(strings.NewReader(want), reloaded); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Frequency("hello") != 3 || reloaded.Len() != 3 {
+		t.Errorf("Expected the export to load back, got %d words", reloaded.Len())
+	}
+}
@@ -0,0 +1,114 @@
+package autocomplete
+
+// -----------------------------------------
+// Stopword Filtering
+// -----------------------------------------
+
+// EnglishStopwords is a small list of filler words that tend to dominate
+// frequency rankings without helping completion quality.
+var EnglishStopwords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "by", "for", "from",
+	"in", "is", "it", "of", "on", "or", "that", "the", "to", "was", "with",
+}
+
+// SetStopwords enables stopword filtering for subsequent Insert and
+// BuildBigramTable calls. Stopwords are never added to the trie, and bigrams
+// with a stopword on either side are not counted. Filtering is off by
+// default; passing an empty list turns it off again. Words already inserted
+// are not removed.
+func (t *TrieA1) SetStopwords(words []string) {
+	t.cache.clear()
+	if len(words) == 0 {
+		t.stopwords = nil
+		return
+	}
+	t.stopwords = make(map[string]bool, len(words))
+	for _, w := range words {
+		t.stopwords[w] = true
+	}
+}
+
+// ContextStopwordMode is how the context model treats the stop words set
+// with WithContextStopwords.
+type ContextStopwordMode int
+
+const (
+	// SkipStopwords counts no bigram with a stop word on either side, as if
+	// the text were cut at every stop word.
+	SkipStopwords ContextStopwordMode = iota
+	// BridgeStopwords counts the words on either side of a run of stop
+	// words as neighbours, so "cup of tea" counts "tea" after "cup".
+	BridgeStopwords
+)
+
+// WithContextStopwords keeps words out of the context model without
+// removing them from the trie: they stay completions, but BuildBigramTable,
+// BuildNgramTable, the corpus builders and ObserveWord never count them as a
+// context or a follower, and mode decides whether the words around them
+// still count as neighbours. Context words given to queries are treated the
+// same way. Counts already made are kept; an empty list turns it off again.
+func (t *TrieA1) WithContextStopwords(words []string, mode ContextStopwordMode) *TrieA1 {
+	t.contextStopwords = nil
+	if len(words) > 0 {
+		t.contextStopwords = make(map[string]bool, len(words))
+		for _, w := range words {
+			t.contextStopwords[w] = true
+		}
+	}
+	t.bridgeStopwords = mode == BridgeStopwords
+	t.cache.clear()
+	return t
+}
+
+// contextWords normalizes corpus as Insert would, leaving "" for rejected
+// words and stopwords, which no context spans.
+func (t *TrieA1) contextWords(corpus []string) []string {
+	words := make([]string, len(corpus))
+	for i, word := range corpus {
+		if word, ok := normalizeWord(word, t.strict); ok && !t.stopwords[word] {
+			words[i] = word
+		}
+	}
+	return words
+}
+
+// contextFollowers returns the word that follows each of words as its
+// context, or "" if none does: after a break, for a context stop word, or
+// when the next word is one and is not bridged.
+func (t *TrieA1) contextFollowers(words []string) []string {
+	followers := make([]string, len(words))
+	next := ""
+	for i := len(words) - 1; i >= 0; i-- {
+		word := words[i]
+		switch {
+		case word == "":
+			next = ""
+		case t.contextStopwords[word]:
+			if !t.bridgeStopwords {
+				next = ""
+			}
+		default:
+			followers[i] = next
+			next = word
+		}
+	}
+	return followers
+}
+
+// contextSequence returns words as the context model sees them: context
+// stop words are dropped when bridged and become breaks otherwise.
+func (t *TrieA1) contextSequence(words []string) []string {
+	if t.contextStopwords == nil {
+		return words
+	}
+	sequence := make([]string, 0, len(words))
+	for _, word := range words {
+		switch {
+		case !t.contextStopwords[word]:
+			sequence = append(sequence, word)
+		case !t.bridgeStopwords:
+			sequence = append(sequence, "")
+		}
+	}
+	return sequence
+}
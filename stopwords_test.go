@@ -0,0 +1,95 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStopwordFiltering(t *testing.T) {
+	corpus := []string{
+		"the", "theory", "of", "the", "thermal", "the", "engine",
+		"the", "theory", "the", "end", "the", "thermal", "the",
+	}
+	prefix := "th"
+
+	unfiltered := buildAlg1Trie(corpus)
+	suggestions := unfiltered.Autocomplete(prefix, 1)
+	if len(suggestions) == 0 || suggestions[0].Word != "the" {
+		t.Fatalf("Expected 'the' to dominate without filtering, got %v", suggestions)
+	}
+
+	filtered := NewTrieA1()
+	filtered.SetStopwords(EnglishStopwords)
+	for _, w := range corpus {
+		filtered.Insert(w)
+	}
+	filtered.BuildBigramTable(corpus)
+
+	for _, s := range filtered.Autocomplete(prefix, 5) {
+		if s.Word == "the" {
+			t.Errorf("Stopword 'the' should not be suggested when filtering is enabled")
+		}
+	}
+	if _, exists := filtered.bigramTable["the"]; exists {
+		t.Errorf("Stopword 'the' should not be counted as context")
+	}
+	for context, followers := range filtered.bigramTable {
+		if followers["the"] > 0 || followers["of"] > 0 {
+			t.Errorf("Context '%s' should not count stopword followers", context)
+		}
+	}
+}
+
+func TestContextStopwords(t *testing.T) {
+	corpus := []string{"cup", "of", "tea", "and", "a", "cup", "of", "coffee"}
+	stop := []string{"of", "and", "a"}
+
+	skip := NewTrieA1().WithContextStopwords(stop, SkipStopwords)
+	skip.BuildFromCorpus(corpus)
+	if skip.Len() != 6 {
+		t.Errorf("Expected stop words to stay in the trie, got %d words", skip.Len())
+	}
+	if len(skip.bigramTable) != 0 {
+		t.Errorf("Expected no bigrams when every pair has a stop word, got %v", skip.bigramTable)
+	}
+
+	bridge := NewTrieA1().WithContextStopwords(stop, BridgeStopwords)
+	bridge.BuildFromCorpus(corpus)
+	want := map[string]map[string]int{
+		"cup": {"tea": 1, "coffee": 1, "_total": 2},
+		"tea": {"cup": 1, "_total": 1},
+	}
+	if !reflect.DeepEqual(bridge.bigramTable, want) {
+		t.Errorf("Expected %v, got %v", want, bridge.bigramTable)
+	}
+
+	parallel := NewTrieA1().WithContextStopwords(stop, BridgeStopwords)
+	parallel.BuildFromCorpusParallel(corpus, 3)
+	if !reflect.DeepEqual(parallel.bigramTable, want) {
+		t.Errorf("Expected the parallel build to count %v, got %v", want, parallel.bigramTable)
+	}
+
+	// A query's context bridges or skips the stop word too.
+	if got := bridge.lookupContext([]string{"cup", "of"}); !reflect.DeepEqual(got, want["cup"]) {
+		t.Errorf("Expected the context 'cup of' to use the counts after cup, got %v", got)
+	}
+	if got := skip.lookupContext([]string{"cup", "of"}); got != nil {
+		t.Errorf("Expected no context after a skipped stop word, got %v", got)
+	}
+}
+
+func TestContextStopwordsNgramsAndStreams(t *testing.T) {
+	trie := NewTrieA1().WithContextStopwords([]string{"the"}, BridgeStopwords)
+	trie.BuildNgramTable([]string{"over", "the", "moon", "over", "the", "hill"}, 3)
+	if got := trie.ngramTable["moon"+ngramSeparator+"over"]["hill"]; got != 1 {
+		t.Errorf("Expected 'moon over' -> hill across the stop word, got %d", got)
+	}
+
+	stream := NewTrieA1().WithContextStopwords([]string{"the"}, SkipStopwords)
+	for _, w := range []string{"over", "the", "moon"} {
+		stream.ObserveWord(w)
+	}
+	if len(stream.bigramTable) != 0 || stream.Len() != 3 {
+		t.Errorf("Expected no bigrams and 3 words, got %v and %d", stream.bigramTable, stream.Len())
+	}
+}
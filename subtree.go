@@ -0,0 +1,117 @@
+package autocomplete
+
+import "container/heap"
+
+// -----------------------------------------
+// Subtree Pruning
+// -----------------------------------------
+
+// recount recomputes the node's subtree counters from its own word and its
+// children's counters.
+func (n *TrieNodeA1) recount() {
+	n.words, n.maxFrequency, n.frequencySum = 0, 0, 0
+	if n.isEnd {
+		n.words, n.maxFrequency, n.frequencySum = 1, n.frequency, n.frequency
+	}
+	n.eachChild(func(_ rune, child *TrieNodeA1) {
+		n.words += child.words
+		n.maxFrequency = max(n.maxFrequency, child.maxFrequency)
+		n.frequencySum += child.frequencySum
+	})
+}
+
+// countInsert updates the subtree counters on the path of word, which is in
+// the trie, after weight was added to its frequency, making it frequency.
+// isNew says whether word was added by it.
+func (t *TrieA1) countInsert(word string, weight, frequency int, isNew bool) {
+	count := func(node *TrieNodeA1) {
+		if isNew {
+			node.words++
+		}
+		node.maxFrequency = max(node.maxFrequency, frequency)
+		node.frequencySum += weight
+	}
+	node := t.root
+	count(node)
+	for _, char := range word {
+		node = node.child(char)
+		count(node)
+	}
+}
+
+// canPrune reports whether a query ranks plainly by frequency, with nothing
+// but the frequencies of the completions deciding their order and scores.
+// Only then can the traversal skip the subtrees whose best word cannot make
+// the top k.
+func (t *TrieA1) canPrune(q query, alternates []Suggestion) bool {
+	return len(alternates) == 0 &&
+		q.only == nil && t.lookupContext(q.context) == nil &&
+		t.maxScan == 0 && t.options.MaxDepth == 0 && t.options.MinFrequency == 0 &&
+		!t.filter.active() &&
+		t.frequencyTransform == nil && t.matchRatioWeight == 0 &&
+		t.scorer == nil && t.clicks == nil && !t.graphemes
+}
+
+// subtreeEntry is a pending subtree, ranked by its best word, or a word,
+// ranked by its frequency, in a best-first traversal.
+type subtreeEntry struct {
+	node      *TrieNodeA1 // nil for a word
+	path      string
+	frequency int
+}
+
+// subtreeQueue pops the entry with the highest frequency first and, among
+// equals, the one with the smallest path. Every word under a subtree's path
+// sorts after the path, so words come out in rank order.
+type subtreeQueue []subtreeEntry
+
+func (q subtreeQueue) Len() int { return len(q) }
+func (q subtreeQueue) Less(i, j int) bool {
+	if q[i].frequency != q[j].frequency {
+		return q[i].frequency > q[j].frequency
+	}
+	return q[i].path < q[j].path
+}
+func (q subtreeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *subtreeQueue) Push(x any)   { *q = append(*q, x.(subtreeEntry)) }
+func (q *subtreeQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+// topCompletions returns the k most frequent words under node, the node of
+// prefix, scored as scoreCompletions would score them among all the words
+// under node. It visits subtrees best first by their highest frequency and
+// stops once k words are out, so branches that cannot reach the top k are
+// never walked. It stops early, reporting false, when cancel says so.
+func (t *TrieA1) topCompletions(node *TrieNodeA1, prefix string, k int, cancel *canceller) ([]Suggestion, bool) {
+	if k <= 0 || node.words == 0 {
+		return nil, true
+	}
+	results := make([]Suggestion, 0, min(k, node.words))
+	total := float64(node.frequencySum)
+	queue := subtreeQueue{{node: node, path: prefix, frequency: node.maxFrequency}}
+	for len(queue) > 0 && len(results) < k {
+		if cancel.stop() {
+			return results, false
+		}
+		entry := heap.Pop(&queue).(subtreeEntry)
+		if entry.node == nil {
+			results = append(results, Suggestion{
+				Word:      entry.path,
+				Score:     float64(entry.frequency) / total,
+				Frequency: entry.frequency,
+			})
+			continue
+		}
+		if entry.node.isEnd {
+			heap.Push(&queue, subtreeEntry{path: entry.path, frequency: entry.node.frequency})
+		}
+		entry.node.eachChild(func(char rune, child *TrieNodeA1) {
+			heap.Push(&queue, subtreeEntry{node: child, path: entry.path + string(char), frequency: child.maxFrequency})
+		})
+	}
+	return results, true
+}
@@ -0,0 +1,112 @@
+package autocomplete
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// checkSubtreeCounters fails unless every node under node carries the
+// counters recount would give it.
+func checkSubtreeCounters(t *testing.T, node *TrieNodeA1, path string) {
+	t.Helper()
+	want := *node
+	node.eachChild(func(char rune, child *TrieNodeA1) {
+		checkSubtreeCounters(t, child, path+string(char))
+	})
+	want.recount()
+	if node.words != want.words || node.maxFrequency != want.maxFrequency || node.frequencySum != want.frequencySum {
+		t.Errorf("For node %q: expected counters %d/%d/%d, got %d/%d/%d", path,
+			want.words, want.maxFrequency, want.frequencySum,
+			node.words, node.maxFrequency, node.frequencySum)
+	}
+}
+
+func TestTopCompletionsMatchFullScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	corpus := make([]string, 3000)
+	for i := range corpus {
+		// Few letters and skewed frequencies give deep shared prefixes and
+		// plenty of ties.
+		corpus[i] = fmt.Sprintf("%c%c%d", 'a'+rng.Intn(3), 'a'+rng.Intn(3), rng.Intn(40)*rng.Intn(40))
+	}
+	trie := buildAlg1Trie(corpus)
+
+	for _, prefix := range []string{"", "a", "ab", "ca1", "bb3", "zz"} {
+		for _, k := range []int{1, 3, 10, 100, 5000} {
+			got := trie.Autocomplete(prefix, k)
+			var want []Suggestion
+			if node := trie.searchPrefix(prefix); node != nil {
+				want = topK(trie.scoreCompletions(prefix, trie.collectCompletions(node, []rune(prefix))), k)
+			}
+			if len(got) == 0 && len(want) == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("For prefix %q and k %d: expected %v, got %v", prefix, k, want, got)
+			}
+		}
+	}
+}
+
+func TestSubtreeCountersStayCurrent(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "hell", "help", "helium", "hero"})
+	checkSubtreeCounters(t, trie.root, "")
+
+	trie.Promote("help")
+	trie.Promote("help")
+	trie.Promote("help")
+	checkSubtreeCounters(t, trie.root, "")
+	if got := trie.Autocomplete("he", 1); got[0].Word != "help" {
+		t.Errorf("Expected 'help' first after promotion, got %v", got)
+	}
+
+	trie.Delete("help")
+	trie.Delete("hero")
+	checkSubtreeCounters(t, trie.root, "")
+	if trie.root.maxFrequency != 3 {
+		t.Errorf("Expected the highest frequency to fall to 3, got %d", trie.root.maxFrequency)
+	}
+
+	var buf bytes.Buffer
+	if err := trie.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewTrieA1().WithCompactNodes(true)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	checkSubtreeCounters(t, loaded.root, "")
+
+	data, err := trie.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshalled := NewTrieA1()
+	if err := unmarshalled.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkSubtreeCounters(t, unmarshalled.root, "")
+
+	parallel := NewTrieA1()
+	parallel.BuildFromCorpusParallel(syntheticCorpus(2000), 4)
+	checkSubtreeCounters(t, parallel.root, "")
+}
+
+func TestPruningStepsAsideForRanking(t *testing.T) {
+	trie := buildAlg1Trie([]string{"hello", "hello", "hello", "help", "helpful", "helpful"})
+
+	if !trie.canPrune(query{}, nil) {
+		t.Fatalf("Expected a plain frequency query to prune")
+	}
+	trie.SetMatchRatioWeight(1)
+	if trie.canPrune(query{}, nil) {
+		t.Errorf("Expected the match ratio weight to need a full scan")
+	}
+	// With the prefix filling most of "help", it outranks "hello".
+	if got := trie.Autocomplete("hel", 1); got[0].Word != "help" {
+		t.Errorf("Expected 'help' first with a match ratio weight, got %v", got)
+	}
+}
@@ -0,0 +1,152 @@
+package autocomplete
+
+import (
+	"unicode/utf8"
+
+	"auto-complete/corpus"
+)
+
+// -----------------------------------------
+// Suffix Completion
+// -----------------------------------------
+
+// CompleteSuffix returns the characters that complete prefix to its best
+// match, e.g. "copter" for "heli" when "helicopter" is the best completion.
+//
+// The best completion is the one with the highest frequency. If two or more
+// completions share that highest frequency the result is ambiguous and
+// CompleteSuffix returns false, as it does when nothing matches. A prefix that
+// is itself the best completion yields an empty suffix and true.
+func (t *TriesA2) CompleteSuffix(prefix string) (string, bool) {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return "", false
+	}
+
+	var entries []Suggestion
+	collectEntriesA2(node, prefix, &entries)
+	entries = t.filter.apply(entries)
+
+	best := -1
+	ambiguous := false
+	for i, e := range entries {
+		switch {
+		case best == -1 || e.Frequency > entries[best].Frequency:
+			best = i
+			ambiguous = false
+		case e.Frequency == entries[best].Frequency:
+			ambiguous = true
+		}
+	}
+	if best == -1 || ambiguous {
+		return "", false
+	}
+	return entries[best].Word[len(prefix):], true
+}
+
+// CommonPrefix returns prefix extended by every character all its
+// completions share, as shell tab completion does: with "hello" and "help"
+// stored, "he" extends to "hel", and with "helicopter" alone, "heli"
+// extends to the whole word. It stops at a stored word, since that word is
+// itself a completion, and returns prefix unchanged when nothing completes
+// it. The walk follows the trie's single-child chain, so it costs the
+// length of the result, not the number of completions, unless a blocklist
+// or filter is set: then only the completions it lets through count and
+// they are compared one by one. With WithGraphemes the extension ends on a
+// whole user-perceived character.
+func (t *TrieA1) CommonPrefix(prefix string) string {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return prefix
+	}
+	if t.filter.active() {
+		shown := t.filter.apply(t.collectCompletions(node, []rune(prefix)))
+		return commonPrefixOf(prefix, Words(shown), t.graphemes)
+	}
+	extended := []byte(prefix)
+	for !node.isEnd && node.childCount() == 1 {
+		node.eachChild(func(char rune, child *TrieNodeA1) {
+			extended = utf8.AppendRune(extended, char)
+			node = child
+		})
+	}
+	if !t.graphemes || node.isEnd {
+		return string(extended)
+	}
+	return wholeGraphemes(prefix, string(extended), node.childRunes())
+}
+
+// CommonPrefix returns prefix extended by every character all its
+// completions share, as TrieA1.CommonPrefix does.
+func (t *TriesA2) CommonPrefix(prefix string) string {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return prefix
+	}
+	if t.filter.active() {
+		var entries []Suggestion
+		collectEntriesA2(node, prefix, &entries)
+		return commonPrefixOf(prefix, Words(t.filter.apply(entries)), t.graphemes)
+	}
+	extended := []byte(prefix)
+	for !node.isEndOfWord && len(node.children) == 1 {
+		for char, child := range node.children {
+			extended = utf8.AppendRune(extended, char)
+			node = child
+		}
+	}
+	if !t.graphemes || node.isEndOfWord {
+		return string(extended)
+	}
+	return wholeGraphemes(prefix, string(extended), sortedKeys(node.children))
+}
+
+// commonPrefixOf returns the longest string, ending on a rune boundary,
+// that starts every one of words, which all start with prefix, or prefix
+// when there are no words. With graphemes it ends on a grapheme boundary
+// too.
+func commonPrefixOf(prefix string, words []string, graphemes bool) string {
+	if len(words) == 0 {
+		return prefix
+	}
+	first := words[0]
+	end := len(first)
+	for _, word := range words[1:] {
+		n := 0
+		for n < end && n < len(word) && first[n] == word[n] {
+			n++
+		}
+		end = n
+	}
+	for end > len(prefix) && end < len(first) && !utf8.RuneStart(first[end]) {
+		end--
+	}
+	common := first[:end]
+	if !graphemes {
+		return common
+	}
+	var next []rune
+	for _, word := range words {
+		if word == common {
+			return common
+		}
+		char, _ := utf8.DecodeRuneInString(word[end:])
+		next = append(next, char)
+	}
+	return wholeGraphemes(prefix, common, next)
+}
+
+// wholeGraphemes shortens extended, which starts with prefix and branches
+// into the runes of next, to its last grapheme boundary past prefix that
+// holds whichever branch follows, so an extension never splits a base
+// letter from its combining marks.
+func wholeGraphemes(prefix, extended string, next []rune) string {
+	end := len(extended)
+	for _, char := range next {
+		text := extended + string(char)
+		for end > len(prefix) && !corpus.IsGraphemeBoundary(text, end) {
+			end--
+		}
+	}
+	return extended[:end]
+}
@@ -0,0 +1,86 @@
+package autocomplete
+
+import "testing"
+
+func TestCompleteSuffixUnambiguous(t *testing.T) {
+	trie := buildAlg2Trie([]string{"helicopter", "hello", "hero"})
+
+	suffix, ok := trie.CompleteSuffix("heli")
+	if !ok || suffix != "copter" {
+		t.Errorf("Expected ('copter', true), got ('%s', %v)", suffix, ok)
+	}
+}
+
+func TestCompleteSuffixTieBrokenByFrequency(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello", "hello", "help"})
+
+	suffix, ok := trie.CompleteSuffix("hel")
+	if !ok || suffix != "lo" {
+		t.Errorf("Expected ('lo', true), got ('%s', %v)", suffix, ok)
+	}
+
+	// Equal frequencies leave no single best completion.
+	trie = buildAlg2Trie([]string{"hello", "help"})
+	if suffix, ok := trie.CompleteSuffix("hel"); ok {
+		t.Errorf("Expected ambiguous result, got ('%s', true)", suffix)
+	}
+}
+
+func TestCompleteSuffixFullWord(t *testing.T) {
+	trie := buildAlg2Trie([]string{"hello"})
+
+	suffix, ok := trie.CompleteSuffix("hello")
+	if !ok || suffix != "" {
+		t.Errorf("Expected ('', true), got ('%s', %v)", suffix, ok)
+	}
+
+	if _, ok := trie.CompleteSuffix("xyz"); ok {
+		t.Errorf("Expected no completion for unknown prefix")
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	words := []string{"hello", "help", "helicopter", "world", "wordle"}
+	tests := []struct {
+		prefix, want string
+	}{
+		{"h", "hel"},
+		{"hel", "hel"},
+		{"heli", "helicopter"},
+		{"hell", "hello"},
+		{"w", "wor"},
+		{"x", "x"},
+		{"", ""},
+	}
+	a1, a2 := buildAlg1Trie(words), buildAlg2Trie(words)
+	for _, test := range tests {
+		if got := a1.CommonPrefix(test.prefix); got != test.want {
+			t.Errorf("Expected A1 to extend %q to %q, got %q", test.prefix, test.want, got)
+		}
+		if got := a2.CommonPrefix(test.prefix); got != test.want {
+			t.Errorf("Expected A2 to extend %q to %q, got %q", test.prefix, test.want, got)
+		}
+	}
+
+	// A stored word stops the extension, even with longer completions.
+	if got := buildAlg2Trie([]string{"he", "hero"}).CommonPrefix("h"); got != "he" {
+		t.Errorf("Expected to stop at the word 'he', got %q", got)
+	}
+}
+
+func TestCommonPrefixWholeGraphemes(t *testing.T) {
+	// "e" followed by two different combining marks shares only the "e".
+	words := []string{"cafe\u0301s", "cafe\u0300s"}
+	a1 := buildAlg1Trie(words)
+	if got := a1.CommonPrefix("caf"); got != "cafe" {
+		t.Errorf("Expected %q without graphemes, got %q", "cafe", got)
+	}
+	a1.WithGraphemes(true)
+	if got := a1.CommonPrefix("caf"); got != "caf" {
+		t.Errorf("Expected the bare 'e' to be dropped with graphemes, got %q", got)
+	}
+	a2 := buildAlg2Trie(words).WithGraphemes(true)
+	if got := a2.CommonPrefix("ca"); got != "caf" {
+		t.Errorf("Expected %q with graphemes, got %q", "caf", got)
+	}
+}
@@ -0,0 +1,75 @@
+package autocomplete
+
+import "sort"
+
+// -----------------------------------------
+// Frequency Tiers
+// -----------------------------------------
+
+// Tier is a named group of completions with similar popularity.
+type Tier struct {
+	Name  string
+	Words []string
+}
+
+// Tier names, from most to least frequent.
+const (
+	TierHigh   = "high"
+	TierMedium = "medium"
+	TierLow    = "low"
+)
+
+// AutocompleteTiered returns the top k completions of prefix grouped into
+// high, medium and low frequency tiers, in that order. Empty tiers are left
+// out.
+//
+// Tier boundaries come from the candidate set itself: each word gets the
+// mid-rank percentile of its frequency among all completions of prefix
+// (words with lower frequency plus half of those with equal frequency). The
+// top third of percentiles is high, the middle third medium and the rest low.
+// Words within a tier keep their frequency order.
+func (t *TriesA2) AutocompleteTiered(prefix string, k int) []Tier {
+	node := t.searchPrefix(prefix)
+	if node == nil {
+		return nil
+	}
+
+	var entries []Suggestion
+	collectEntriesA2(node, prefix, &entries)
+	entries = t.filter.apply(entries)
+	sort.Slice(entries, func(i, j int) bool { return rankBefore(entries[i], entries[j]) })
+
+	n := len(entries)
+	tiers := []Tier{{Name: TierHigh}, {Name: TierMedium}, {Name: TierLow}}
+	for i, e := range entries[:clampK(k, n)] {
+		// entries is sorted descending, so count the run of equal frequencies
+		// around i and everything after it.
+		first, last := i, i
+		for first > 0 && entries[first-1].Frequency == e.Frequency {
+			first--
+		}
+		for last < n-1 && entries[last+1].Frequency == e.Frequency {
+			last++
+		}
+		lower := n - 1 - last
+		equal := last - first + 1
+		percentile := (float64(lower) + 0.5*float64(equal)) / float64(n)
+
+		switch {
+		case percentile >= 2.0/3.0:
+			tiers[0].Words = append(tiers[0].Words, e.Word)
+		case percentile >= 1.0/3.0:
+			tiers[1].Words = append(tiers[1].Words, e.Word)
+		default:
+			tiers[2].Words = append(tiers[2].Words, e.Word)
+		}
+	}
+
+	var result []Tier
+	for _, tier := range tiers {
+		if len(tier.Words) > 0 {
+			result = append(result, tier)
+		}
+	}
+	return result
+}
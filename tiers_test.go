@@ -0,0 +1,49 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAutocompleteTiered(t *testing.T) {
+	var corpus []string
+	add := func(word string, times int) {
+		for i := 0; i < times; i++ {
+			corpus = append(corpus, word)
+		}
+	}
+	add("hello", 100)
+	add("help", 90)
+	add("here", 80)
+	add("hero", 12)
+	add("herb", 10)
+	add("heat", 8)
+	add("heap", 1)
+	add("heal", 1)
+	add("heel", 1)
+	trie := buildAlg2Trie(corpus)
+
+	tiers := trie.AutocompleteTiered("he", 10)
+	want := []Tier{
+		{Name: TierHigh, Words: []string{"hello", "help", "here"}},
+		{Name: TierMedium, Words: []string{"hero", "herb", "heat"}},
+		{Name: TierLow, Words: []string{"heal", "heap", "heel"}},
+	}
+	if !reflect.DeepEqual(tiers, want) {
+		t.Errorf("Unexpected tiers:\n got  %v\n want %v", tiers, want)
+	}
+
+	// Truncating to k drops lower tiers entirely but keeps the boundaries.
+	tiers = trie.AutocompleteTiered("he", 4)
+	want = []Tier{
+		{Name: TierHigh, Words: []string{"hello", "help", "here"}},
+		{Name: TierMedium, Words: []string{"hero"}},
+	}
+	if !reflect.DeepEqual(tiers, want) {
+		t.Errorf("Unexpected tiers with k=4:\n got  %v\n want %v", tiers, want)
+	}
+
+	if tiers := trie.AutocompleteTiered("xyz", 10); len(tiers) != 0 {
+		t.Errorf("Expected no tiers for unknown prefix, got %v", tiers)
+	}
+}
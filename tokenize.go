@@ -0,0 +1,31 @@
+package autocomplete
+
+import "auto-complete/corpus"
+
+// -----------------------------------------
+// Corpus Tokenization
+// -----------------------------------------
+
+// WithTokenizer makes BuildFromCorpus, BuildFromCorpusParallel and
+// BuildBigramTable run every corpus entry through tokenizer first, so an
+// entry may be a whole line of text and the words counted are the tokens
+// it yields, stemmed or filtered as the tokenizer chains. Insert still takes
+// words as given. A nil tokenizer counts corpus entries as they are, the
+// default.
+func (t *TrieA1) WithTokenizer(tokenizer corpus.Tokenizer) *TrieA1 {
+	t.tokenizer = tokenizer
+	return t
+}
+
+// tokenize returns the tokens of every entry of corpus in order, or corpus
+// itself without a tokenizer.
+func (t *TrieA1) tokenize(entries []string) []string {
+	if t.tokenizer == nil {
+		return entries
+	}
+	var tokens []string
+	for _, entry := range entries {
+		tokens = append(tokens, t.tokenizer.Tokenize(entry)...)
+	}
+	return tokens
+}
@@ -0,0 +1,41 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+
+	"auto-complete/corpus"
+)
+
+func TestWithTokenizer(t *testing.T) {
+	lines := []string{"The cats chased the Dogs.", "Cats sleep."}
+	tokenizer := corpus.Chain(corpus.Words, corpus.Lowercase, corpus.Stopwords([]string{"the"}), corpus.Stem(corpus.EnglishStem))
+
+	trie := NewTrieA1().WithTokenizer(tokenizer)
+	trie.BuildFromCorpus(lines)
+	if got, want := Words(trie.Autocomplete("c", 5)), []string{"cat", "chased"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	// The stopword is gone before counting, so its neighbours become one.
+	if got := trie.bigramTable["chased"]["dog"]; got != 1 {
+		t.Errorf("Expected chased -> dog to be counted once, got %d", got)
+	}
+	// Bigrams run on across entries, as they do over a plain corpus.
+	if got := trie.bigramTable["dog"]["cat"]; got != 1 {
+		t.Errorf("Expected dog -> cat to be counted once, got %d", got)
+	}
+
+	parallel := NewTrieA1().WithTokenizer(tokenizer)
+	parallel.BuildFromCorpusParallel(lines, 2)
+	if !reflect.DeepEqual(parallel.Stats(), trie.Stats()) || !reflect.DeepEqual(parallel.bigramTable, trie.bigramTable) {
+		t.Errorf("Expected the parallel build to match BuildFromCorpus")
+	}
+}
+
+func TestBuildBigramTableUsesTokenizer(t *testing.T) {
+	trie := NewTrieA1().WithTokenizer(corpus.Chain(corpus.Whitespace, corpus.Lowercase))
+	trie.BuildBigramTable([]string{"New York", "new YORK"})
+	if got := trie.bigramTable["new"]["york"]; got != 2 {
+		t.Errorf("Expected new -> york twice, got %d", got)
+	}
+}
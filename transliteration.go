@@ -0,0 +1,63 @@
+package autocomplete
+
+import (
+	"strings"
+	"unicode"
+)
+
+// -----------------------------------------
+// Transliterated Queries
+// -----------------------------------------
+
+// Transliteration spells the letters of a script in Latin letters, such as
+// 'п' as "p". Keys are lower case.
+type Transliteration map[rune]string
+
+// RussianTransliteration romanizes Russian Cyrillic as it is commonly typed
+// on a Latin keyboard, so "привет" becomes "privet" and "щука" "shchuka".
+var RussianTransliteration = Transliteration{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// Romanize returns word in lower case with every letter the table knows
+// spelled in Latin letters and any other rune kept.
+func (tr Transliteration) Romanize(word string) string {
+	var b strings.Builder
+	for _, char := range word {
+		char = unicode.ToLower(char)
+		if latin, ok := tr[char]; ok {
+			b.WriteString(latin)
+		} else {
+			b.WriteRune(char)
+		}
+	}
+	return b.String()
+}
+
+// WithTransliteration lets queries be typed in Latin letters for words
+// written in another script: every word the table changes is also indexed
+// under its romanization, so with RussianTransliteration "priv" suggests
+// "привет". Transliterated matches rank like ordinary completions. The index
+// is built on the first query and rebuilt after words are added or removed.
+// A nil or empty table turns transliteration off.
+func (t *TrieA1) WithTransliteration(table Transliteration) *TrieA1 {
+	t.transliteration = nil
+	if len(table) > 0 {
+		t.transliteration = &keyIndex{
+			encode: func(word string) []string {
+				if latin := table.Romanize(word); latin != strings.ToLower(word) {
+					return []string{latin}
+				}
+				return nil
+			},
+			encodeQuery: func(prefix string) []string { return []string{table.Romanize(prefix)} },
+			weight:      1,
+		}
+	}
+	t.cache.clear()
+	return t
+}
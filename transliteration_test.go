@@ -0,0 +1,60 @@
+package autocomplete
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRomanize(t *testing.T) {
+	tests := map[string]string{
+		"привет": "privet",
+		"Щука":   "shchuka",
+		"объект": "obekt",
+		"hello":  "hello",
+	}
+	for word, want := range tests {
+		if got := RussianTransliteration.Romanize(word); got != want {
+			t.Errorf("Expected %q to romanize to %q, got %q", word, want, got)
+		}
+	}
+}
+
+func TestWithTransliteration(t *testing.T) {
+	trie := buildAlg1Trie([]string{"привет", "привет", "приз", "private", "мир"})
+
+	if got := Words(trie.Autocomplete("priv", 5)); !reflect.DeepEqual(got, []string{"private"}) {
+		t.Errorf("Expected only [private] without a table, got %v", got)
+	}
+
+	trie.WithTransliteration(RussianTransliteration)
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"priv", []string{"привет", "private"}},
+		{"Pri", []string{"привет", "приз"}},
+		{"при", []string{"привет", "приз"}},
+		{"mir", []string{"мир"}},
+		{"xyz", []string{}},
+	}
+	for _, test := range tests {
+		got := Words(trie.Autocomplete(test.prefix, 5))
+		if len(got) == 0 {
+			got = []string{}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("For %q: expected %v, got %v", test.prefix, test.want, got)
+		}
+	}
+
+	// New words are indexed on the next query.
+	trie.Insert("мирный")
+	if got, want := Words(trie.Autocomplete("mir", 5)), []string{"мир", "мирный"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v after an insert, got %v", want, got)
+	}
+
+	trie.WithTransliteration(nil)
+	if got := trie.Autocomplete("mir", 5); len(got) != 0 {
+		t.Errorf("Expected no matches with transliteration off, got %v", got)
+	}
+}
@@ -0,0 +1,533 @@
+package autocomplete
+
+import (
+	"slices"
+
+	"auto-complete/corpus"
+)
+
+// -----------------------------------------
+// Algorithm_1: Contextual Bigram-Based Trie
+// -----------------------------------------
+
+// TrieNodeA1's children are in the children map or, for compact nodes (see
+// WithCompactNodes), in sorted.
+type TrieNodeA1 struct {
+	children  map[rune]*TrieNodeA1
+	sorted    *sortedChildren
+	isEnd     bool
+	frequency int
+	// words is the number of words ending at or below the node, and
+	// maxFrequency and frequencySum the highest and the total frequency
+	// among them.
+	words        int
+	maxFrequency int
+	frequencySum int
+	// top holds the node's best completions while the trie is finalized.
+	top []Suggestion
+}
+
+type TrieA1 struct {
+	root        *TrieNodeA1
+	bigramTable map[string]map[string]int
+
+	// ngramTable holds the counts for contexts of two or more words, keyed by
+	// the context words joined with ngramSeparator; single-word contexts stay
+	// in bigramTable. ngramOrder is the N of the model, zero if
+	// BuildNgramTable was never called.
+	ngramTable map[string]map[string]int
+	ngramOrder int
+
+	// phrases counts the multi-word phrases recorded by IndexPhrases and
+	// phraseLength is the longest phrase indexed or generated.
+	phrases      *TriesA2
+	phraseLength int
+
+	// history holds the last words passed to ObserveWord, at most
+	// ngramOrder-1 of them, as the context for the next one.
+	history []string
+
+	// matchRatioWeight blends the prefix match ratio into the ranking score.
+	// Zero (the default) ranks by probability alone.
+	matchRatioWeight float64
+
+	// frequencyTransform reshapes raw counts before they are normalized into
+	// probabilities; nil means RawFrequency.
+	frequencyTransform FrequencyTransform
+
+	// stopwords are excluded from the trie and the bigram table when set.
+	stopwords map[string]bool
+
+	// contextStopwords are kept out of the context model but not the trie;
+	// bridgeStopwords lets the words around them count as neighbours.
+	contextStopwords map[string]bool
+	bridgeStopwords  bool
+
+	// tokenizer, if set with WithTokenizer, splits and filters corpus
+	// entries before the corpus builders count them.
+	tokenizer corpus.Tokenizer
+
+	// graphemes, set with WithGraphemes, keeps completions whose prefix
+	// ends inside a grapheme cluster out of results.
+	graphemes bool
+
+	// cache holds recent Autocomplete results; nil disables caching.
+	cache *suggestionCache
+
+	// strict trims words and rejects whitespace-only ones; rejected counts
+	// every word Insert refused.
+	strict   bool
+	rejected int
+
+	// finalK is the length of the lists Finalize stored at every node, zero
+	// when there are none.
+	finalK int
+
+	// maxScan bounds how many words a query collects; zero means no limit.
+	maxScan int
+
+	// options are the result limits and cutoffs set with WithOptions.
+	options Options
+
+	// scorer, if set with WithScorer, gives every candidate its final score.
+	scorer Scorer
+
+	// filter hides blocklisted and filtered-out words from results.
+	filter wordFilter
+
+	// compactNodes makes new nodes store their children in sorted slices.
+	compactNodes bool
+
+	// maxCorrections is the edit limit for "did you mean" suggestions when
+	// a prefix has no completions; zero disables them.
+	maxCorrections int
+
+	// phonetic indexes words by how they sound, if set with WithPhonetic.
+	phonetic *keyIndex
+
+	// transliteration indexes words by their romanization, if set with
+	// WithTransliteration.
+	transliteration *keyIndex
+
+	// clicks counts accepted suggestions, if WithClickThrough is enabled.
+	clicks *clickModel
+
+	// payloads holds the metadata attached with InsertWithPayload; nil
+	// until the first one.
+	payloads map[string]Payload
+
+	// precision is the number of decimal places returned probabilities are
+	// rounded to; zero leaves them unrounded.
+	precision int
+
+	// frequencyWeight is 1-alpha of WithContextWeight: the share of plain
+	// frequency in contextual scores. Zero ranks by context alone.
+	frequencyWeight float64
+
+	// smoothing spreads contextual probability to completions that never
+	// followed the context; the zero value disables it.
+	smoothing Smoothing
+
+	// fallback supplies extra suggestions when the trie has fewer than k.
+	fallback SuggestionSource
+
+	// size is the number of distinct words stored.
+	size int
+}
+
+// Suggestion is a completion returned by either algorithm. Score is the
+// ranking score (a probability unless ranking adjustments are enabled) and
+// Frequency is the word's count in the trie. Completions collected before
+// ranking carry only Word and Frequency.
+//
+// CorrectedPrefix is set only on "did you mean" suggestions returned when
+// the query's prefix had no completions (see WithCorrections): it is the
+// prefix in the trie the word completes instead.
+type Suggestion struct {
+	Word            string  `json:"word"`
+	Score           float64 `json:"score"`
+	Frequency       int     `json:"frequency"`
+	CorrectedPrefix string  `json:"correctedPrefix,omitempty"`
+}
+
+func NewTrieNodeA1() *TrieNodeA1 {
+	return &TrieNodeA1{children: make(map[rune]*TrieNodeA1)}
+}
+
+func NewTrieA1() *TrieA1 {
+	return &TrieA1{
+		root:        NewTrieNodeA1(),
+		bigramTable: make(map[string]map[string]int),
+	}
+}
+
+// Insert adds word to the trie. Empty words are always ignored so the root
+// never becomes a word; other words are stored verbatim unless strict mode is
+// enabled with SetStrict.
+func (t *TrieA1) Insert(word string) {
+	t.InsertWithWeight(word, 1)
+}
+
+// InsertWithWeight adds weight occurrences of word at once, as if Insert
+// were called weight times. Weights below one are ignored.
+func (t *TrieA1) InsertWithWeight(word string, weight int) {
+	if weight <= 0 {
+		return
+	}
+	word, ok := normalizeWord(word, t.strict)
+	if !ok {
+		t.rejected++
+		return
+	}
+	if t.stopwords[word] {
+		return
+	}
+	t.insertNormalized(word, weight)
+	t.cache.clear()
+}
+
+// InsertWithWeightFloat is InsertWithWeight for fractional weights, such as
+// relative frequencies or counts scaled by how much a source is trusted.
+// Frequencies count whole occurrences, so weight is rounded to the nearest
+// one; weights rounding below one, and NaN, are ignored.
+func (t *TrieA1) InsertWithWeightFloat(word string, weight float64) {
+	t.InsertWithWeight(word, roundWeight(weight))
+}
+
+// insertNormalized adds weight occurrences of a word that has already been
+// normalized and checked against the stopwords.
+func (t *TrieA1) insertNormalized(word string, weight int) {
+	node := t.root
+	for _, char := range word {
+		node = node.addChild(char)
+	}
+	isNew := !node.isEnd
+	if isNew {
+		t.size++
+		t.resetKeyIndexes()
+	}
+	node.isEnd = true
+	node.frequency += weight
+	t.countInsert(word, weight, node.frequency, isNew)
+	t.unfinalize()
+}
+
+// BuildBigramTable counts every pair of neighbouring words in corpus, after
+// running it through the tokenizer set with WithTokenizer, if any.
+func (t *TrieA1) BuildBigramTable(corpus []string) {
+	t.countBigrams(t.tokenize(corpus))
+}
+
+func (t *TrieA1) countBigrams(corpus []string) {
+	words := t.contextWords(corpus)
+	for i, follower := range t.contextFollowers(words) {
+		if follower != "" {
+			countFollower(t.bigramTable, words[i], follower)
+		}
+	}
+	t.cache.clear()
+}
+
+// countFollower counts one occurrence of word after context in table.
+func countFollower(table map[string]map[string]int, context, word string) {
+	if _, exists := table[context]; !exists {
+		table[context] = map[string]int{"_total": 0}
+	}
+	table[context][word]++
+	table[context]["_total"]++
+}
+
+// BuildFromCorpus inserts every word of corpus in order and builds the bigram
+// table from the same slice, so the trie and the context model can never be
+// built from diverging inputs. Insert and BuildBigramTable remain available
+// for callers that need to feed them separately. With WithTokenizer, corpus
+// is tokenized once and both are built from the tokens.
+func (t *TrieA1) BuildFromCorpus(corpus []string) {
+	t.buildFromTokens(t.tokenize(corpus))
+}
+
+func (t *TrieA1) buildFromTokens(words []string) {
+	for _, word := range words {
+		t.Insert(word)
+	}
+	t.countBigrams(words)
+}
+
+func (t *TrieA1) searchPrefix(prefix string) *TrieNodeA1 {
+	node := t.root
+	for _, char := range prefix {
+		if child := node.child(char); child != nil {
+			node = child
+		} else {
+			return nil
+		}
+	}
+	return node
+}
+
+// Contains reports whether word is stored in the trie, without running a
+// query.
+func (t *TrieA1) Contains(word string) bool {
+	node := t.searchPrefix(word)
+	return node != nil && node.isEnd
+}
+
+// Frequency returns how many times word was inserted, or 0 if it is not
+// stored.
+func (t *TrieA1) Frequency(word string) int {
+	node := t.searchPrefix(word)
+	if node == nil || !node.isEnd {
+		return 0
+	}
+	return node.frequency
+}
+
+// searchRunes walks an already decoded prefix.
+func (t *TrieA1) searchRunes(prefix []rune) *TrieNodeA1 {
+	node := t.root
+	for _, char := range prefix {
+		child := node.child(char)
+		if child == nil {
+			return nil
+		}
+		node = child
+	}
+	return node
+}
+
+func (t *TrieA1) collectCompletions(node *TrieNodeA1, prefix []rune) []Suggestion {
+	results, _ := t.collectCompletionsLimit(node, prefix, 0, -1, nil)
+	return results
+}
+
+// scoredCompletions collects the completions under node allowed by the scan
+// limit, the result options and the query and scores them after the query's
+// context words, unordered.
+func (t *TrieA1) scoredCompletions(node *TrieNodeA1, prefix []rune, prefixStr string, q query) ([]Suggestion, bool) {
+	completions, truncated := t.completionsOf(node, prefix, prefixStr, q)
+	return t.scoreCandidates(prefixStr, q, completions, nil), truncated
+}
+
+// completionsOf collects the words under node, the node of prefix, within
+// the scan and depth limits.
+func (t *TrieA1) completionsOf(node *TrieNodeA1, prefix []rune, prefixStr string, q query) ([]Suggestion, bool) {
+	completions, truncated := t.collectCompletionsLimit(node, prefix, t.maxScan, t.options.depth(), q.cancel)
+	if t.graphemes {
+		completions = keepWholeGraphemes(prefixStr, completions)
+	}
+	return completions, truncated
+}
+
+// scoreCandidates keeps the collected completions allowed by the result
+// options and the query and scores them after the query's context words,
+// unordered. The words in alternates are phonetic or transliterated
+// matches, whose scores are multiplied by their weights.
+func (t *TrieA1) scoreCandidates(prefixStr string, q query, completions []Suggestion, alternates map[string]float64) []Suggestion {
+	completions = q.restrict(t.filter.apply(t.options.keepFrequent(completions)))
+	scored := t.scoreInContext(prefixStr, t.lookupContext(q.context), completions)
+	weightAlternates(scored, alternates)
+	t.clicks.blend(prefixStr, scored)
+	return t.options.keepScoring(rescore(t.scorer, prefixStr, q.context, scored))
+}
+
+// candidates returns every suggestion a plain Autocomplete of prefix ranks,
+// phonetic and transliterated matches included, scored as it scores them
+// but neither ordered nor rounded. It is for queries that need more of the
+// ranking than its top k, and so cannot use the pruned search.
+func (t *TrieA1) candidates(prefix string) []Suggestion {
+	runes := []rune(prefix)
+	node := t.searchRunes(runes)
+	alternates, weights := t.alternateCandidates(prefix)
+	var completions []Suggestion
+	if node != nil {
+		completions, _ = t.completionsOf(node, runes, prefix, query{})
+	}
+	return t.scoreCandidates(prefix, query{}, append(completions, alternates...), weights)
+}
+
+// collectCompletionsLimit stops after collecting limit words (zero means no
+// limit) or when cancel says so, and reports whether any were left unvisited.
+// It descends at most depth levels below node; a negative depth is no limit.
+//
+// The walk keeps its own stack rather than recursing, so deep tries cannot
+// grow the goroutine stack, and spells every word in one path buffer: an
+// entry remembers how long the path is at its node, and visiting it cuts
+// the buffer back to its parent's runes before writing its own. Siblings
+// thus overwrite each other's rune in turn but never a rune still in use,
+// and the caller's prefix is copied, never appended to.
+func (t *TrieA1) collectCompletionsLimit(node *TrieNodeA1, prefix []rune, limit, depth int, cancel *canceller) ([]Suggestion, bool) {
+	var results []Suggestion
+	maxLen := len(prefix) + depth
+
+	path := append(make([]rune, 0, len(prefix)+8), prefix...)
+	stack := []pathEntry{{node: node, length: len(prefix)}}
+	for len(stack) > 0 {
+		if cancel.stop() {
+			return results, true
+		}
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		path = path[:entry.length]
+		if entry.length > len(prefix) {
+			path[entry.length-1] = entry.char
+		}
+
+		current := entry.node
+		if current.isEnd {
+			if limit > 0 && len(results) == limit {
+				return results, true
+			}
+			results = append(results, Suggestion{Word: string(path), Frequency: current.frequency})
+		}
+		if depth >= 0 && len(path) == maxLen {
+			continue
+		}
+		// Room for the children's rune, so cutting the path back to a
+		// child's length never reaches past the buffer.
+		path = slices.Grow(path, 1)
+		// A limited walk keeps the words that come first in rune order, so
+		// the same ones are kept on every run. The stack pops last in first
+		// out, so the children go on in reverse.
+		if limit > 0 {
+			runes := current.childRunes()
+			for i := len(runes) - 1; i >= 0; i-- {
+				stack = append(stack, pathEntry{node: current.child(runes[i]), char: runes[i], length: len(path) + 1})
+			}
+			continue
+		}
+		current.eachChild(func(char rune, child *TrieNodeA1) {
+			stack = append(stack, pathEntry{node: child, char: char, length: len(path) + 1})
+		})
+	}
+	return results, false
+}
+
+// pathEntry is a node waiting on the stack of collectCompletionsLimit: the
+// rune leading to it and the length of the path through it.
+type pathEntry struct {
+	node   *TrieNodeA1
+	char   rune
+	length int
+}
+
+// scoreCompletions computes the context-free ranking score of every
+// completion without ordering them.
+func (t *TrieA1) scoreCompletions(prefix string, completions []Suggestion) []Suggestion {
+	return t.scoreInContext(prefix, nil, completions)
+}
+
+// scoreInContext scores completions by how often they followed the context
+// whose follower counts are contextData, smoothed as configured with
+// SetSmoothing, or by frequency when contextData is nil.
+func (t *TrieA1) scoreInContext(prefix string, contextData map[string]int, completions []Suggestion) []Suggestion {
+	if len(completions) == 0 {
+		return nil
+	}
+	probabilities := t.unigramProbabilities(completions)
+	if contextData != nil {
+		probabilities = t.blendContext(t.smoothing.apply(t.bigramTable, contextData, completions, probabilities), probabilities)
+	}
+
+	ranked := make([]Suggestion, len(completions))
+	for i, completion := range completions {
+		ranked[i] = Suggestion{Word: completion.Word, Score: t.blendMatchRatio(prefix, completion.Word, probabilities[i]), Frequency: completion.Frequency}
+	}
+	return ranked
+}
+
+// unigramProbabilities returns each completion's share of the completions'
+// total transformed frequency.
+func (t *TrieA1) unigramProbabilities(completions []Suggestion) []float64 {
+	transform := t.frequencyTransform
+	if transform == nil {
+		transform = RawFrequency
+	}
+	probabilities := make([]float64, len(completions))
+	for i, completion := range completions {
+		probabilities[i] = transform(completion.Frequency)
+	}
+	totalFreq := exactSum(probabilities)
+	for i := range probabilities {
+		probabilities[i] /= totalFreq
+	}
+	return probabilities
+}
+
+// Autocomplete returns up to k completions of prefix ranked by frequency.
+// Use AutocompleteWithContext to rank by the previous word instead.
+func (t *TrieA1) Autocomplete(prefix string, k int) []Suggestion {
+	key := cacheKey{prefix: prefix, k: k}
+	if cached, ok := t.cache.get(key); ok {
+		return cached
+	}
+	result := t.autocomplete(prefix, k)
+	t.cache.put(key, result)
+	return result
+}
+
+// AutocompleteWithContext returns up to k completions of prefix ranked by how
+// often each one followed prevWord in the corpus. It falls back to frequency
+// when prevWord is empty or never had a follower.
+func (t *TrieA1) AutocompleteWithContext(prevWord, prefix string, k int) []Suggestion {
+	var context []string
+	if prevWord != "" {
+		context = []string{prevWord}
+	}
+	return t.AutocompleteContext(prefix, context, k)
+}
+
+// AutocompleteRunes is Autocomplete for callers that already hold the prefix
+// as runes, such as editors working on CJK text. The prefix is walked without
+// being decoded again.
+func (t *TrieA1) AutocompleteRunes(prefix []rune, k int) []Suggestion {
+	key := cacheKey{prefix: string(prefix), k: k}
+	if cached, ok := t.cache.get(key); ok {
+		return cached
+	}
+	result := t.autocompleteRunes(prefix, key.prefix, k)
+	t.cache.put(key, result)
+	return result
+}
+
+func (t *TrieA1) autocomplete(prefix string, k int) []Suggestion {
+	return t.autocompleteRunes([]rune(prefix), prefix, k)
+}
+
+// autocompleteRunes takes the prefix in both forms so neither has to be
+// converted again: runes for the trie walk, the string for context lookups.
+func (t *TrieA1) autocompleteRunes(prefix []rune, prefixStr string, k int) []Suggestion {
+	suggestions, _ := t.autocompleteBounded(prefix, prefixStr, query{}, k)
+	return t.fillFromFallback(prefixStr, k, suggestions)
+}
+
+func (t *TrieA1) autocompleteBounded(prefix []rune, prefixStr string, q query, k int) ([]Suggestion, bool) {
+	node := t.searchRunes(prefix)
+	alternates, weights := t.alternateCandidates(prefixStr)
+	if node == nil && len(alternates) == 0 {
+		return t.correct(prefix, prefixStr, q, k), false
+	}
+
+	if node != nil && t.canPrune(q, alternates) {
+		if top, ok := t.finalTop(node, t.options.limit(k)); ok {
+			top = t.options.keepScoring(top)
+			t.roundProbabilities(top)
+			return top, false
+		}
+		top, complete := t.topCompletions(node, string(prefix), t.options.limit(k), q.cancel)
+		top = t.options.keepScoring(top)
+		t.roundProbabilities(top)
+		return top, !complete
+	}
+
+	var completions []Suggestion
+	truncated := false
+	if node != nil {
+		completions, truncated = t.completionsOf(node, prefix, prefixStr, q)
+	}
+	scored := t.scoreCandidates(prefixStr, q, append(completions, alternates...), weights)
+	rankedCompletions := topK(scored, t.options.limit(k))
+
+	// Round only after ranking so the order reflects the exact scores.
+	t.roundProbabilities(rankedCompletions)
+	return rankedCompletions, truncated
+}
@@ -0,0 +1,215 @@
+package autocomplete
+
+import "time"
+
+// -----------------------------------------
+// Algorithm_2: Frequency-Based Trie
+// -----------------------------------------
+
+type NodeA2 struct {
+	children    map[rune]*NodeA2
+	isEndOfWord bool
+	frequency   int
+	windowCount int     // occurrences within the recency window, if enabled
+	weight      float64 // time-decayed frequency, if decay is enabled
+	words       int     // words ending at or below the node
+}
+
+type TriesA2 struct {
+	root *NodeA2
+
+	// strict trims words and rejects whitespace-only ones; rejected counts
+	// every word Insert refused.
+	strict   bool
+	rejected int
+
+	// size is the number of distinct words stored.
+	size int
+
+	// window is a ring buffer of the word nodes from the most recent inserts;
+	// nil disables windowed counting.
+	window     []*NodeA2
+	windowNext int
+
+	// halfLife is how long an occurrence takes to lose half its weight;
+	// zero disables decay. lastDecay is when weights were last decayed and
+	// now is the clock, time.Now unless replaced in tests.
+	halfLife  time.Duration
+	lastDecay time.Time
+	now       func() time.Time
+
+	// infix is the suffix index behind Search, built on first use and
+	// dropped whenever a word is added or removed.
+	infix *infixCache
+
+	// options are the result limits and cutoffs set with WithOptions.
+	options Options
+
+	// scorer, if set with WithScorer, gives every candidate its final score.
+	scorer Scorer
+
+	// filter hides blocklisted and filtered-out words from results.
+	filter wordFilter
+
+	// maxCorrections is the edit limit for "did you mean" suggestions when
+	// a prefix has no completions; zero disables them.
+	maxCorrections int
+
+	// keyboard, if set with WithKeyboard, makes fuzzy substitutions of
+	// neighbouring keys cheaper.
+	keyboard *KeyboardLayout
+
+	// graphemes, set with WithGraphemes, keeps completions whose prefix
+	// ends inside a grapheme cluster out of results.
+	graphemes bool
+
+	// clicks counts accepted suggestions, if WithClickThrough is enabled.
+	clicks *clickModel
+
+	// payloads holds the metadata attached with InsertWithPayload; nil
+	// until the first one.
+	payloads map[string]Payload
+}
+
+// NewTriesA2 returns an empty frequency-based trie.
+func NewTriesA2() *TriesA2 {
+	return &TriesA2{
+		root: &NodeA2{
+			isEndOfWord: false,
+			children:    make(map[rune]*NodeA2),
+			frequency:   0,
+		},
+		infix: &infixCache{},
+	}
+}
+
+// Insert adds word to the trie. Empty words are always ignored so the root
+// never becomes a word; other words are stored verbatim unless strict mode is
+// enabled with SetStrict.
+func (t *TriesA2) Insert(word string) {
+	t.InsertWithWeight(word, 1)
+}
+
+// InsertWithWeight adds weight occurrences of word at once, as if Insert
+// were called weight times. Weights below one are ignored.
+func (t *TriesA2) InsertWithWeight(word string, weight int) {
+	if weight <= 0 {
+		return
+	}
+	word, ok := normalizeWord(word, t.strict)
+	if !ok {
+		t.rejected++
+		return
+	}
+	current := t.root
+	for _, char := range word {
+		node, ok := current.children[char]
+		if !ok {
+			node = &NodeA2{
+				isEndOfWord: false,
+				children:    make(map[rune]*NodeA2),
+				frequency:   0,
+			}
+			current.children[char] = node
+		}
+		current = node
+	}
+	if !current.isEndOfWord {
+		t.size++
+		t.countNewWord(word)
+		t.resetInfix()
+	}
+	current.isEndOfWord = true
+	current.frequency += weight
+	if t.halfLife > 0 {
+		current.weight += float64(weight)
+	}
+	// Occurrences beyond the window size would only push each other out.
+	for i := 0; i < min(weight, len(t.window)); i++ {
+		t.recordInWindow(current)
+	}
+}
+
+// InsertWithWeightFloat is InsertWithWeight for fractional weights, such as
+// relative frequencies or counts scaled by how much a source is trusted.
+// Frequencies count whole occurrences, so weight is rounded to the nearest
+// one; weights rounding below one, and NaN, are ignored.
+func (t *TriesA2) InsertWithWeightFloat(word string, weight float64) {
+	t.InsertWithWeight(word, roundWeight(weight))
+}
+
+// Contains reports whether word is stored in the trie, without running a
+// query.
+func (t *TriesA2) Contains(word string) bool {
+	node := t.searchPrefix(word)
+	return node != nil && node.isEndOfWord
+}
+
+// Frequency returns how many times word was inserted, or 0 if it is not
+// stored.
+func (t *TriesA2) Frequency(word string) int {
+	node := t.searchPrefix(word)
+	if node == nil || !node.isEndOfWord {
+		return 0
+	}
+	return node.frequency
+}
+
+// Autocomplete returns up to k completions of prefix ranked by frequency,
+// with each word's share of the candidates' total frequency as its
+// probability. It is the same as AutocompleteProb.
+func (t *TriesA2) Autocomplete(prefix string, k int) []Suggestion {
+	return t.AutocompleteProb(prefix, k)
+}
+
+// AutocompleteTopK returns up to k completions of prefix ranked by frequency.
+func (t *TriesA2) AutocompleteTopK(prefix string, k int) []string {
+	return Words(t.AutocompleteProb(prefix, k))
+}
+
+func (t *TriesA2) searchPrefix(prefix string) *NodeA2 {
+	current := t.root
+	for _, char := range prefix {
+		node, ok := current.children[char]
+		if !ok {
+			return nil
+		}
+		current = node
+	}
+	return current
+}
+
+// collectEntriesA2 gathers every word under node together with its frequency.
+func collectEntriesA2(node *NodeA2, prefix string, results *[]Suggestion) {
+	collectEntriesA2Cancel(node, prefix, results, -1, nil)
+}
+
+// collectEntriesA2Cancel is collectEntriesA2 that descends at most depth
+// levels (a negative depth is no limit) and stops when cancel says so,
+// reporting false if it did.
+func collectEntriesA2Cancel(node *NodeA2, prefix string, results *[]Suggestion, depth int, cancel *canceller) bool {
+	if cancel.stop() {
+		return false
+	}
+	if node.isEndOfWord {
+		*results = append(*results, Suggestion{Word: prefix, Frequency: node.frequency})
+	}
+	if depth == 0 {
+		return true
+	}
+	for char, child := range node.children {
+		if !collectEntriesA2Cancel(child, prefix+string(char), results, depth-1, cancel) {
+			return false
+		}
+	}
+	return true
+}
+
+func collectWordsA2(node *NodeA2, prefix string, results *[]string) {
+	if node.isEndOfWord {
+		*results = append(*results, prefix)
+	}
+	for char, child := range node.children {
+		collectWordsA2(child, prefix+string(char), results)
+	}
+}
@@ -0,0 +1,174 @@
+package autocomplete
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// -----------------------------------------
+// Algorithm_3: Radix (Patricia) Trie
+// -----------------------------------------
+
+// NodeA3 is a radix trie node. Its label is the text of the edge leading to
+// it, so a chain of single-child nodes in Algorithm_1 or Algorithm_2
+// collapses into one node here.
+type NodeA3 struct {
+	label     string
+	children  []*NodeA3 // at most one per first rune of label
+	isEnd     bool
+	frequency int
+}
+
+// TrieA3 is a compressed trie that ranks completions by frequency like
+// Algorithm_2 while allocating one node per branch point instead of one per
+// rune, which saves most of the memory on long words.
+type TrieA3 struct {
+	root *NodeA3
+
+	// size is the number of distinct words stored.
+	size int
+}
+
+// NewTrieA3 returns an empty radix trie.
+func NewTrieA3() *TrieA3 {
+	return &TrieA3{root: &NodeA3{}}
+}
+
+// child returns the child whose label starts with char, or nil.
+func (n *NodeA3) child(char rune) (int, *NodeA3) {
+	for i, c := range n.children {
+		if first, _ := utf8.DecodeRuneInString(c.label); first == char {
+			return i, c
+		}
+	}
+	return -1, nil
+}
+
+// commonPrefixLen returns the byte length of the longest common prefix of a
+// and b that ends on a rune boundary.
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) {
+		ra, size := utf8.DecodeRuneInString(a[n:])
+		rb, _ := utf8.DecodeRuneInString(b[n:])
+		if ra != rb {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// Insert adds word to the trie, splitting an edge when word diverges from it
+// part way. Empty words are ignored so the root never becomes a word.
+func (t *TrieA3) Insert(word string) {
+	t.InsertWithWeight(word, 1)
+}
+
+// InsertWithWeight adds weight occurrences of word at once, as if Insert
+// were called weight times. Weights below one are ignored.
+func (t *TrieA3) InsertWithWeight(word string, weight int) {
+	if weight <= 0 {
+		return
+	}
+	if word == "" {
+		return
+	}
+	node, rest := t.root, word
+	for rest != "" {
+		first, _ := utf8.DecodeRuneInString(rest)
+		i, child := node.child(first)
+		if child == nil {
+			child = &NodeA3{label: rest}
+			node.children = append(node.children, child)
+			node, rest = child, ""
+			break
+		}
+
+		common := commonPrefixLen(child.label, rest)
+		if common < len(child.label) {
+			// Split the edge: the shared part becomes a new node above child.
+			mid := &NodeA3{label: child.label[:common], children: []*NodeA3{child}}
+			child.label = child.label[common:]
+			node.children[i] = mid
+			child = mid
+		}
+		node, rest = child, rest[common:]
+	}
+
+	if !node.isEnd {
+		t.size++
+	}
+	node.isEnd = true
+	node.frequency += weight
+}
+
+// InsertWithWeightFloat is InsertWithWeight for fractional weights, such as
+// relative frequencies or counts scaled by how much a source is trusted.
+// Frequencies count whole occurrences, so weight is rounded to the nearest
+// one; weights rounding below one, and NaN, are ignored.
+func (t *TrieA3) InsertWithWeightFloat(word string, weight float64) {
+	t.InsertWithWeight(word, roundWeight(weight))
+}
+
+// Autocomplete returns up to k completions of prefix ranked by frequency,
+// with each word's share of the candidates' total frequency as its
+// probability, exactly as Algorithm_2 does.
+func (t *TrieA3) Autocomplete(prefix string, k int) []Suggestion {
+	node, path := t.searchPrefix(prefix)
+	if node == nil {
+		return nil
+	}
+
+	var entries []Suggestion
+	collectEntriesA3(node, path, &entries)
+
+	total := 0
+	for _, e := range entries {
+		total += e.Frequency
+	}
+	for i := range entries {
+		entries[i].Score = float64(entries[i].Frequency) / float64(total)
+	}
+	return topK(entries, k)
+}
+
+// Len returns the number of distinct words in the trie.
+func (t *TrieA3) Len() int {
+	return t.size
+}
+
+// searchPrefix returns the node whose subtree holds every completion of
+// prefix, together with the full text leading to that node, which may run
+// past prefix when prefix ends inside an edge.
+func (t *TrieA3) searchPrefix(prefix string) (*NodeA3, string) {
+	node, rest := t.root, prefix
+	for rest != "" {
+		first, _ := utf8.DecodeRuneInString(rest)
+		_, child := node.child(first)
+		if child == nil {
+			return nil, ""
+		}
+		switch {
+		case strings.HasPrefix(rest, child.label):
+			rest = rest[len(child.label):]
+		case strings.HasPrefix(child.label, rest):
+			return child, prefix + child.label[len(rest):]
+		default:
+			return nil, ""
+		}
+		node = child
+	}
+	return node, prefix
+}
+
+// collectEntriesA3 gathers every word under node together with its frequency.
+// path is the text leading to node, including its own label.
+func collectEntriesA3(node *NodeA3, path string, results *[]Suggestion) {
+	if node.isEnd {
+		*results = append(*results, Suggestion{Word: path, Frequency: node.frequency})
+	}
+	for _, child := range node.children {
+		collectEntriesA3(child, path+child.label, results)
+	}
+}
@@ -0,0 +1,84 @@
+package autocomplete
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func countNodesA3(node *NodeA3) int {
+	n := 1
+	for _, child := range node.children {
+		n += countNodesA3(child)
+	}
+	return n
+}
+
+func TestTrieA3CompressesChains(t *testing.T) {
+	trie := NewTrieA3()
+	for _, w := range []string{"helicopter", "hello", "hell", "help"} {
+		trie.Insert(w)
+	}
+
+	// root -> "hel" -> {"icopter", "l" -> {"o"}, "p"}
+	if got := countNodesA3(trie.root); got != 6 {
+		t.Errorf("Expected 6 nodes, got %d", got)
+	}
+	if got := Words(trie.Autocomplete("hell", 5)); !reflect.DeepEqual(got, []string{"hell", "hello"}) {
+		t.Errorf("Expected [hell hello], got %v", got)
+	}
+	// A prefix ending inside an edge still finds the words below it.
+	if got := Words(trie.Autocomplete("heli", 5)); !reflect.DeepEqual(got, []string{"helicopter"}) {
+		t.Errorf("Expected [helicopter], got %v", got)
+	}
+	if got := trie.Autocomplete("helix", 5); len(got) != 0 {
+		t.Errorf("Expected no suggestions for a diverging prefix, got %v", got)
+	}
+}
+
+func TestTrieA3SplitsOnRuneBoundaries(t *testing.T) {
+	trie := NewTrieA3()
+	// "é" and "è" share their first UTF-8 byte, so a byte-wise split would
+	// cut a rune in half.
+	for _, w := range []string{"café", "cafè", "日本語", "日本"} {
+		trie.Insert(w)
+	}
+	for _, prefix := range []string{"caf", "café", "cafè", "日", "日本"} {
+		for _, s := range trie.Autocomplete(prefix, 5) {
+			if !utf8.ValidString(s.Word) {
+				t.Errorf("Invalid word %q for prefix %q", s.Word, prefix)
+			}
+		}
+	}
+	if got := trie.Autocomplete("caf", 5); len(got) != 2 {
+		t.Errorf("Expected 2 completions of caf, got %v", got)
+	}
+	if got := Words(trie.Autocomplete("日本", 5)); !reflect.DeepEqual(got, []string{"日本", "日本語"}) {
+		t.Errorf("Expected [日本 日本語], got %v", got)
+	}
+}
+
+func TestTrieA3MatchesAlgorithm2(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	letters := []rune("abcé")
+	a2, a3 := NewTriesA2(), NewTrieA3()
+	for i := 0; i < 500; i++ {
+		word := make([]rune, 1+rng.Intn(6))
+		for j := range word {
+			word[j] = letters[rng.Intn(len(letters))]
+		}
+		a2.Insert(string(word))
+		a3.Insert(string(word))
+	}
+
+	if a2.Len() != a3.Len() {
+		t.Errorf("Expected %d words, got %d", a2.Len(), a3.Len())
+	}
+	for _, prefix := range []string{"", "a", "ab", "é", "cé", "abca", "zzz"} {
+		want, got := a2.Autocomplete(prefix, 10), a3.Autocomplete(prefix, 10)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("For prefix %q: expected %v, got %v", prefix, want, got)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package autocomplete
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestTrieA4Autocomplete(t *testing.T) {
+	trie := NewTrieA4()
+	for _, w := range []string{"hello", "hello", "hell", "help", "helicopter", "hero", "日本", "日本語"} {
+		trie.Insert(w)
+	}
+
+	if got := Words(trie.Autocomplete("hel", 5)); !reflect.DeepEqual(got, []string{"hello", "helicopter", "hell", "help"}) {
+		t.Errorf("Expected [hello helicopter hell help], got %v", got)
+	}
+	if got := Words(trie.Autocomplete("日本", 5)); !reflect.DeepEqual(got, []string{"日本", "日本語"}) {
+		t.Errorf("Expected [日本 日本語], got %v", got)
+	}
+	if got := trie.Autocomplete("hex", 5); len(got) != 0 {
+		t.Errorf("Expected no suggestions for an unknown prefix, got %v", got)
+	}
+}
+
+func TestTrieA4MatchesAlgorithm2(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	letters := []rune("abcé")
+	a2, a4 := NewTriesA2(), NewTrieA4()
+	for i := 0; i < 500; i++ {
+		word := make([]rune, 1+rng.Intn(6))
+		for j := range word {
+			word[j] = letters[rng.Intn(len(letters))]
+		}
+		a2.Insert(string(word))
+		a4.Insert(string(word))
+	}
+
+	if a2.Len() != a4.Len() {
+		t.Errorf("Expected %d words, got %d", a2.Len(), a4.Len())
+	}
+	for _, prefix := range []string{"", "a", "ab", "é", "cé", "abca", "zzz"} {
+		want, got := a2.Autocomplete(prefix, 10), a4.Autocomplete(prefix, 10)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("For prefix %q: expected %v, got %v", prefix, want, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package autocomplete
+
+import "strings"
+
+// -----------------------------------------
+// Input Validation
+// -----------------------------------------
+
+// normalizeWord prepares word for insertion and reports whether it should be
+// stored. The empty string is never stored. In strict mode surrounding
+// whitespace is trimmed first, so whitespace-only words are rejected too.
+func normalizeWord(word string, strict bool) (string, bool) {
+	if strict {
+		word = strings.TrimSpace(word)
+	}
+	return word, word != ""
+}
+
+// SetStrict toggles strict input validation. When enabled, Insert trims
+// surrounding whitespace and rejects words that end up empty; bigrams that
+// involve such words are skipped as well.
+func (t *TrieA1) SetStrict(strict bool) {
+	t.strict = strict
+	t.cache.clear()
+}
+
+// Rejected returns how many words Insert has refused so far.
+func (t *TrieA1) Rejected() int {
+	return t.rejected
+}
+
+// SetStrict toggles strict input validation. When enabled, Insert trims
+// surrounding whitespace and rejects words that end up empty.
+func (t *TriesA2) SetStrict(strict bool) {
+	t.strict = strict
+}
+
+// Rejected returns how many words Insert has refused so far.
+func (t *TriesA2) Rejected() int {
+	return t.rejected
+}
@@ -0,0 +1,53 @@
+package autocomplete
+
+import "testing"
+
+func TestEmptyWordNeverMarksRoot(t *testing.T) {
+	trieA1 := NewTrieA1()
+	trieA2 := NewTriesA2()
+
+	trieA1.Insert("")
+	trieA2.Insert("")
+
+	if trieA1.root.isEnd || trieA2.root.isEndOfWord {
+		t.Errorf("Inserting an empty word must not turn the root into a word")
+	}
+	if trieA1.Rejected() != 1 || trieA2.Rejected() != 1 {
+		t.Errorf("Expected one rejection each, got %d and %d", trieA1.Rejected(), trieA2.Rejected())
+	}
+}
+
+func TestStrictModeRejectsWhitespace(t *testing.T) {
+	words := []string{"", "   ", "\t", " hello ", "hero"}
+
+	trieA1 := NewTrieA1()
+	trieA1.SetStrict(true)
+	trieA2 := NewTriesA2()
+	trieA2.SetStrict(true)
+	for _, w := range words {
+		trieA1.Insert(w)
+		trieA2.Insert(w)
+	}
+
+	if trieA1.Rejected() != 3 || trieA2.Rejected() != 3 {
+		t.Errorf("Expected 3 rejections each, got %d and %d", trieA1.Rejected(), trieA2.Rejected())
+	}
+	if trieA1.root.isEnd || trieA2.root.isEndOfWord {
+		t.Errorf("Strict mode must never turn the root into a word")
+	}
+	if _, exists := trieA1.root.children[' ']; exists {
+		t.Errorf("Whitespace should not be stored in strict mode")
+	}
+	if !trieA1.Contains("hello") || trieA2.Frequency("hello") != 1 {
+		t.Errorf("Expected ' hello ' to be trimmed and stored as 'hello'")
+	}
+}
+
+func TestDefaultModeKeepsWhitespace(t *testing.T) {
+	trie := NewTriesA2()
+	trie.Insert(" ")
+
+	if trie.Rejected() != 0 || trie.Frequency(" ") != 1 {
+		t.Errorf("Expected whitespace to be stored verbatim outside strict mode")
+	}
+}
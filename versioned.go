@@ -0,0 +1,212 @@
+package autocomplete
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// -----------------------------------------
+// Copy-on-Write Versions
+// -----------------------------------------
+
+// Versioned serves queries from an immutable version of a trie without any
+// locking. Writers never touch the published version: they build the next
+// one and swap it in atomically, so a query sees either the old version or
+// the new one, never a half-applied update. This suits high query rates
+// with periodic updates; for frequent small writes use Concurrent, since
+// every update here copies the whole trie.
+type Versioned struct {
+	current atomic.Pointer[version]
+
+	// mu serializes writers and guards pending.
+	mu      sync.Mutex
+	pending []weightedWord
+}
+
+type version struct {
+	trie Autocompleter
+}
+
+type weightedWord struct {
+	word   string
+	weight int
+}
+
+// errNotCloneable is returned by Versioned.Update for tries that cannot be
+// copied, which can only be replaced with Publish.
+var errNotCloneable = errors.New("autocomplete: trie does not support copy-on-write updates; use Publish")
+
+// NewVersioned publishes initial as the first version. The Versioned takes
+// ownership of it; it must not be used directly afterwards.
+func NewVersioned(initial Autocompleter) *Versioned {
+	v := &Versioned{}
+	v.current.Store(&version{trie: initial})
+	return v
+}
+
+// Current returns the published version. It stays valid and unchanged
+// however many versions are published after it, and must not be modified.
+func (v *Versioned) Current() Autocompleter {
+	return v.current.Load().trie
+}
+
+// Autocomplete queries the published version without locking.
+func (v *Versioned) Autocomplete(prefix string, k int) []Suggestion {
+	return v.Current().Autocomplete(prefix, k)
+}
+
+// Len returns the number of distinct words in the published version.
+func (v *Versioned) Len() int {
+	return v.Current().Len()
+}
+
+// Insert queues one occurrence of word for the next version. Queued words
+// are invisible to queries until Commit or Update publishes them.
+func (v *Versioned) Insert(word string) {
+	v.InsertWithWeight(word, 1)
+}
+
+// InsertWithWeight queues weight occurrences of word for the next version.
+func (v *Versioned) InsertWithWeight(word string, weight int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pending = append(v.pending, weightedWord{word, weight})
+}
+
+// Pending returns how many inserts are queued for the next version.
+func (v *Versioned) Pending() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.pending)
+}
+
+// Commit publishes a version with the queued inserts applied. It does
+// nothing when none are queued.
+func (v *Versioned) Commit() error {
+	v.mu.Lock()
+	empty := len(v.pending) == 0
+	v.mu.Unlock()
+	if empty {
+		return nil
+	}
+	return v.Update(nil)
+}
+
+// Update copies the published version, applies the queued inserts and then
+// fn, if not nil, to the copy, and publishes it. fn may make any change,
+// such as Delete or BuildBigramTable. Only TrieA1 and TriesA2 can be
+// copied; other tries return an error and must be replaced with Publish.
+func (v *Versioned) Update(fn func(next Autocompleter)) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	next, ok := cloneAutocompleter(v.Current())
+	if !ok {
+		return errNotCloneable
+	}
+	for _, w := range v.pending {
+		next.InsertWithWeight(w.word, w.weight)
+	}
+	if fn != nil {
+		fn(next)
+	}
+	v.pending = nil
+	v.current.Store(&version{trie: next})
+	return nil
+}
+
+// Publish replaces the published version with next, built by the caller,
+// and drops any queued inserts. The Versioned takes ownership of next.
+func (v *Versioned) Publish(next Autocompleter) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pending = nil
+	v.current.Store(&version{trie: next})
+}
+
+func cloneAutocompleter(a Autocompleter) (Autocompleter, bool) {
+	switch t := a.(type) {
+	case *TrieA1:
+		return t.Clone(), true
+	case *TriesA2:
+		return t.Clone(), true
+	}
+	return nil, false
+}
+
+// Clone returns a deep copy of the trie that shares no mutable state with
+// it, settings included. A cache, if enabled, starts out empty.
+func (t *TrieA1) Clone() *TrieA1 {
+	c := *t
+	c.root = cloneNodeA1(t.root)
+	c.bigramTable = cloneCounts(t.bigramTable)
+	if t.ngramTable != nil {
+		c.ngramTable = cloneCounts(t.ngramTable)
+	}
+	if t.phrases != nil {
+		c.phrases = t.phrases.Clone()
+	}
+	c.history = append([]string(nil), t.history...)
+	c.cache = t.cache.emptyCopy()
+	c.phonetic = t.phonetic.emptyCopy()
+	c.transliteration = t.transliteration.emptyCopy()
+	c.clicks = t.clicks.clone()
+	if t.payloads != nil {
+		c.payloads = make(map[string]Payload, len(t.payloads))
+		for word, payload := range t.payloads {
+			c.payloads[word] = payload
+		}
+	}
+	return &c
+}
+
+func cloneNodeA1(node *TrieNodeA1) *TrieNodeA1 {
+	return convertNodeA1(node, node.compact())
+}
+
+func cloneCounts(table map[string]map[string]int) map[string]map[string]int {
+	c := make(map[string]map[string]int, len(table))
+	for context, followers := range table {
+		copied := make(map[string]int, len(followers))
+		for word, count := range followers {
+			copied[word] = count
+		}
+		c[context] = copied
+	}
+	return c
+}
+
+// Clone returns a deep copy of the trie that shares no mutable state with
+// it, settings and the recency window included.
+func (t *TriesA2) Clone() *TriesA2 {
+	c := *t
+	copies := make(map[*NodeA2]*NodeA2)
+	var clone func(*NodeA2) *NodeA2
+	clone = func(node *NodeA2) *NodeA2 {
+		n := *node
+		n.children = make(map[rune]*NodeA2, len(node.children))
+		for char, child := range node.children {
+			n.children[char] = clone(child)
+		}
+		copies[node] = &n
+		return &n
+	}
+	c.root = clone(t.root)
+	if t.window != nil {
+		c.window = make([]*NodeA2, len(t.window))
+		for i, node := range t.window {
+			c.window[i] = copies[node]
+		}
+	}
+	c.infix = &infixCache{}
+	c.clicks = t.clicks.clone()
+	if t.payloads != nil {
+		c.payloads = make(map[string]Payload, len(t.payloads))
+		for word, payload := range t.payloads {
+			c.payloads[word] = payload
+		}
+	}
+	return &c
+}
+
+var _ Autocompleter = (*Versioned)(nil)
@@ -0,0 +1,108 @@
+package autocomplete
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestVersionedPublishesOnCommit(t *testing.T) {
+	v := NewVersioned(buildAlg1Trie([]string{"hello", "hell"}))
+	before := v.Current()
+
+	v.Insert("help")
+	v.InsertWithWeight("hero", 3)
+	if v.Len() != 2 || v.Pending() != 2 {
+		t.Errorf("Expected queued inserts to stay invisible, got %d words and %d pending", v.Len(), v.Pending())
+	}
+
+	if err := v.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if v.Len() != 4 || v.Pending() != 0 {
+		t.Errorf("Expected 4 words and nothing pending after Commit, got %d and %d", v.Len(), v.Pending())
+	}
+	if suggestions := v.Autocomplete("he", 1); len(suggestions) != 1 || suggestions[0].Word != "hero" {
+		t.Errorf("Expected 'hero' first after Commit, got %v", suggestions)
+	}
+	if before.Len() != 2 {
+		t.Errorf("Expected the previous version to stay unchanged, got %d words", before.Len())
+	}
+}
+
+func TestVersionedUpdate(t *testing.T) {
+	v := NewVersioned(buildAlg1Trie([]string{"the", "cat", "the", "car"}))
+	err := v.Update(func(next Autocompleter) {
+		next.(*TrieA1).BuildFromCorpus([]string{"the", "cat"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	suggestions := v.Current().(*TrieA1).AutocompleteWithContext("the", "ca", 1)
+	if len(suggestions) != 1 || suggestions[0].Word != "cat" {
+		t.Errorf("Expected 'cat' after the update, got %v", suggestions)
+	}
+
+	unsupported := NewVersioned(NewTrieA4())
+	if err := unsupported.Update(nil); err == nil {
+		t.Errorf("Expected an error updating a trie that cannot be copied")
+	}
+	unsupported.Publish(buildAlg1Trie([]string{"hello"}))
+	if unsupported.Len() != 1 {
+		t.Errorf("Expected the published trie, got %d words", unsupported.Len())
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	a1 := buildAlg1Trie([]string{"hello", "hell", "hello"})
+	a1.EnableCache()
+	c1 := a1.Clone()
+	c1.BuildFromCorpus([]string{"hero", "hello"})
+	if a1.Len() != 2 || a1.bigramTable["hero"] != nil || c1.Len() != 3 {
+		t.Errorf("Expected the clone's changes not to reach the original")
+	}
+	if c1.cache == nil || c1.cache == a1.cache {
+		t.Errorf("Expected the clone to get its own cache")
+	}
+
+	a2 := buildAlg2Trie([]string{"hello", "hell"})
+	a2.EnableWindow(2)
+	a2.Insert("hero")
+	c2 := a2.Clone()
+	if !reflect.DeepEqual(a2.root, c2.root) {
+		t.Errorf("Expected the clone to hold the same words")
+	}
+	c2.Insert("help")
+	c2.Insert("help")
+	if a2.WindowedFrequency("hero") != 1 || c2.WindowedFrequency("hero") != 0 {
+		t.Errorf("Expected each window to evict independently, got %d and %d",
+			a2.WindowedFrequency("hero"), c2.WindowedFrequency("hero"))
+	}
+}
+
+func TestVersionedConcurrentReadsAndUpdates(t *testing.T) {
+	v := NewVersioned(buildAlg2Trie([]string{"hello"}))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if len(v.Autocomplete("he", 3)) == 0 {
+					t.Errorf("Expected at least one suggestion")
+					return
+				}
+			}
+		}()
+	}
+	for j := 0; j < 50; j++ {
+		v.Insert("help")
+		if err := v.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+	if v.Len() != 2 {
+		t.Errorf("Expected 2 words, got %d", v.Len())
+	}
+}
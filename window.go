@@ -0,0 +1,65 @@
+package autocomplete
+
+// -----------------------------------------
+// Sliding-Window Frequency
+// -----------------------------------------
+
+// EnableWindow switches ranking from all-time frequency to how often each word
+// occurred among the last n inserts, so recently active words rise and words
+// that stopped appearing fall away. Only inserts made after enabling are
+// counted.
+//
+// The window costs one pointer per slot (8n bytes on 64-bit platforms) plus
+// one counter per node. A non-positive n turns windowing off and ranking
+// returns to all-time frequency.
+func (t *TriesA2) EnableWindow(n int) {
+	t.clearWindowCounts(t.root)
+	t.windowNext = 0
+	if n <= 0 {
+		t.window = nil
+		return
+	}
+	t.window = make([]*NodeA2, n)
+}
+
+func (t *TriesA2) clearWindowCounts(node *NodeA2) {
+	node.windowCount = 0
+	for _, child := range node.children {
+		t.clearWindowCounts(child)
+	}
+}
+
+// recordInWindow counts node in the window, evicting the oldest occurrence
+// once the ring is full.
+func (t *TriesA2) recordInWindow(node *NodeA2) {
+	if t.window == nil {
+		return
+	}
+	if oldest := t.window[t.windowNext]; oldest != nil {
+		oldest.windowCount--
+	}
+	t.window[t.windowNext] = node
+	node.windowCount++
+	t.windowNext = (t.windowNext + 1) % len(t.window)
+}
+
+// forgetInWindow drops the occurrences of node's word from the window once
+// the word is removed, so it starts from nothing if inserted again.
+func (t *TriesA2) forgetInWindow(node *NodeA2) {
+	for i, n := range t.window {
+		if n == node {
+			t.window[i] = nil
+		}
+	}
+	node.windowCount = 0
+}
+
+// WindowedFrequency returns how often word occurred within the recency
+// window, or zero when windowing is disabled or the word is unknown.
+func (t *TriesA2) WindowedFrequency(word string) int {
+	node := t.searchPrefix(word)
+	if t.window == nil || node == nil || !node.isEndOfWord {
+		return 0
+	}
+	return node.windowCount
+}
@@ -0,0 +1,52 @@
+package autocomplete
+
+import "testing"
+
+func TestWindowedFrequencyDecays(t *testing.T) {
+	trie := NewTriesA2()
+	trie.EnableWindow(10)
+
+	for i := 0; i < 50; i++ {
+		trie.Insert("hello")
+	}
+	if got := trie.WindowedFrequency("hello"); got != 10 {
+		t.Errorf("Expected the window to cap 'hello' at 10, got %d", got)
+	}
+
+	for i := 0; i < 8; i++ {
+		trie.Insert("help")
+	}
+
+	if got := trie.WindowedFrequency("hello"); got != 2 {
+		t.Errorf("Expected 'hello' to decay to 2 within the window, got %d", got)
+	}
+	if got := trie.WindowedFrequency("help"); got != 8 {
+		t.Errorf("Expected 'help' to have 8 recent occurrences, got %d", got)
+	}
+	if trie.Frequency("hello") != 50 {
+		t.Errorf("All-time frequency must be unaffected by the window")
+	}
+
+	if got := trie.AutocompleteTopK("hel", 1); got[0] != "help" {
+		t.Errorf("Expected recently active 'help' to rank first, got %v", got)
+	}
+
+	trie.EnableWindow(0)
+	if got := trie.AutocompleteTopK("hel", 1); got[0] != "hello" {
+		t.Errorf("Expected all-time ranking after disabling the window, got %v", got)
+	}
+}
+
+func TestWindowWithWeightedInsert(t *testing.T) {
+	trie := NewTriesA2()
+	trie.EnableWindow(10)
+
+	trie.InsertWithWeight("hello", 1000)
+	trie.InsertWithWeight("help", 4)
+	if got := trie.WindowedFrequency("hello"); got != 6 {
+		t.Errorf("Expected 'hello' to keep 6 of the 10 window slots, got %d", got)
+	}
+	if got := trie.WindowedFrequency("help"); got != 4 {
+		t.Errorf("Expected 'help' to fill 4 window slots, got %d", got)
+	}
+}